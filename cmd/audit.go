@@ -0,0 +1,60 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+var auditLimit int
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find existing data which violates non-native constraints",
+	Long: `A collection of commands which search a neo4j database for nodes and
+relationships violating the constraints configured for it, including those
+which neo4j itself cannot enforce.`,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	addConnectionFlags(auditCmd, "Audit records of this database")
+	auditCmd.PersistentFlags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the database")
+	auditCmd.PersistentFlags().IntVarP(&auditLimit, "limit", "l", 100, "maximum number of violations reported per constraint")
+}
+
+// printAuditReport lists the violations found and returns an error if there were any
+func printAuditReport(violations []geno.Violation) error {
+	for _, v := range violations {
+		fmt.Println("\t" + v.Error())
+	}
+	fmt.Println("audit report:", len(violations), "violation(s) found")
+	if len(violations) > 0 {
+		return fmt.Errorf("%d violation(s) were found", len(violations))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+// auditCardinalityCmd represents the audit cardinality command
+var auditCardinalityCmd = &cobra.Command{
+	Use:   "cardinality",
+	Short: "find nodes with too few or too many relationships of a type",
+	Long: `Check every configured cardinality constraint against the database,
+reporting nodes whose number of relationships of the constrained type and
+direction falls outside the allowed range. Cardinality constraints are
+configured per database alongside the other constraints, where a constraint
+without a Max leaves the degree unbounded and a Max of 0 allows none:
+
+constraints:
+    geno:
+        Cardinality:
+            - Label: Customer
+              Type: HAS_TAXCODE
+              Max: 1
+              OnViolation: replace
+            - Label: Vendor
+              Type: HAS_ADDRESS
+              Min: 1
+              Max: 1
+            - Label: Bank
+              Type: HAS_BANK
+              Direction: INCOMING
+              Min: 1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		constraints, err = loadConstraints(&driver, refreshConstraints)
+		if err != nil {
+			return err
+		}
+
+		var violations []geno.Violation
+		for _, c := range constraints.Cardinality {
+			keys := constraints.GetNodeConstraints(&geno.Node{Labels: []string{c.Label}})
+			found, err := driver.AuditCardinality(cfg.Database, c, keys, auditLimit)
+			if err != nil {
+				return err
+			}
+			fmt.Println(c, "-", len(found), "violation(s)")
+			violations = append(violations, found...)
+		}
+		return printAuditReport(violations)
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditCardinalityCmd)
+}
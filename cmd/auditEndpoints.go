@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+// auditEndpointsCmd represents the audit endpoints command
+var auditEndpointsCmd = &cobra.Command{
+	Use:   "endpoints",
+	Short: "find relationships between labels which are not allowed",
+	Long: `Check every relationship type with configured endpoint constraints,
+reporting relationships whose start and end labels conform to none of them.
+Endpoint constraints are configured per database alongside the other
+constraints:
+
+constraints:
+    geno:
+        RelationshipEndpoints:
+            - Type: HAS_ADDRESS
+              Start: [Customer, Vendor]
+              End: [Address]
+            - Type: HAS_BANK
+              Start: [Vendor]
+              End: [Bank]`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		constraints, err = loadConstraints(&driver, refreshConstraints)
+		if err != nil {
+			return err
+		}
+
+		var (
+			violations []geno.Violation
+			audited    map[string]bool = make(map[string]bool)
+		)
+		for _, e := range constraints.RelationshipEndpoints {
+			if audited[e.Type] {
+				continue
+			}
+			audited[e.Type] = true
+			found, err := driver.AuditEndpoints(cfg.Database, e.Type, constraints.GetEndpointConstraints(e.Type), auditLimit)
+			if err != nil {
+				return err
+			}
+			fmt.Println(e.Type, "-", len(found), "violation(s)")
+			violations = append(violations, found...)
+		}
+		return printAuditReport(violations)
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditEndpointsCmd)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+var auditPageSize int
+
+// auditValuesCmd represents the audit values command
+var auditValuesCmd = &cobra.Command{
+	Use:   "values",
+	Short: "find property values which break the configured value rules",
+	Long: `Check every configured property value rule against the database, using
+the same checks applied by import and validate. Nodes (or relationships) are
+read in pages ordered by id, so labels of any size may be audited. Value rules
+are configured per database alongside the other constraints:
+
+constraints:
+    geno:
+        PropertyRules:
+            - Label: Customer
+              Property: KUNNR
+              Regex: '[0-9]{10}'
+            - Label: Customer
+              Property: Database
+              Enum: [Hybrid_Germany, Hybrid_CH_CH4_100, Hybrid_CH_CH4_300]
+            - Label: Customer
+              Property: NAME1
+              NonEmpty: true
+              MaxLength: 35
+            - Label: HAS_BANK
+              EntityType: RELATIONSHIP
+              Property: ValidFrom
+              After: 1990-01-01`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if auditPageSize < 1 {
+			return errors.New("the page size must be at least 1")
+		}
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		constraints, err = loadConstraints(&driver, refreshConstraints)
+		if err != nil {
+			return err
+		}
+
+		var violations []geno.Violation
+		for _, rule := range constraints.PropertyRules {
+			var keys []string
+			if rule.EntityType == geno.IS_RELATIONSHIP {
+				keys = constraints.GetRelationshipConstraints(&geno.Relationship{Label: rule.Label})
+			} else {
+				keys = constraints.GetNodeConstraints(&geno.Node{Labels: []string{rule.Label}})
+			}
+			found, err := driver.AuditPropertyRule(cfg.Database, rule, keys, auditLimit, auditPageSize)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s.%s - %d violation(s)\n", rule.Label, rule.Property, len(found))
+			violations = append(violations, found...)
+		}
+		return printAuditReport(violations)
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditValuesCmd)
+
+	auditValuesCmd.Flags().IntVar(&auditPageSize, "page-size", 10000, "number of records read from the database at a time")
+}
@@ -0,0 +1,189 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertFrom   string
+	convertTo     string
+	convertLabels []string
+	convertTypes  []string
+)
+
+// graphWriters are the formats a graph can be written to without a database, by name.
+// Formats needing options read them from the same flags as their export commands.
+var graphWriters map[string]func(io.Writer, pkg.Graph) error = map[string]func(io.Writer, pkg.Graph) error{
+	"json":      pkg.WriteJson,
+	"yaml":      pkg.WriteYaml,
+	"apoc-json": pkg.WriteApocJson,
+	"graphml": func(w io.Writer, g pkg.Graph) error {
+		return pkg.WriteGraphML(w, g, graphmlOptions)
+	},
+	"cypher": func(w io.Writer, g pkg.Graph) error {
+		c := cfg.Constraints[cfg.Database]
+		return pkg.WriteCypher(w, g, &c, cfg.DatabaseTimestamps(), nil, cypherBatchSize)
+	},
+	"gexf": func(w io.Writer, g pkg.Graph) error {
+		return pkg.WriteGexf(w, g, exportCaptions)
+	},
+	"dot": func(w io.Writer, g pkg.Graph) error {
+		return pkg.WriteDot(w, g, pkg.DotOptions{Captions: exportCaptions, Styles: dotStyles})
+	},
+	"mermaid": func(w io.Writer, g pkg.Graph) error {
+		return pkg.WriteMermaid(w, g, exportCaptions, mermaidDirection)
+	},
+}
+
+// formatExtensions infer a format from the extension of a file when it is not given
+var formatExtensions map[string]string = map[string]string{
+	".json":    "json",
+	".yaml":    "yaml",
+	".yml":     "yaml",
+	".graphml": "graphml",
+	".jsonl":   "apoc-json",
+	".csv":     "csv",
+	".tsv":     "csv",
+	".xlsx":    "xlsx",
+	".ttl":     "rdf",
+	".nt":      "rdf",
+	".cypher":  "cypher",
+	".cql":     "cypher",
+	".gexf":    "gexf",
+	".dot":     "dot",
+	".gv":      "dot",
+	".mmd":     "mermaid",
+}
+
+// formatOf returns the format given, or the one inferred from a path's extension
+func formatOf(given, path, flag string) (string, error) {
+	if given != "" {
+		return given, nil
+	}
+	if format, found := formatExtensions[strings.ToLower(filepath.Ext(path))]; found {
+		return format, nil
+	}
+	return "", fmt.Errorf("the format of %s cannot be inferred from its extension and must be given with --%s", path, flag)
+}
+
+func formatNames[T any](formats map[string]T) string {
+	var names []string = make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert a file of nodes and relationships from one format to another",
+	Long: `Convert a file from one format to another without connecting to a database,
+e.g. geno convert -f customers.csv --mapping customers.yaml --to json -o customers.json
+
+Formats are inferred from the extensions of the input (--filepath) and output
+(--out) files unless given with --from and --to. The input may be several files,
+given as a directory or glob, or - for stdin, and may be compressed or archived
+with tar, as for geno import. Formats needing options take
+them from the same flags as their import and export commands, such as
+--mapping for csv, xlsx and rdf, or --caption for gexf, dot and mermaid. Cypher
+scripts are written with the constraints configured for the database given by
+--database.
+
+Only nodes with any of the labels given by --labels and relationships of any
+of the types given by --types are converted; relationships are dropped when
+either of their nodes is.`,
+	// conversion happens offline, so no server or credentials are needed
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputs, err := readInputs()
+		if err != nil {
+			return err
+		}
+		from, err := formatOf(convertFrom, inputs[0].Name, "from")
+		if err != nil {
+			return err
+		}
+		to := convertTo
+		if to == "" && exportPath == "" {
+			return errors.New("the format to write must be given with --to when writing to stdout")
+		}
+		if to, err = formatOf(to, exportPath, "to"); err != nil {
+			return err
+		}
+		read, found := graphReaders[from]
+		if !found {
+			return fmt.Errorf("%s cannot be read; choose from %s", from, formatNames(graphReaders))
+		}
+		write, found := graphWriters[to]
+		if !found {
+			return fmt.Errorf("%s cannot be written; choose from %s", to, formatNames(graphWriters))
+		}
+
+		graph, err := read(inputs)
+		if err != nil {
+			return err
+		}
+		before := len(graph.Nodes) + len(graph.Relationships)
+		graph = pkg.FilterGraph(graph, convertLabels, convertTypes)
+		if err := writeGraph(graph, write); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "conversion report:", len(graph.Nodes), "nodes and", len(graph.Relationships), "relationships converted,",
+			before-len(graph.Nodes)-len(graph.Relationships), "filtered out")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("files to convert"))
+	convertCmd.Flags().StringVarP(&exportPath, "out", "o", "", "path of the file to write (default is stdout)")
+	convertCmd.Flags().StringVar(&convertFrom, "from", "", "format to read: "+formatNames(graphReaders)+" (default inferred from the extension)")
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "format to write: "+formatNames(graphWriters)+" (default inferred from the extension)")
+	convertCmd.Flags().StringSliceVar(&convertLabels, "labels", nil, "only convert nodes with any of these labels")
+	convertCmd.Flags().StringSliceVar(&convertTypes, "types", nil, "only convert relationships of any of these types")
+
+	convertCmd.Flags().StringVarP(&mappingPath, "mapping", "m", "", "path to the yaml mapping file of a csv, xlsx or rdf input")
+	convertCmd.Flags().StringVar(&graphmlOptions.LabelAttribute, "label-attribute", graphmlOptions.LabelAttribute, "graphml node attribute holding labels")
+	convertCmd.Flags().StringVar(&graphmlOptions.TypeAttribute, "type-attribute", graphmlOptions.TypeAttribute, "graphml edge attribute holding relationship types")
+	convertCmd.Flags().StringVar(&graphmlOptions.DefaultLabel, "default-label", "", "label of graphml nodes without any (default is to fail on them)")
+	convertCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+	convertCmd.Flags().StringToStringVar(&dotStyles, "style", nil, "graphviz attributes for the nodes of each label, e.g. Customer=shape=ellipse")
+	convertCmd.Flags().StringVar(&mermaidDirection, "direction", "LR", "direction of a mermaid flowchart: TB, TD, BT, LR or RL")
+	convertCmd.Flags().IntVarP(&cypherBatchSize, "batch-size", "b", 1000, "number of nodes or relationships merged by each statement of a cypher script")
+	convertCmd.Flags().StringVarP(&cfg.Database, "database", "d", cfg.Database, "write cypher scripts with the constraints configured for this database")
+}
@@ -0,0 +1,265 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyToDb        string
+	copyFromProfile string
+	copyToProfile   string
+	copyQuery       string
+)
+
+// copyCmd represents the copy command
+var copyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy a subgraph from one database into another",
+	Long: `Copy the nodes and relationships returned by a query from one database into
+another without writing an intermediate file, e.g.
+geno copy --from-db staging --to-db prod -q "MATCH (c:Customer)-[r]->(v) RETURN c, r, v"
+
+The query (--query) defaults to the whole database. Relationships are copied
+along with the nodes at either end of them, as by geno export. The query's
+records are streamed --batch-size at a time, and each page is merged before the
+next is read, so only the ids of the elements copied so far, and any invalid
+records, are held in memory.
+
+Both databases are on the configured server unless --from-profile or
+--to-profile names a server profile of the configuration file, e.g.
+profiles:
+    prod:
+        server: prod.example.com:7687
+        user: loader
+The password of each server is asked for when it is first connected to.
+
+The subgraph is merged into the target database as geno import merges a file:
+it is validated against the constraints configured for the target database (or
+refreshed from it with -r), merged --batch-size at a time, and reported.
+Invalid records are handled according to --on-invalid, and --dry-run reports
+what would be merged without writing anything. As pages are merged as they are
+read, --on-invalid fail stops the copy at the first page holding an invalid
+record, leaving the pages before it merged; run with --dry-run first to find
+invalid records without writing anything.`,
+	// connections are validated once it is known which servers are copied between
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if copyToDb == "" {
+			return errors.New("the database to copy into (--to-db) must be provided")
+		}
+		if strings.EqualFold(copyFromProfile, copyToProfile) && strings.EqualFold(copyToDb, cfg.Database) {
+			return errors.New("a database cannot be copied into itself")
+		}
+		if err := checkOnInvalid(); err != nil {
+			return err
+		}
+
+		source, err := copyConfiguration(copyFromProfile, cfg.Database)
+		if err != nil {
+			return err
+		}
+		if err := source.ValidateWithAttempts(); err != nil {
+			return err
+		}
+		target, err := copyConfiguration(copyToProfile, copyToDb)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(copyToProfile, copyFromProfile) {
+			target.SetPassword(source.GetPassword()) // the same server, so its password is only asked for once
+		}
+		if err := target.ValidateWithAttempts(); err != nil {
+			return err
+		}
+
+		sourceDriver, err := newDriverFor(&source)
+		if err != nil {
+			return err
+		}
+		defer sourceDriver.Close()
+
+		cfg = target // pages are merged into the configured server and database, as by geno import
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+		constraints, err = loadConstraints(&driver, refreshConstraints)
+		if err != nil {
+			return err
+		}
+		query = geno.NewQuery(&driver, &constraints)
+
+		var (
+			invalid    pkg.Graph
+			violations []geno.Violation
+			copied     copyProgress = newCopyProgress()
+		)
+		err = pkg.StreamGraphFromDb(&sourceDriver, source.Database, copyQuery, nil, importBatchSize, func(page pkg.Graph) error {
+			pageValid, pageInvalid, found := copied.partition(page)
+			if len(found) > 0 && onInvalid == onInvalidFail {
+				for _, v := range found {
+					fmt.Fprintln(os.Stderr, "\t"+v.Error())
+				}
+				return fmt.Errorf("%d constraint violation(s) were found; the %d nodes and %d relationships read before them were copied",
+					len(found), copied.validNodes, copied.validRelationships)
+			}
+			invalid.Nodes = append(invalid.Nodes, pageInvalid.Nodes...)
+			invalid.Relationships = append(invalid.Relationships, pageInvalid.Relationships...)
+			violations = append(violations, found...)
+			copied.warnUnindexedMerges(&driver, pageValid)
+			copied.validNodes += len(pageValid.Nodes)
+			copied.validRelationships += len(pageValid.Relationships)
+			if importDryRun {
+				return nil
+			}
+
+			if err := mergeNodes(&query, cfg.Database, pageValid.Nodes, geno.MERGE_KEEP_SURVIVOR, importBatchSize, "nodes"); err != nil {
+				return err
+			}
+			rejected, found, err := mergeRelationships(&query, cfg.Database, pageValid.Relationships, geno.MERGE_KEEP_SURVIVOR, importBatchSize, "rels ")
+			if err != nil {
+				return err
+			}
+			invalid.Relationships = append(invalid.Relationships, rejected...)
+			violations = append(violations, found...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "copy report:", copied.nodes, "nodes and", copied.relationships, "relationships read from", source.Database)
+
+		if importDryRun {
+			reportDryRun(cfg.Database, copied.validNodes, copied.validRelationships, invalid, violations)
+			return nil
+		}
+		return reportImport(invalid, violations)
+	},
+}
+
+// copyProgress remembers the ids of the elements a copy has read, so that elements returned on several pages are
+// merged, reported and counted once
+type copyProgress struct {
+	nodeIds, relationshipIds       map[int64]bool
+	rejected                       map[int64]bool  // nodes which violate a constraint
+	shapes                         map[string]bool // labels of the nodes already checked for indexes
+	nodes, relationships           int             // elements read
+	validNodes, validRelationships int             // elements read which satisfy the constraints
+}
+
+func newCopyProgress() copyProgress {
+	return copyProgress{nodeIds: make(map[int64]bool), relationshipIds: make(map[int64]bool), rejected: make(map[int64]bool), shapes: make(map[string]bool)}
+}
+
+// partition separates the nodes and relationships of a page which no earlier page held, as partitionGraph does.
+// Relationships to a node rejected on an earlier page are invalid as well.
+func (p *copyProgress) partition(page pkg.Graph) (valid, invalid pkg.Graph, violations []geno.Violation) {
+	valid, invalid, violations = partitionGraph(p.unseen(page), &constraints)
+	for _, n := range invalid.Nodes {
+		p.rejected[n.Id] = true
+	}
+	var accepted []geno.Relationship
+	for _, r := range valid.Relationships {
+		if p.rejected[r.Start.Id] || p.rejected[r.End.Id] {
+			invalid.Relationships = append(invalid.Relationships, r)
+			continue
+		}
+		accepted = append(accepted, r)
+	}
+	valid.Relationships = accepted
+	return valid, invalid, violations
+}
+
+// unseen returns the nodes and relationships of a page which no earlier page held
+func (p *copyProgress) unseen(g pkg.Graph) pkg.Graph {
+	var fresh pkg.Graph
+	for _, n := range g.Nodes {
+		if !p.nodeIds[n.Id] {
+			p.nodeIds[n.Id] = true
+			fresh.Nodes = append(fresh.Nodes, n)
+		}
+	}
+	for _, r := range g.Relationships {
+		if !p.relationshipIds[r.Id] {
+			p.relationshipIds[r.Id] = true
+			fresh.Relationships = append(fresh.Relationships, r)
+		}
+	}
+	p.nodes += len(fresh.Nodes)
+	p.relationships += len(fresh.Relationships)
+	return fresh
+}
+
+// warnUnindexedMerges warns of unindexed merges, as geno import does, for the labels no earlier page held
+func (p *copyProgress) warnUnindexedMerges(driver *geno.Driver, g pkg.Graph) {
+	var fresh pkg.Graph
+	for _, n := range g.Nodes {
+		if !p.shapes[n.String()] {
+			p.shapes[n.String()] = true
+			fresh.Nodes = append(fresh.Nodes, n)
+		}
+	}
+	if len(fresh.Nodes) > 0 {
+		warnUnindexedMerges(driver, cfg.Database, fresh, &constraints)
+	}
+}
+
+// copyConfiguration returns the configuration connecting to one side of a copy: the configured server,
+// or the server of the named profile, with the database given
+func copyConfiguration(profile, database string) (pkg.Configuration, error) {
+	var c pkg.Configuration = cfg
+	if profile != "" {
+		var err error
+		if c, err = cfg.ProfileConfiguration(profile, database); err != nil {
+			return c, err
+		}
+	}
+	c.Database = database
+	return c, nil
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	addConnectionFlags(copyCmd, "Copy from this database")
+	copyCmd.Flags().StringVar(&cfg.Database, "from-db", cfg.Database, "Copy from this database (the same as --database)")
+	copyCmd.Flags().StringVar(&copyToDb, "to-db", "", "Copy into this database")
+	copyCmd.Flags().StringVar(&copyFromProfile, "from-profile", "", "server profile to copy from (default is the configured server)")
+	copyCmd.Flags().StringVar(&copyToProfile, "to-profile", "", "server profile to copy into (default is the configured server)")
+	copyCmd.Flags().StringVarP(&copyQuery, "query", "q", defaultExportQuery, "Read query returning the nodes, relationships and paths to copy")
+
+	copyCmd.Flags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the target database")
+	copyCmd.Flags().StringVar(&onInvalid, "on-invalid", onInvalidFail, "handling of records violating constraints: fail, skip or dead-letter")
+	copyCmd.Flags().StringVar(&deadLetterPath, "dead-letter", "", "json file receiving invalid records when --on-invalid is dead-letter")
+	copyCmd.Flags().IntVarP(&importBatchSize, "batch-size", "b", 1000, "number of nodes or relationships merged by each statement")
+	copyCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "validate and report what would be merged without writing anything")
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupeLabel     string
+	dedupeKeys      []string
+	dedupeSurvivor  string
+	dedupeConflict  string
+	dedupeBatchSize int
+	dedupeDryRun    bool
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Consolidate duplicate nodes of a label into a single node",
+	Long: `Find nodes of a label which share values for every key property and
+consolidate each group into a single surviving node without the use of APOC.
+
+Key properties default to the configured uniqueness, node key and property
+existence constraints of the label and may be overridden with --keys.
+
+For every group of duplicates:
+- a survivor is chosen (lowest-id, most-relationships or most-properties), with
+  ties going to the lowest internal id
+- all incoming and outgoing relationships are recreated on the survivor
+- properties are merged (keep: survivor wins, overwrite: losers win,
+  fail: conflicting groups are skipped and reported)
+- the remaining duplicates are deleted
+
+Each group is consolidated in its own transaction and groups are processed in
+batches, so an interrupted run may simply be started again. Use --dry-run to
+preview the groups and their survivors without writing anything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dedupeLabel == "" {
+			return errors.New("label cannot be empty")
+		}
+		if dedupeBatchSize < 1 {
+			return errors.New("batch size must be at least 1")
+		}
+		var (
+			rule    geno.SurvivorRule = geno.SurvivorRule(dedupeSurvivor)
+			policy  geno.MergePolicy  = geno.MergePolicy(dedupeConflict)
+			total   geno.DedupeSummary
+			skipped int
+		)
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		keys := dedupeKeys
+		if len(keys) == 0 {
+			constraints, err = loadConstraints(&driver, refreshConstraints)
+			if err != nil {
+				return err
+			}
+			keys = constraints.GetNodeConstraints(&geno.Node{Labels: []string{dedupeLabel}})
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no constraints are configured for %s; provide key properties with --keys", dedupeLabel)
+		}
+
+		for {
+			groups, err := driver.FindDuplicates(cfg.Database, dedupeLabel, keys, skipped, dedupeBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				break
+			}
+
+			for _, group := range groups {
+				survivor, losers, err := group.Survivor(rule)
+				if err != nil {
+					return err
+				}
+				if dedupeDryRun {
+					skipped++
+					_, err = geno.MergeProperties(survivor, losers, policy)
+					if geno.IsMergeConflict(err) {
+						fmt.Println(group, "would be skipped:", err)
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					fmt.Println(group, "keeps node", survivor.Id, "and merges", len(losers), "duplicate(s)")
+					total.Groups++
+					for _, loser := range losers {
+						total.NodesDeleted++
+						total.RelationshipsMoved += int(loser.Degree)
+					}
+					continue
+				}
+
+				summary, err := driver.ConsolidateDuplicates(cfg.Database, group, rule, policy)
+				if geno.IsMergeConflict(err) {
+					skipped++
+					fmt.Println(group, "skipped:", err)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				total.Groups += summary.Groups
+				total.NodesDeleted += summary.NodesDeleted
+				total.RelationshipsMoved += summary.RelationshipsMoved
+				total.RelationshipsDropped += summary.RelationshipsDropped
+			}
+		}
+
+		if dedupeDryRun {
+			fmt.Println("dry run:", total.Groups, "group(s) would be consolidated, deleting", total.NodesDeleted, "node(s) and moving up to", total.RelationshipsMoved, "relationship(s)")
+		} else {
+			fmt.Println("dedupe report:", total.Groups, "group(s) consolidated")
+			fmt.Println("\tnodes deleted:", total.NodesDeleted)
+			fmt.Println("\trelationships moved:", total.RelationshipsMoved, " dropped:", total.RelationshipsDropped)
+		}
+		if skipped > 0 && !dedupeDryRun {
+			fmt.Println("\tgroups skipped because of conflicts:", skipped)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+
+	addConnectionFlags(dedupeCmd, "Consolidate duplicates within this database")
+	dedupeCmd.Flags().StringVarP(&dedupeLabel, "label", "l", "", "label of the nodes to consolidate")
+	dedupeCmd.Flags().StringSliceVarP(&dedupeKeys, "keys", "k", nil, "properties which identify duplicates (defaults to the label's constraints)")
+	dedupeCmd.Flags().StringVar(&dedupeSurvivor, "survivor", string(geno.SURVIVOR_LOWEST_ID), "rule used to pick the surviving node: lowest-id, most-relationships or most-properties")
+	dedupeCmd.Flags().StringVar(&dedupeConflict, "conflict", string(geno.MERGE_KEEP_SURVIVOR), "policy for conflicting property values: keep, overwrite or fail")
+	dedupeCmd.Flags().IntVarP(&dedupeBatchSize, "batch-size", "b", 100, "number of duplicate groups fetched per batch")
+	dedupeCmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "report the groups which would be consolidated without writing anything")
+	dedupeCmd.Flags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the database")
+}
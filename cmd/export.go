@@ -0,0 +1,113 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+const defaultExportQuery string = "MATCH (n) OPTIONAL MATCH (n)-[r]->(m) RETURN n, r, m"
+
+var (
+	exportQuery string
+	exportPath  string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Extract data from a neo4j database",
+	Long: `A collection of commands which extract data (nodes and relationships)
+from a neo4j database into a variety of filetypes.
+
+The subgraph exported is every node, relationship and path returned by the
+query (--query), which defaults to the whole database. Relationships are always
+exported along with the nodes at either end of them.
+
+Filetypes currently included are:
+- yaml (command yaml)
+- graphml (command graphml)
+- apoc json lines (command apoc-json)
+- cypher scripts (command cypher)
+- gexf for Gephi (command gexf)
+- graphviz dot (command dot)
+- mermaid flowcharts (command mermaid)
+- a backup of the whole database, restored by geno restore (command all)
+- the neighborhood of seed nodes, in any of the formats above (command neighborhood)
+- the nodes of a label or relationships of a type, a page at a time (command stream)
+- the changes made since a time, in any of the formats above (command changes)
+- a flat csv or xlsx table of the nodes of a label and their related values (command table)`,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	addConnectionFlags(exportCmd, "Export from this database")
+	exportCmd.PersistentFlags().StringVarP(&exportQuery, "query", "q", defaultExportQuery, "Read query returning the nodes, relationships and paths to export")
+	exportCmd.PersistentFlags().StringVarP(&exportPath, "out", "o", "", "path of the file to write (default is stdout)")
+}
+
+// exportGraph runs the export query and writes its subgraph with the given writer to the output file or stdout
+func exportGraph(write func(io.Writer, pkg.Graph) error) error {
+	driver, err := newDriver()
+	if err != nil {
+		return err
+	}
+	defer driver.Close()
+	return exportGraphFrom(&driver, write)
+}
+
+// exportGraphFrom exports as exportGraph does, over a connection the command has already opened
+func exportGraphFrom(driver *geno.Driver, write func(io.Writer, pkg.Graph) error) error {
+	graph, err := pkg.GetGraphFromDb(driver, cfg.Database, exportQuery, nil)
+	if err != nil {
+		return err
+	}
+	if err := writeGraph(graph, write); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "export report:", len(graph.Nodes), "nodes and", len(graph.Relationships), "relationships exported")
+	return nil
+}
+
+// writeGraph writes a graph with the given writer to the output file or stdout
+func writeGraph(graph pkg.Graph, write func(io.Writer, pkg.Graph) error) error {
+	if exportPath == "" {
+		return write(os.Stdout, graph)
+	}
+	f, err := os.Create(exportPath)
+	if err != nil {
+		return err
+	}
+	if err := write(f, graph); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var backupPageSize int
+
+// exportAllCmd represents the export all command
+var exportAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "export the whole database to a directory, one file per label and relationship type",
+	Long: `Export every node and relationship of the database to the directory given by
+--out, as a logical backup which geno restore can load again. It works on any
+edition of neo4j, including Community Edition where online dumps are not
+available.
+
+Nodes are written to nodes/<label>.jsonl, under the first of their labels in
+order, and relationships to relationships/<type>.jsonl, as apoc json lines.
+Nodes without labels cannot be found again on restore, so neither they nor
+their relationships are written; the export report counts them.
+Elements are read --page-size at a time in order of their internal ids, so
+memory use does not grow with the database. Once every file is written,
+backup.json records the statements recreating the database's constraints, and
+the number of elements and sha256 checksum of every file.
+
+The export query (--query) is not used. As the database is read a page at a
+time, it should not be written to while it is exported. Temporal values are
+written as text, as by the other json exports.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportPath == "" {
+			return errors.New("a directory to write the backup to (--out) must be provided")
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		b, err := pkg.WriteBackup(&driver, cfg.Database, exportPath, backupPageSize, func(f pkg.BackupFile) {
+			fmt.Fprintln(os.Stderr, "\t"+f.Path+":", f.Count, f.Kind+"s")
+		})
+		if err != nil {
+			return err
+		}
+
+		if b.UnlabelledNodes > 0 {
+			fmt.Fprintln(os.Stderr, "warning:", b.UnlabelledNodes, "nodes without labels and", b.UnlabelledRelationships, "of their relationships were not exported")
+		}
+		fmt.Fprintln(os.Stderr, "export report:", b.Nodes, "nodes and", b.Relationships, "relationships exported to", len(b.Files), "files,", len(b.Constraints), "constraints recorded")
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportAllCmd)
+
+	exportAllCmd.Flags().IntVar(&backupPageSize, "page-size", 10000, "number of nodes or relationships read by each query")
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+// exportApocJsonCmd represents the export apoc-json command
+var exportApocJsonCmd = &cobra.Command{
+	Use:   "apoc-json",
+	Short: "export nodes and relationships in the format of apoc.export.json",
+	Long: `Export the subgraph returned by the export query as line delimited json in
+the format written by apoc.export.json.all, one node or relationship per line.
+Files written by this command can be loaded with apoc.import.json as well as
+geno import apoc-json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGraph(pkg.WriteApocJson)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportApocJsonCmd)
+}
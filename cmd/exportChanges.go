@@ -0,0 +1,151 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changesSince  string
+	changesFormat string
+)
+
+// exportChangesCmd represents the export changes command
+var exportChangesCmd = &cobra.Command{
+	Use:   "changes",
+	Short: "export the nodes and relationships written or deleted since a time",
+	Long: `Export what has changed in the database since a time, e.g.
+geno export changes --since 2026-10-17T00:00:00Z -o changes.json
+
+Changes are found through the timestamp properties configured for labels and
+relationship types under timestamps in the configuration file, by database:
+
+timestamps:
+  neo4j:
+    - Label: Customer
+      Property: UPDATED_AT
+
+geno imports set them to the time each element is created or its values
+changed; writes made outside of geno must maintain them too.
+
+Every node of a configured label with a timestamp at or after --since is
+exported along with all of its relationships and the nodes at their other
+ends, so relationships whose endpoints changed are included, as is every
+relationship of a configured type written since then.
+
+Nodes and relationships deleted by geno, such as the duplicates removed by
+geno dedupe or the relationships replaced by cardinality constraints, leave a
+GenoTombstone node recording the element deleted and when. Tombstones of
+deletions since --since are exported as nodes alongside the changes.
+
+The changes are written in any of the formats geno convert writes, given by
+--format or inferred from the extension of --out, and json by default. The
+export query (--query) is not used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if changesSince == "" {
+			return errors.New("the time to export changes since (--since) must be provided")
+		}
+		since, err := parseSince(changesSince)
+		if err != nil {
+			return err
+		}
+		if len(cfg.DatabaseTimestamps()) == 0 {
+			return fmt.Errorf("no timestamp properties are configured for %s, so changes cannot be found", cfg.Database)
+		}
+		format := changesFormat
+		if format == "" && exportPath != "" {
+			if format, err = formatOf("", exportPath, "format"); err != nil {
+				return err
+			}
+		} else if format == "" {
+			format = "json"
+		}
+		write, found := graphWriters[format]
+		if !found {
+			return fmt.Errorf("%s cannot be written; choose from %s", format, formatNames(graphWriters))
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		// a configured name may be a label, a relationship type or both
+		labels, err := driver.GetLabels(cfg.Database)
+		if err != nil {
+			return err
+		}
+		types, err := driver.GetRelationshipTypes(cfg.Database)
+		if err != nil {
+			return err
+		}
+		var (
+			labelTimestamps map[string]string = make(map[string]string)
+			typeTimestamps  map[string]string = make(map[string]string)
+		)
+		for _, label := range labels {
+			if property := cfg.TimestampProperty(label); property != "" && label != geno.TombstoneLabel {
+				labelTimestamps[label] = property
+			}
+		}
+		for _, relType := range types {
+			if property := cfg.TimestampProperty(relType); property != "" {
+				typeTimestamps[relType] = property
+			}
+		}
+
+		graph, err := pkg.GetGraphFromDb(&driver, cfg.Database, geno.ChangesQuery(labelTimestamps, typeTimestamps), map[string]any{"since": since})
+		if err != nil {
+			return err
+		}
+		if err := writeGraph(graph, write); err != nil {
+			return err
+		}
+
+		var tombstones int
+		for _, n := range graph.Nodes {
+			for _, label := range n.Labels {
+				if label == geno.TombstoneLabel {
+					tombstones++
+				}
+			}
+		}
+		fmt.Fprintln(os.Stderr, "export report:", len(graph.Nodes)-tombstones, "nodes,", len(graph.Relationships), "relationships and", tombstones, "tombstones exported")
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportChangesCmd)
+
+	exportChangesCmd.Flags().StringVar(&changesSince, "since", "", "export changes made at or after this time, as 2006-01-02 or 2006-01-02T15:04:05Z07:00")
+	exportChangesCmd.Flags().StringVar(&changesFormat, "format", "", "format to write: "+formatNames(graphWriters)+" (default inferred from --out, or json)")
+	exportChangesCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cypherBatchSize         int
+	cypherRefreshConstraint bool
+)
+
+// exportCypherCmd represents the export cypher command
+var exportCypherCmd = &cobra.Command{
+	Use:   "cypher",
+	Short: "export nodes and relationships as a cypher-shell script",
+	Long: `Export the subgraph returned by the export query as a self-contained cypher
+script, which can be run by anyone with cypher-shell access:
+
+cypher-shell -d <DATABASE> -f export.cypher
+
+The script creates the configured constraints (or those of the database, with
+--refresh-constraints), then merges nodes and relationships in batches with
+UNWIND, passing each batch as a :param block. Merges are built exactly as geno
+import builds them, so running the script is equivalent to an import with the
+same constraints which keeps existing values: elements are merged on their
+constraints, and those created are stamped with the configured timestamps.
+Constraints of types the server cannot hold, such as property type constraints
+before neo4j 5.9, are written as comments rather than statements.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		c, err := loadConstraints(&driver, cypherRefreshConstraint)
+		if err != nil {
+			return err
+		}
+		var version *geno.ServerVersion
+		if v, err := driver.GetServerVersion(); err == nil {
+			version = &v
+		} else {
+			fmt.Fprintln(os.Stderr, "warning: the server version could not be read, so every configured constraint is scripted:", err)
+		}
+		return exportGraphFrom(&driver, func(w io.Writer, g pkg.Graph) error {
+			return pkg.WriteCypher(w, g, &c, cfg.DatabaseTimestamps(), version, cypherBatchSize)
+		})
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportCypherCmd)
+
+	exportCypherCmd.Flags().IntVarP(&cypherBatchSize, "batch-size", "b", 1000, "number of nodes or relationships merged by each statement")
+	exportCypherCmd.Flags().BoolVarP(&cypherRefreshConstraint, "refresh-constraints", "r", false, "script the constraints read from the database rather than the configuration file")
+}
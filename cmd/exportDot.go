@@ -0,0 +1,58 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var dotStyles map[string]string
+
+// exportDotCmd represents the export dot command
+var exportDotCmd = &cobra.Command{
+	Use:   "dot",
+	Short: "export nodes and relationships to a Graphviz dot file",
+	Long: `Export the subgraph returned by the export query in Graphviz's DOT
+language, to be rendered with e.g. dot -Tsvg export.dot -o export.svg.
+
+Nodes are filled with a colour for their first label and captioned with the
+property given for that label by --caption (e.g. --caption Customer=NAME1), or
+otherwise their name or title. Further Graphviz attributes can be given for the
+nodes of a label by --style, separated by semicolons, e.g.
+--style "Customer=shape=ellipse; penwidth=2". Relationships are labelled with
+their type.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGraph(func(w io.Writer, g pkg.Graph) error {
+			return pkg.WriteDot(w, g, pkg.DotOptions{Captions: exportCaptions, Styles: dotStyles})
+		})
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportDotCmd)
+
+	exportDotCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+	exportDotCmd.Flags().StringToStringVar(&dotStyles, "style", nil, "graphviz attributes for the nodes of each label, e.g. Customer=shape=ellipse")
+}
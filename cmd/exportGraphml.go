@@ -0,0 +1,56 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var graphmlOptions pkg.GraphMLOptions = pkg.DefaultGraphMLOptions
+
+// exportGraphmlCmd represents the export graphml command
+var exportGraphmlCmd = &cobra.Command{
+	Use:   "graphml",
+	Short: "export nodes and relationships to a graphml file",
+	Long: `Export the subgraph returned by the export query as GraphML, readable by
+Gephi, yEd, NetworkX and apoc.import.graphml.
+
+Every property is declared as a typed key. The labels of each node are written
+to the labels attribute (e.g. :Customer:Vendor) and the type of each
+relationship to the label attribute, as APOC does, so both survive a round trip
+through other tools.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGraph(func(w io.Writer, g pkg.Graph) error {
+			return pkg.WriteGraphML(w, g, graphmlOptions)
+		})
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportGraphmlCmd)
+
+	exportGraphmlCmd.Flags().StringVar(&graphmlOptions.LabelAttribute, "label-attribute", graphmlOptions.LabelAttribute, "node attribute to write labels to")
+	exportGraphmlCmd.Flags().StringVar(&graphmlOptions.TypeAttribute, "type-attribute", graphmlOptions.TypeAttribute, "edge attribute to write relationship types to")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var mermaidDirection string
+
+// exportMermaidCmd represents the export mermaid command
+var exportMermaidCmd = &cobra.Command{
+	Use:   "mermaid",
+	Short: "export nodes and relationships as a Mermaid flowchart",
+	Long: `Export the subgraph returned by the export query as a Mermaid flowchart,
+which can be pasted into a markdown code block tagged mermaid to be rendered
+by GitHub, GitLab and most wikis.
+
+Nodes are coloured by their first label and captioned with the property given
+for that label by --caption (e.g. --caption Customer=NAME1), or otherwise their
+name or title. Relationships are labelled with their type. Mermaid is meant for
+small subgraphs; restrict the export with --query.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGraph(func(w io.Writer, g pkg.Graph) error {
+			return pkg.WriteMermaid(w, g, exportCaptions, mermaidDirection)
+		})
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportMermaidCmd)
+
+	exportMermaidCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+	exportMermaidCmd.Flags().StringVar(&mermaidDirection, "direction", "LR", "direction of the flowchart: TB, TD, BT, LR or RL")
+}
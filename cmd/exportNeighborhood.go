@@ -0,0 +1,141 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	neighborhoodLabel   string
+	neighborhoodKeys    []string
+	neighborhoodSeeds   string
+	neighborhoodFormat  string
+	neighborhoodOptions pkg.NeighborhoodOptions
+)
+
+// exportNeighborhoodCmd represents the export neighborhood command
+var exportNeighborhoodCmd = &cobra.Command{
+	Use:   "neighborhood",
+	Short: "export the nodes and relationships within a number of hops of seed nodes",
+	Long: `Export the neighborhood of one or more seed nodes, e.g.
+geno export neighborhood --label Customer --key Key=Hybrid_Germany0249697900 --hops 2
+
+Seeds are looked up by the constraint properties configured for their label,
+given as Key=value pairs separated by commas, or as a single value when the
+label has a single constraint property. Each --key is one seed; more may be
+listed in a file given by --seeds, one per line, where blank lines and lines
+starting with # are ignored.
+
+Relationships are followed in either direction for --hops hops, limited to the
+types given by --types and to nodes with any of the labels given by --labels.
+No more than --max-nodes nodes are read, seeds included: each hop reads only as
+many new nodes as remain of the limit, those with the lowest ids, so that the
+nodes beyond it are never read from the database.
+
+The neighborhood is written in any of the formats geno convert writes, given by
+--format or inferred from the extension of --out, and json by default. The
+export query (--query) is not used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if neighborhoodLabel == "" {
+			return errors.New("the label of the seeds (--label) must be provided")
+		}
+		if neighborhoodOptions.Hops < 0 {
+			return errors.New("hops cannot be negative")
+		}
+		format := neighborhoodFormat
+		if format == "" && exportPath != "" {
+			var err error
+			if format, err = formatOf("", exportPath, "format"); err != nil {
+				return err
+			}
+		} else if format == "" {
+			format = "json"
+		}
+		write, found := graphWriters[format]
+		if !found {
+			return fmt.Errorf("%s cannot be written; choose from %s", format, formatNames(graphWriters))
+		}
+
+		var seeds []string = append([]string(nil), neighborhoodKeys...)
+		if neighborhoodSeeds != "" {
+			raw, err := os.ReadFile(neighborhoodSeeds)
+			if err != nil {
+				return err
+			}
+			for _, line := range strings.Split(string(raw), "\n") {
+				if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+					seeds = append(seeds, line)
+				}
+			}
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		c, err := loadConstraints(&driver, false)
+		if err != nil {
+			return err
+		}
+		keys, err := pkg.ParseSeeds(neighborhoodLabel, &c, seeds)
+		if err != nil {
+			return err
+		}
+
+		graph, truncated, err := pkg.GetNeighborhood(&driver, cfg.Database, neighborhoodLabel, keys, neighborhoodOptions)
+		if err != nil {
+			return err
+		}
+		if err := writeGraph(graph, write); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "export report:", len(graph.Nodes), "nodes and", len(graph.Relationships), "relationships exported")
+		if truncated {
+			fmt.Fprintln(os.Stderr, "\tthe neighborhood holds more than", neighborhoodOptions.MaxNodes, "nodes and was cut short")
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportNeighborhoodCmd)
+
+	exportNeighborhoodCmd.Flags().StringVar(&neighborhoodLabel, "label", "", "label of the seed nodes")
+	exportNeighborhoodCmd.Flags().StringArrayVar(&neighborhoodKeys, "key", nil, "constraint properties of a seed, e.g. Key=Hybrid_Germany0249697900")
+	exportNeighborhoodCmd.Flags().StringVar(&neighborhoodSeeds, "seeds", "", "file listing the keys of seeds, one per line")
+	exportNeighborhoodCmd.Flags().IntVar(&neighborhoodOptions.Hops, "hops", 1, "number of relationships followed from the seeds")
+	exportNeighborhoodCmd.Flags().StringSliceVar(&neighborhoodOptions.Types, "types", nil, "only follow relationships of any of these types")
+	exportNeighborhoodCmd.Flags().StringSliceVar(&neighborhoodOptions.Labels, "labels", nil, "only reach nodes with any of these labels")
+	exportNeighborhoodCmd.Flags().IntVar(&neighborhoodOptions.MaxNodes, "max-nodes", 1000, "most nodes exported, or 0 for no limit")
+	exportNeighborhoodCmd.Flags().StringVar(&neighborhoodFormat, "format", "", "format to write: "+formatNames(graphWriters)+" (default inferred from --out, or json)")
+	exportNeighborhoodCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+}
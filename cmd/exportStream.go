@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	streamOptions    pkg.StreamOptions
+	streamSince      string
+	streamCheckpoint string
+)
+
+// exportStreamCmd represents the export stream command
+var exportStreamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "export the nodes of a label, or the relationships of a type, a page at a time",
+	Long: `Export every node of a label (--label), or every relationship of a type
+(--type), as apoc json lines, reading --page-size elements at a time and
+writing each page as soon as it is read, so that memory use stays constant
+however many elements are exported. Relationships are written without the
+nodes at either end of them, which can be streamed by label.
+
+Pages follow one another by a stable cursor: the internal id of the elements,
+or for nodes the property given by --order-by, which must be the single
+property of a uniqueness constraint or node key of the label. Nodes without
+the property are not exported.
+
+--limit stops the export after that many elements. --since only exports the
+elements whose timestamp property, configured for the label or type under
+timestamps in the configuration file, holds a datetime at or after the time
+given, as 2006-01-02 or 2006-01-02T15:04:05Z07:00.
+
+With --checkpoint, the position reached is written to the file given after
+every page. Running the same export again then resumes from it, discarding
+anything written to the output after the last checkpoint, and an export which
+has completed is not run again. The query (--query) is not used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		if streamOptions.Label != "" {
+			streamOptions.Timestamp = cfg.TimestampProperty(streamOptions.Label)
+		} else {
+			streamOptions.Timestamp = cfg.TimestampProperty(streamOptions.Type)
+		}
+		if streamSince != "" {
+			since, err := parseSince(streamSince)
+			if err != nil {
+				return err
+			}
+			streamOptions.Since = &since
+			if streamOptions.Timestamp == "" {
+				return fmt.Errorf("no timestamp property is configured for %s%s in %s to export elements since a time", streamOptions.Label, streamOptions.Type, cfg.Database)
+			}
+		}
+		if streamCheckpoint != "" && exportPath == "" {
+			return errors.New("a checkpoint can only be kept when writing to a file (--out)")
+		}
+
+		checkpoint := pkg.NewStreamCheckpoint(streamOptions)
+		if streamCheckpoint != "" {
+			checkpoint, err = pkg.ReadStreamCheckpoint(streamCheckpoint)
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				checkpoint = pkg.NewStreamCheckpoint(streamOptions)
+			case err != nil:
+				return err
+			case !checkpoint.Resumes(streamOptions):
+				return fmt.Errorf("%s was written by the export of other elements, remove it to start again", streamCheckpoint)
+			case checkpoint.Complete:
+				fmt.Fprintln(os.Stderr, "export report: already complete,", checkpoint.Written, "elements exported")
+				return nil
+			}
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		if streamOptions.OrderBy != "" {
+			c, err := loadConstraints(&driver, true)
+			if err != nil {
+				return err
+			}
+			if !isSingleKey(&c, streamOptions.Label, streamOptions.OrderBy) {
+				return fmt.Errorf("%s is not unique within :%s, so nodes cannot be exported in order of it", streamOptions.OrderBy, streamOptions.Label)
+			}
+		}
+
+		var out io.Writer = os.Stdout
+		if exportPath != "" {
+			f, err := openStreamOutput(exportPath, checkpoint.Offset)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		if checkpoint.Written > 0 {
+			fmt.Fprintln(os.Stderr, "resuming after", checkpoint.Written, "elements")
+		}
+
+		checkpoint, err = pkg.StreamGraph(&driver, cfg.Database, streamOptions, checkpoint, out, func(c pkg.StreamCheckpoint) error {
+			fmt.Fprintln(os.Stderr, "\t", c.Written, "elements exported")
+			if streamCheckpoint == "" {
+				return nil
+			}
+			return pkg.WriteStreamCheckpoint(streamCheckpoint, c)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "export report:", checkpoint.Written, "elements exported")
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportStreamCmd)
+
+	exportStreamCmd.Flags().StringVar(&streamOptions.Label, "label", "", "label of the nodes to export")
+	exportStreamCmd.Flags().StringVar(&streamOptions.Type, "type", "", "type of the relationships to export")
+	exportStreamCmd.Flags().StringVar(&streamOptions.OrderBy, "order-by", "", "unique property nodes are exported in order of (default is their internal id)")
+	exportStreamCmd.Flags().IntVar(&streamOptions.PageSize, "page-size", 10000, "number of nodes or relationships read by each query")
+	exportStreamCmd.Flags().IntVar(&streamOptions.Limit, "limit", 0, "number of elements exported at most (default is all of them)")
+	exportStreamCmd.Flags().StringVar(&streamSince, "since", "", "only export elements written at or after this time")
+	exportStreamCmd.Flags().StringVar(&streamCheckpoint, "checkpoint", "", "file recording the position reached, from which an interrupted export resumes")
+	exportStreamCmd.MarkFlagsMutuallyExclusive("label", "type")
+}
+
+// parseSince reads a time given on the command line as a date or an RFC 3339 timestamp, dates being taken as UTC
+func parseSince(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return t, fmt.Errorf("%s is neither a date (2006-01-02) nor a timestamp (2006-01-02T15:04:05Z07:00)", raw)
+	}
+	return t, nil
+}
+
+// isSingleKey reports whether a property alone is unique within a label, by a uniqueness constraint or node key
+func isSingleKey(c *geno.Constraints, label, property string) bool {
+	for _, key := range append(append([]geno.Constraint(nil), c.NodeUniqueness...), c.NodeKeys...) {
+		if key.Label == label && len(key.Properties) == 1 && key.Properties[0] == property {
+			return true
+		}
+	}
+	return false
+}
+
+// openStreamOutput opens the output of a stream, keeping the bytes written up to its last checkpoint and nothing after
+func openStreamOutput(path string, offset int64) (*os.File, error) {
+	if offset == 0 {
+		return os.Create(path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("the output of the interrupted export could not be reopened: %w", err)
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tablePatternPath string
+	tableFormat      string
+)
+
+// exportTableCmd represents the export table command
+var exportTableCmd = &cobra.Command{
+	Use:   "table",
+	Short: "export one row per node of a label, with the values of related nodes as columns",
+	Long: `Export a flat table with one row per node of an anchor label, for those who
+want spreadsheets rather than graphs, e.g.
+geno export table --pattern customers.yaml -o customers.xlsx
+
+The pattern is a yaml file naming the anchor label and its columns. A column
+is a property of the anchor, or of the nodes reached from it by one
+relationship of a type, followed in either direction unless a Direction
+(OUTGOING or INCOMING) is given and optionally limited to nodes with a Label:
+
+Anchor: Customer
+Columns:
+  - Name: Name
+    Property: NAME1
+  - Name: Phone
+    Relationship: HAS_PHONE
+    Label: Phone
+    Property: Phone
+  - Name: Banks
+    Relationship: HAS_BANK
+    Property: BANKL
+    Aggregate: count
+
+A column reaching several nodes lists their distinct values in order, joined
+by the Separator ("; " by default), or with an Aggregate of first or count
+holds only the first of them or their number. Rows are identified, and
+ordered, by the pattern's Keys, which default to the uniqueness, key and
+existence constraint properties configured for the anchor, and are written as
+the first columns, so no column may be named after a key.
+
+The table is written as csv or xlsx, given by --format or inferred from the
+extension of --out, and csv by default. An xlsx table is written to a sheet
+named after the anchor label, which excel limits to 31 characters, none of them
+: \ / ? * [ or ]. The export query (--query) is not used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if tablePatternPath == "" {
+			return errors.New("a table pattern (--pattern) must be provided")
+		}
+		raw, err := os.ReadFile(tablePatternPath)
+		if err != nil {
+			return err
+		}
+		pattern, err := pkg.ReadTablePattern(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tablePatternPath, err)
+		}
+		format := strings.ToLower(tableFormat)
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(exportPath)), ".")
+		}
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "xlsx" {
+			return fmt.Errorf("tables are written as csv or xlsx, not %s", format)
+		}
+		if format == "xlsx" {
+			if err := pkg.CheckSheetName(pattern.Anchor); err != nil {
+				return fmt.Errorf("the anchor label names the sheet of the workbook: %w", err)
+			}
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		c, err := loadConstraints(&driver, refreshConstraints)
+		if err != nil {
+			return err
+		}
+		header, rows, err := pkg.GetTable(&driver, cfg.Database, pattern, &c)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if exportPath != "" {
+			f, err := os.Create(exportPath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		if format == "xlsx" {
+			err = pkg.WriteTableXlsx(out, pattern.Anchor, header, rows)
+		} else {
+			err = pkg.WriteTableCsv(out, header, rows)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "export report:", len(rows), "rows of", len(header), "columns exported")
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportTableCmd)
+
+	exportTableCmd.Flags().StringVarP(&tablePatternPath, "pattern", "p", "", "yaml file describing the anchor label and columns of the table")
+	exportTableCmd.Flags().StringVar(&tableFormat, "format", "", "format to write: csv or xlsx (default inferred from --out, or csv)")
+	exportTableCmd.Flags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the database")
+}
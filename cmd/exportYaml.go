@@ -0,0 +1,42 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+// exportYamlCmd represents the export yaml command
+var exportYamlCmd = &cobra.Command{
+	Use:   "yaml",
+	Short: "export nodes and relationships to a yaml file",
+	Long: `Export the subgraph returned by the export query as yaml, in the nodes
+and rels structure read by geno import yaml.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportGraph(pkg.WriteYaml)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportYamlCmd)
+}
@@ -22,9 +22,40 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 )
 
+const (
+	onInvalidFail       string = "fail"        // nothing is imported if any record is invalid
+	onInvalidSkip       string = "skip"        // invalid records are reported and skipped
+	onInvalidDeadLetter string = "dead-letter" // invalid records are reported and written to a file
+)
+
+var (
+	refreshConstraints bool
+	importBatchSize    int
+	importDryRun       bool
+	onInvalid          string
+	deadLetterPath     string
+	query              geno.Query
+	constraints        geno.Constraints
+	nodesFoundCount    map[string]int = make(map[string]int)
+	relsFoundCount     map[string]int = make(map[string]int)
+	nodesMergedCount   map[string]int = make(map[string]int)
+	relsMergedCount    map[string]int = make(map[string]int)
+)
+
 // importCmd represents the import command
 var importCmd = &cobra.Command{
 	Use:   "import",
@@ -35,6 +66,15 @@ constraints which extended beyond those in native neo4j.
 
 Filetypes currently included are:
 - json (command json)
+- yaml (command yaml)
+- graphml (command graphml)
+- apoc json lines (command apoc-json)
+- neo4j browser query results (command browser)
+- cypher scripts (command cypher)
+- delimited tables through a mapping file (command table)
+- rdf as N-Triples or Turtle (command rdf)
+- excel workbooks (command xlsx)
+- manifests listing several sources of the formats above but cypher (command manifest)
 
 Non-native constraint types include:
 - Uniqueness of nodes with multiple property definitions in Community Edition
@@ -42,7 +82,36 @@ Non-native constraint types include:
 - Node property existence on multiple properties for each node label in Community Edition
 - Relationship unqiueness
 - Relationship keys
-- Relationship property existence in Community Edition`,
+- Relationship property existence in Community Edition
+- Property types of nodes and relationships before Neo4j 5.9
+- Relationship cardinality (minimum and maximum degree per node label)
+- Labels allowed at either end of a relationship type
+- Property value rules (regex, enum, numeric and date ranges, length, non-empty)
+
+Every command reads the files given by --filepath, which may be a single file,
+a directory, a glob such as exports/*.json.gz, or - for stdin. Files compressed
+with gzip, bzip2 or zstandard are decompressed, and tar archives are expanded
+into the files they hold. All files are read as one set before anything is
+written, so relationship files may refer to the nodes of node files, and every
+node of the set is merged before any relationship.
+
+Nodes and relationships are merged --batch-size at a time. With --dry-run the
+files are read and validated, and what would be merged is reported, without
+writing anything. Labels and relationship types given a timestamp property
+under timestamps in the configuration file have it set to the time each
+element is created or its values changed, which geno export changes reads.
+
+Relationships of a type with a maximum cardinality are checked and merged one
+at a time, each in a single transaction, so that each check counts those merged
+before it and relationships replaced are only deleted once the new one is
+merged. Imports of such
+relationships should not run at the same time, as each checks the degree of a
+node before the other has written to it; geno audit cardinality finds any node
+they push over its maximum.
+
+Records which violate a constraint cause the whole import to fail by default.
+With --on-invalid skip they are reported and skipped instead, and with
+--on-invalid dead-letter they are also written to the --dead-letter file.`,
 }
 
 func init() {
@@ -57,9 +126,279 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// importCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	importCmd.PersistentFlags().StringVarP(&cfg.Database, "database", "d", cfg.Database, "Insert records into this database")
+	addConnectionFlags(importCmd, "Insert records into this database")
+	importCmd.PersistentFlags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the database")
+	importCmd.PersistentFlags().StringVar(&onInvalid, "on-invalid", onInvalidFail, "handling of records violating constraints: fail, skip or dead-letter")
+	importCmd.PersistentFlags().StringVar(&deadLetterPath, "dead-letter", "", "json file receiving invalid records when --on-invalid is dead-letter")
+	importCmd.PersistentFlags().IntVarP(&importBatchSize, "batch-size", "b", 1000, "number of nodes or relationships merged by each statement")
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "validate and report what would be merged without writing anything")
+}
+
+// importGraph merges every node and then every relationship of a graph into the configured database,
+// respecting the configured (or refreshed) constraints. The whole graph is validated before anything is written,
+// and invalid nodes and relationships are handled according to the --on-invalid policy.
+func importGraph(graph pkg.Graph, keys ...geno.Constraint) error {
+	if err := checkOnInvalid(); err != nil {
+		return err
+	}
 
-	importCmd.PersistentFlags().StringVarP(&cfg.Server, "server", "s", cfg.Server, "Location of database in format: <SERVER>:<PORT>")
+	driver, err := newDriver()
+	if err != nil {
+		return err
+	}
+	defer driver.Close()
+
+	constraints, err = loadConstraints(&driver, refreshConstraints)
+	if err != nil {
+		return err
+	}
+	constraints = withNodeKeys(constraints, keys...)
+
+	valid, invalid, violations := partitionGraph(graph, &constraints)
+	if len(violations) > 0 && onInvalid == onInvalidFail {
+		for _, v := range violations {
+			fmt.Fprintln(os.Stderr, "\t"+v.Error())
+		}
+		return fmt.Errorf("%d constraint violation(s) were found, nothing was imported", len(violations))
+	}
+
+	warnUnindexedMerges(&driver, cfg.Database, valid, &constraints)
+	if importDryRun {
+		reportDryRun(cfg.Database, len(valid.Nodes), len(valid.Relationships), invalid, violations)
+		return nil
+	}
+
+	query = geno.NewQuery(&driver, &constraints)
+	if err := mergeNodes(&query, cfg.Database, valid.Nodes, geno.MERGE_KEEP_SURVIVOR, importBatchSize, "nodes"); err != nil {
+		return err
+	}
+	rejected, found, err := mergeRelationships(&query, cfg.Database, valid.Relationships, geno.MERGE_KEEP_SURVIVOR, importBatchSize, "rels ")
+	if err != nil {
+		return err
+	}
+	invalid.Relationships = append(invalid.Relationships, rejected...)
+	violations = append(violations, found...)
+
+	return reportImport(invalid, violations)
+}
+
+// withNodeKeys returns a copy of the constraints in which nodes are also merged on the uniqueness constraints given,
+// for formats which carry the identity of their nodes, unless a key or uniqueness constraint on the same properties
+// is already configured
+func withNodeKeys(c geno.Constraints, keys ...geno.Constraint) geno.Constraints {
+	var uniqueness []geno.Constraint = append([]geno.Constraint{}, c.NodeUniqueness...)
+	for _, key := range keys {
+		var configured bool
+		for _, existing := range append(append([]geno.Constraint{}, c.NodeKeys...), c.NodeUniqueness...) {
+			if existing.Label == key.Label && reflect.DeepEqual(existing.Properties, key.Properties) {
+				configured = true
+				break
+			}
+		}
+		if !configured {
+			uniqueness = append(uniqueness, key)
+		}
+	}
+	c.NodeUniqueness = uniqueness
+	return c
+}
+
+// mergeNodes merges nodes in batches of up to batchSize, counting them by label for the import report
+func mergeNodes(q *geno.Query, database string, nodes []geno.Node, policy geno.MergePolicy, batchSize int, description string) error {
+	bar := progressbar.Default(int64(len(nodes)), description)
+	return q.MergeNodes(database, nodes, policy, batchSize, func(batch []geno.Node, summary neo4j.ResultSummary) {
+		bar.Add(len(batch))
+		for _, l := range batch[0].Labels { // every node of a batch has the same labels
+			nodesFoundCount[l] += len(batch)
+			nodesMergedCount[l] += summary.Counters().NodesCreated()
+		}
+	})
+}
+
+// mergeRelationships merges relationships in batches of up to batchSize, counting them by type for the import report.
+// Relationships which would exceed the maximum degree of a cardinality constraint are returned, along with their
+// violations, rather than merged. Relationships bounded by a maximum degree are merged one at a time, in the
+// transaction which checks them, so that the check of the next relationship of the same node counts them.
+func mergeRelationships(q *geno.Query, database string, rels []geno.Relationship, policy geno.MergePolicy, batchSize int, description string) (rejected []geno.Relationship, violations []geno.Violation, err error) {
+	var (
+		accepted []geno.Relationship = make([]geno.Relationship, 0, len(rels))
+		bar                          = progressbar.Default(int64(len(rels)), description)
+		merged                       = func(batch []geno.Relationship, summary neo4j.ResultSummary) {
+			bar.Add(len(batch))
+			relsMergedCount[batch[0].Label] += summary.Counters().RelationshipsCreated()
+		}
+	)
+	for _, rel := range rels {
+		relsFoundCount[rel.Label]++
+		if !q.BoundsDegree(&rel) {
+			accepted = append(accepted, rel)
+			continue
+		}
+		found, err := q.MergeBoundedRelationship(database, rel, policy, merged)
+		if err != nil {
+			return rejected, violations, err
+		}
+		if len(found) > 0 {
+			violations = append(violations, found...)
+			rejected = append(rejected, rel)
+			bar.Add(1)
+		}
+	}
+
+	err = q.MergeRelationships(database, accepted, policy, batchSize, merged)
+	return rejected, violations, err
+}
+
+// reportDryRun prints what an import would merge, along with every violation, without writing anything
+func reportDryRun(database string, nodes, relationships int, invalid pkg.Graph, violations []geno.Violation) {
+	fmt.Println("dry run:", nodes, "nodes and", relationships, "relationships would be merged into", database)
+	if len(violations) > 0 {
+		fmt.Println("invalid records report:", len(invalid.Nodes), "nodes and", len(invalid.Relationships), "relationships would not be imported")
+		for _, v := range violations {
+			fmt.Println("\t" + v.Error())
+		}
+	}
+}
+
+// reportImport prints the nodes and relationships found and merged by label and type, along with every violation,
+// and writes invalid records to the dead letter file when requested
+func reportImport(invalid pkg.Graph, violations []geno.Violation) error {
+	fmt.Println("nodes report:", printMapSum(nodesMergedCount), "of", printMapSum(nodesFoundCount), "merged")
+	for lab, cnt := range nodesFoundCount {
+		fmt.Println("\tNode type:", lab, " found:", cnt, " merged:", nodesMergedCount[lab])
+	}
+	fmt.Println("relationships report:", printMapSum(relsMergedCount), "of", printMapSum(relsFoundCount), "merged")
+	for lab, cnt := range relsFoundCount {
+		fmt.Println("\tNode type:", lab, " found:", cnt, " merged:", relsMergedCount[lab])
+	}
+	if len(violations) > 0 {
+		fmt.Println("invalid records report:", len(invalid.Nodes), "nodes and", len(invalid.Relationships), "relationships not imported")
+		for _, v := range violations {
+			fmt.Println("\t" + v.Error())
+		}
+	}
+	if len(violations) > 0 && onInvalid == onInvalidDeadLetter {
+		if err := writeDeadLetters(invalid); err != nil {
+			return err
+		}
+		fmt.Println("\tinvalid records written to", deadLetterPath)
+	}
+	return nil
+}
+
+// checkOnInvalid rejects an unknown --on-invalid policy, or the dead-letter policy without a file to write
+func checkOnInvalid() error {
+	switch onInvalid {
+	case onInvalidFail, onInvalidSkip:
+	case onInvalidDeadLetter:
+		if deadLetterPath == "" {
+			return errors.New("a dead letter file (--dead-letter) must be provided with --on-invalid dead-letter")
+		}
+	default:
+		return fmt.Errorf("invalid record policy %s is not supported", onInvalid)
+	}
+	return nil
+}
+
+// partitionGraph separates nodes and relationships which satisfy the offline constraints from those which do not.
+// Relationships to an invalid node are invalid themselves, as their endpoint will never be merged.
+func partitionGraph(graph pkg.Graph, c *geno.Constraints) (valid, invalid pkg.Graph, violations []geno.Violation) {
+	var rejectedNodes map[int64]bool = make(map[int64]bool)
+	for i := range graph.Nodes {
+		found := c.ValidateNode(&graph.Nodes[i])
+		if len(found) > 0 {
+			violations = append(violations, found...)
+			invalid.Nodes = append(invalid.Nodes, graph.Nodes[i])
+			rejectedNodes[graph.Nodes[i].Id] = true
+			continue
+		}
+		valid.Nodes = append(valid.Nodes, graph.Nodes[i])
+	}
+	for i := range graph.Relationships {
+		rel := &graph.Relationships[i]
+		found := c.ValidateRelationship(rel)
+		if len(found) > 0 || rejectedNodes[rel.Start.Id] || rejectedNodes[rel.End.Id] {
+			violations = append(violations, found...)
+			invalid.Relationships = append(invalid.Relationships, *rel)
+			continue
+		}
+		valid.Relationships = append(valid.Relationships, *rel)
+	}
+	return valid, invalid, violations
+}
+
+// validateGraph checks every node and relationship against the constraints which do not require a database
+func validateGraph(graph pkg.Graph, c *geno.Constraints) []geno.Violation {
+	_, _, violations := partitionGraph(graph, c)
+	return violations
+}
+
+// writeDeadLetters writes invalid records, along with the endpoints of invalid relationships,
+// as a json file which can be imported again once corrected
+func writeDeadLetters(invalid pkg.Graph) error {
+	var included map[int64]bool = make(map[int64]bool)
+	for _, n := range invalid.Nodes {
+		included[n.Id] = true
+	}
+	for _, r := range invalid.Relationships {
+		for _, n := range []geno.Node{r.Start, r.End} {
+			if !included[n.Id] {
+				included[n.Id] = true
+				invalid.Nodes = append(invalid.Nodes, n)
+			}
+		}
+	}
+
+	f, err := os.Create(deadLetterPath)
+	if err != nil {
+		return err
+	}
+	if err := pkg.WriteJson(f, invalid); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// warnUnindexedMerges warns about labels with merge keys backed by neither a constraint nor an index,
+// as every merge of such a node scans all nodes of the label
+func warnUnindexedMerges(driver *geno.Driver, database string, graph pkg.Graph, c *geno.Constraints) {
+	indexes, err := driver.GetIndexes(database)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: indexes could not be read, merges may be slow:", err)
+		return
+	}
+
+	var warned map[string]bool = make(map[string]bool)
+	for i := range graph.Nodes {
+		node := &graph.Nodes[i]
+		if warned[node.String()] {
+			continue
+		}
+		warned[node.String()] = true
+
+		keys := c.GetNodeConstraints(node)
+		if len(keys) == 0 {
+			continue // merged on its labels alone, which no index could speed up
+		}
+		var covered bool
+		for _, label := range node.Labels {
+			if indexes.Covers(geno.IS_NODE, label, keys) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			sort.Strings(keys)
+			fmt.Fprintf(os.Stderr, "warning: merges on :%s(%s) are not backed by a constraint or index and will scan the whole label\n", node, strings.Join(keys, ", "))
+		}
+	}
+}
 
-	importCmd.PersistentFlags().StringVarP(&cfg.User, "username", "u", cfg.User, "Username used to connect to the server")
+func printMapSum(m map[string]int) int {
+	var s int
+	for _, v := range m {
+		s += v
+	}
+	return s
 }
@@ -0,0 +1,54 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importApocJsonCmd represents the import apoc-json command
+var importApocJsonCmd = &cobra.Command{
+	Use:   "apoc-json",
+	Short: "import a file written by apoc.export.json",
+	Long: `Import the line delimited json written by apoc.export.json.all and
+apoc.export.json.query, which holds one node or relationship per line:
+
+{"type":"node","id":"0","labels":["User"],"properties":{"name":"Adam"}}
+{"type":"relationship","id":"0","label":"KNOWS","properties":{"since":1993},"start":{"id":"0","labels":["User"]},"end":{"id":"1","labels":["User"]}}
+
+Relationships may come before the nodes they connect, but both nodes must be
+in the files imported. The files are read into memory before they are imported;
+to load a whole database from its export, use geno restore instead.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("apoc-json")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importApocJsonCmd)
+
+	importApocJsonCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("apoc json files"))
+}
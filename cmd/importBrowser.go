@@ -0,0 +1,56 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importBrowserCmd represents the import browser command
+var importBrowserCmd = &cobra.Command{
+	Use:   "browser",
+	Short: "import query results exported as json from Neo4j Browser",
+	Long: `Import the results of a query as exported to json by Neo4j Browser, an
+array of records holding each column by name:
+
+[
+	{"n":{"identity":1,"labels":["Label1"],"properties":{...}},"r":{"identity":4,"start":1,"end":2,"type":"Rel_Type","properties":{...}},"m":{...}},
+	{"p":{"start":{...},"end":{...},"segments":[{"start":{...},"relationship":{...},"end":{...}}],"length":1}}
+]
+
+Every node, relationship and path found in any column, including those within
+lists and maps, is imported once. Relationships can only be imported when the
+nodes at either end of them are also part of the export.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("browser")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importBrowserCmd)
+
+	importBrowserCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("exported json files"))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+// importCypherCmd represents the import cypher command
+var importCypherCmd = &cobra.Command{
+	Use:   "cypher",
+	Short: "run a cypher script as cypher-shell would",
+	Long: `Run a cypher script, such as one written by geno export cypher, against the
+database. Statements end with a semicolon and are committed one at a time,
+unless they are wrapped in :begin and :commit. The cypher-shell directives
+:param (both name => value and {name: value}), :begin, :commit, :rollback and
+:use are honoured.
+
+Several scripts, given as a directory or glob, are run one after another in
+order of their names. Execution stops at the first failing statement, reporting
+the script and line it started on; an open transaction is rolled back. With
+--dry-run the scripts are only parsed, and --batch-size is not used.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputs, err := readInputs()
+		if err != nil {
+			return err
+		}
+		var (
+			scripts [][]geno.ScriptStatement = make([][]geno.ScriptStatement, len(inputs))
+			total   int
+		)
+		for i, input := range inputs {
+			if scripts[i], err = geno.ParseScript(input.Reader()); err != nil {
+				return fmt.Errorf("%s: %w", input.Name, err)
+			}
+			total += len(scripts[i])
+		}
+
+		if importDryRun {
+			fmt.Println("dry run:", total, "statements of", len(inputs), "script(s) parsed, nothing was run")
+			return nil
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		var (
+			nodesCreated int
+			relsCreated  int
+			propsSet     int
+			executed     int
+		)
+		bar := progressbar.Default(int64(total), "statements")
+		progress := func(s geno.ScriptStatement, summary neo4j.ResultSummary, err error) {
+			bar.Add(1)
+			if err != nil {
+				return
+			}
+			executed++
+			if summary != nil {
+				nodesCreated += summary.Counters().NodesCreated()
+				relsCreated += summary.Counters().RelationshipsCreated()
+				propsSet += summary.Counters().PropertiesSet()
+			}
+		}
+		for i, input := range inputs {
+			if err = driver.RunScript(cfg.Database, scripts[i], progress); err != nil {
+				err = fmt.Errorf("%s: %w", input.Name, err)
+				break
+			}
+		}
+		fmt.Println("script report:", executed, "of", total, "statements run,", nodesCreated, "nodes created,", relsCreated, "relationships created,", propsSet, "properties set")
+		return err
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importCypherCmd)
+
+	importCypherCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("cypher scripts"))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importGraphmlCmd represents the import graphml command
+var importGraphmlCmd = &cobra.Command{
+	Use:   "graphml",
+	Short: "import a graphml file of nodes and edges",
+	Long: `Import the first graph of a GraphML file, such as those written by Gephi,
+yEd, NetworkX or apoc.export.graphml.
+
+Each <node> becomes a node and each <edge> a relationship. Properties are typed
+according to their <key> declarations (boolean, int, long, float, double or
+string, including APOC's attr.list), and keys' defaults apply to elements
+without a value. Labels are read from a node attribute holding colon separated
+labels (e.g. :Customer:Vendor) and relationship types from an edge attribute;
+both attributes can be changed to suit the tool which wrote the file. Labels
+and types are made plain names, with any character other than a letter, digit
+or underscore replaced by an underscore, so My Label becomes My_Label.
+
+Every edge must have a type, and every node a label. Files written by tools
+without labels, such as NetworkX, can be read with --default-label, which
+labels every node without one.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("graphml")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importGraphmlCmd)
+
+	importGraphmlCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("graphml files"))
+	importGraphmlCmd.Flags().StringVar(&graphmlOptions.LabelAttribute, "label-attribute", graphmlOptions.LabelAttribute, "node attribute holding labels")
+	importGraphmlCmd.Flags().StringVar(&graphmlOptions.TypeAttribute, "type-attribute", graphmlOptions.TypeAttribute, "edge attribute holding relationship types")
+	importGraphmlCmd.Flags().StringVar(&graphmlOptions.DefaultLabel, "default-label", "", "label of nodes without any (default is to fail on them)")
+}
@@ -22,26 +22,11 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"errors"
-	"fmt"
-	"os"
-
-	"github.com/Viking2012/geno/geno"
-	"github.com/Viking2012/geno/pkg"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
-	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 )
 
 var (
-	fPath              string
-	refreshConstraints bool
-	query              geno.Query
-	constraints        geno.Constraints
-	nodesFoundCount    map[string]int = make(map[string]int)
-	relsFoundCount     map[string]int = make(map[string]int)
-	nodesMergedCount   map[string]int = make(map[string]int)
-	relsMergedCount    map[string]int = make(map[string]int)
+	fPath string
 )
 
 // jsonCmd represents the json command
@@ -60,67 +45,11 @@ The file must be in the format:
 	]
 }`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if fPath == "" {
-			return errors.New("filepath cannot be empty")
-		}
-		raw, err := os.ReadFile(fPath)
-		if err != nil {
-			return err
-		}
-		graph, err := pkg.GetGraphFromJson(raw)
+		graph, err := readGraphInputs("json")
 		if err != nil {
 			return err
 		}
-		driver, err := geno.NewDriver("neo4j://"+cfg.Server, neo4j.BasicAuth(cfg.User, cfg.GetPassword(), ""))
-		if err != nil {
-			return err
-		}
-
-		// fmt.Println(cfg.Constraints)
-		if refreshConstraints {
-			constraints, err = driver.GetConstraints(cfg.Database)
-			if err != nil {
-				return err
-			}
-		} else {
-			constraints = cfg.Constraints[cfg.Database]
-		}
-
-		query = geno.NewQuery(&driver, &constraints)
-
-		bar := progressbar.Default(int64(len(graph.Nodes)), "nodes")
-		for _, node := range graph.Nodes {
-			summary, err := query.MergeNode(cfg.Database, node)
-			if err != nil {
-				return err
-			}
-			bar.Add(1)
-			for _, l := range node.Labels {
-				nodesFoundCount[l]++
-				nodesMergedCount[l] += summary.Counters().NodesCreated()
-			}
-
-		}
-		bar = progressbar.Default(int64(len(graph.Relationships)), "rels ")
-		for _, rel := range graph.Relationships {
-			summary, err := query.MergeRelationship(cfg.Database, rel)
-			if err != nil {
-				return err
-			}
-			bar.Add(1)
-			relsFoundCount[rel.Label]++
-			relsMergedCount[rel.Label] += summary.Counters().RelationshipsCreated()
-		}
-
-		fmt.Println("nodes report:", printMapSum(nodesMergedCount), "of", printMapSum(nodesFoundCount), "merged")
-		for lab, cnt := range nodesFoundCount {
-			fmt.Println("\tNode type:", lab, " found:", cnt, " merged:", nodesMergedCount[lab])
-		}
-		fmt.Println("relationships report:", printMapSum(relsMergedCount), "of", printMapSum(relsFoundCount), "merged")
-		for lab, cnt := range relsFoundCount {
-			fmt.Println("\tNode type:", lab, " found:", cnt, " merged:", relsMergedCount[lab])
-		}
-		return nil
+		return importGraph(graph)
 	},
 }
 
@@ -136,14 +65,5 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// jsonCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	jsonCmd.Flags().StringVarP(&fPath, "filepath", "f", "", "path to the json file")
-	jsonCmd.Flags().BoolVarP(&refreshConstraints, "refresh-constraints", "r", false, "attempt to read constraints direct from the database")
-}
-
-func printMapSum(m map[string]int) int {
-	var s int
-	for _, v := range m {
-		s += v
-	}
-	return s
+	jsonCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("json files"))
 }
@@ -0,0 +1,286 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+// manifestSource is a source of a manifest once it has been read and validated
+type manifestSource struct {
+	pkg.ManifestSource
+	graph       pkg.Graph
+	valid       pkg.Graph
+	keys        []geno.Constraint // merge keys carried by the format, such as the IRIs of rdf
+	constraints *geno.Constraints
+}
+
+// manifestCmd represents the manifest command
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "import every source listed by a manifest",
+	Long: `Import every source listed by a yaml manifest, e.g. geno import manifest -f load.yaml
+
+Database: PRD              # database of sources which do not name one
+OnConflict: keep           # keep, overwrite or fail existing values
+BatchSize: 1000            # nodes or relationships merged by each statement
+Sources:
+    - Name: customers      # defaults to the path
+      Path: extracts/customers_*.json.gz
+      Identity: sap
+    - Name: orders
+      Path: extracts/orders.csv
+      Format: csv          # inferred from the extension when not given
+      Mapping: mappings/orders.yaml
+      Database: SALES
+      OnConflict: overwrite
+      BatchSize: 500
+      After: [customers]   # sources which must be loaded first
+    - Name: ownership
+      Path: extracts/ownership.json
+      Identity: sap
+
+Paths are relative to the manifest and are read as --filepath is by the other
+import commands. Sources are loaded in the order listed, except that a source is
+moved after those named by its After list. The nodes of every source are merged
+before the relationships of any.
+
+Json and yaml sources sharing an Identity space are read as one set, so that
+their relationships may refer to the node ids of the other sources of the space.
+They must write to the same database and may not reuse each other's node ids.
+
+With OnConflict keep, existing nodes and relationships keep their values and
+properties are only set when they are created. With overwrite the values merged
+replace theirs, and with fail a batch holding an element whose existing values
+differ is not written, and the import stops.
+
+The whole manifest is read and validated against the constraints of each
+database before anything is written. Batch sizes and conflict policies are
+taken from the manifest rather than --batch-size.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOnInvalid(); err != nil {
+			return err
+		}
+		sources, err := readManifestSources()
+		if err != nil {
+			return err
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		var (
+			databases  map[string]*geno.Constraints = make(map[string]*geno.Constraints)
+			invalid    pkg.Graph
+			violations []geno.Violation
+		)
+		for i := range sources {
+			s := &sources[i]
+			if _, found := databases[s.Database]; !found {
+				c, err := loadDatabaseConstraints(&driver, s.Database, refreshConstraints)
+				if err != nil {
+					return fmt.Errorf("database %s: %w", s.Database, err)
+				}
+				databases[s.Database] = &c
+			}
+			s.constraints = databases[s.Database]
+			if len(s.keys) > 0 {
+				c := withNodeKeys(*s.constraints, s.keys...)
+				s.constraints = &c
+			}
+
+			var found []geno.Violation
+			var rejected pkg.Graph
+			s.valid, rejected, found = partitionGraph(s.graph, s.constraints)
+			violations = append(violations, found...)
+			invalid.Nodes = append(invalid.Nodes, rejected.Nodes...)
+			invalid.Relationships = append(invalid.Relationships, rejected.Relationships...)
+		}
+		if len(violations) > 0 && onInvalid == onInvalidFail {
+			for _, v := range violations {
+				fmt.Fprintln(os.Stderr, "\t"+v.Error())
+			}
+			return fmt.Errorf("%d constraint violation(s) were found, nothing was imported", len(violations))
+		}
+
+		for _, s := range sources {
+			warnUnindexedMerges(&driver, s.Database, s.valid, s.constraints)
+		}
+
+		if importDryRun {
+			for _, s := range sources {
+				fmt.Print("source ", s.Name, ": ")
+				reportDryRun(s.Database, len(s.valid.Nodes), len(s.valid.Relationships), pkg.Graph{}, nil)
+			}
+			if len(violations) > 0 {
+				reportDryRun("the manifest's databases", 0, 0, invalid, violations)
+			}
+			return nil
+		}
+
+		for _, s := range sources {
+			q := geno.NewQuery(&driver, s.constraints)
+			if err := mergeNodes(&q, s.Database, s.valid.Nodes, geno.MergePolicy(s.OnConflict), s.BatchSize, s.Name+" nodes"); err != nil {
+				return fmt.Errorf("source %s: %w", s.Name, err)
+			}
+		}
+		for _, s := range sources {
+			q := geno.NewQuery(&driver, s.constraints)
+			rejected, found, err := mergeRelationships(&q, s.Database, s.valid.Relationships, geno.MergePolicy(s.OnConflict), s.BatchSize, s.Name+" rels")
+			if err != nil {
+				return fmt.Errorf("source %s: %w", s.Name, err)
+			}
+			invalid.Relationships = append(invalid.Relationships, rejected...)
+			violations = append(violations, found...)
+		}
+
+		return reportImport(invalid, violations)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(manifestCmd)
+
+	manifestCmd.Flags().StringVarP(&fPath, "filepath", "f", "", "path of the yaml manifest, or - for stdin")
+}
+
+// readManifestSources reads the manifest named by --filepath and every source it lists, checking formats, mappings
+// and identity spaces, so that nothing is written unless every source can be read
+func readManifestSources() ([]manifestSource, error) {
+	var (
+		raw []byte
+		dir string = "."
+		err error
+	)
+	switch fPath {
+	case "":
+		return nil, errors.New("filepath cannot be empty")
+	case "-":
+		raw, err = io.ReadAll(os.Stdin)
+	default:
+		raw, err = os.ReadFile(fPath)
+		dir = filepath.Dir(fPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := pkg.ReadManifest(raw, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sources []manifestSource = make([]manifestSource, len(manifest.Sources))
+		inputs  [][]pkg.Input    = make([][]pkg.Input, len(manifest.Sources))
+		spaces  map[string][]int = make(map[string][]int)
+		order   []string
+	)
+	for i, ms := range manifest.Sources {
+		if ms.Database == "" {
+			ms.Database = cfg.Database
+		}
+		sources[i].ManifestSource = ms
+
+		if inputs[i], err = pkg.ReadInputs(ms.Path, os.Stdin); err != nil {
+			return nil, fmt.Errorf("source %s: %w", ms.Name, err)
+		}
+		format := ms.Format
+		if format == "" {
+			if format = formatExtensions[strings.ToLower(filepath.Ext(inputs[i][0].Name))]; format == "" {
+				return nil, fmt.Errorf("source %s: the format of %s cannot be inferred from its extension and must be given", ms.Name, inputs[i][0].Name)
+			}
+		}
+		if _, found := graphReaders[format]; !found {
+			return nil, fmt.Errorf("source %s: %s cannot be imported by a manifest; choose from %s", ms.Name, format, formatNames(graphReaders))
+		}
+		switch format {
+		case "csv", "xlsx", "rdf":
+		default:
+			if ms.Mapping != "" {
+				return nil, fmt.Errorf("source %s: only csv, xlsx and rdf sources are read through a mapping", ms.Name)
+			}
+		}
+		sources[i].Format = format
+
+		if ms.Identity != "" {
+			if format != "json" && format != "yaml" {
+				return nil, fmt.Errorf("source %s: only json and yaml sources refer to nodes by id and can share an identity space", ms.Name)
+			}
+			if _, found := spaces[ms.Identity]; !found {
+				order = append(order, ms.Identity)
+			}
+			spaces[ms.Identity] = append(spaces[ms.Identity], i)
+		}
+	}
+
+	for i := range sources {
+		s := &sources[i]
+		if s.Identity != "" {
+			continue
+		}
+		mappingPath = s.Mapping
+		if s.graph, err = graphReaders[s.Format](inputs[i]); err != nil {
+			return nil, fmt.Errorf("source %s: %w", s.Name, err)
+		}
+		if s.Format == "rdf" {
+			mapping, err := readRdfMapping()
+			if err != nil {
+				return nil, fmt.Errorf("source %s: %w", s.Name, err)
+			}
+			s.keys = []geno.Constraint{mapping.Key()}
+		}
+	}
+	for _, space := range order {
+		var sets [][][]byte = make([][][]byte, len(spaces[space]))
+		for j, i := range spaces[space] {
+			if sources[i].Database != sources[spaces[space][0]].Database {
+				return nil, fmt.Errorf("identity space %s: sources %s and %s write to different databases", space, sources[spaces[space][0]].Name, sources[i].Name)
+			}
+			sets[j] = rawInputs(inputs[i])
+			if sources[i].Format == "yaml" {
+				if sets[j], err = pkg.YamlAsJson(sets[j]); err != nil {
+					return nil, fmt.Errorf("source %s: %w", sources[i].Name, err)
+				}
+			}
+		}
+		graphs, err := pkg.GetGraphsFromJsonDocuments(sets)
+		if err != nil {
+			return nil, fmt.Errorf("identity space %s: %w", space, err)
+		}
+		for j, i := range spaces[space] {
+			sources[i].graph = graphs[j]
+		}
+	}
+	return sources, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importRdfCmd represents the import rdf command
+var importRdfCmd = &cobra.Command{
+	Use:   "rdf",
+	Short: "import N-Triples or Turtle",
+	Long: `Import RDF written as N-Triples or Turtle. Every resource becomes a node
+holding its IRI in a key property, labelled with a default label along with
+each of its rdf:type classes. Predicates with literal objects become properties,
+typed by their XSD datatype, and predicates with IRI or blank node objects
+become relationships. Names are compacted with the prefixes declared in the
+file, so that foaf:name becomes the property foaf__name.
+
+An optional yaml mapping file changes these defaults:
+
+KeyProperty: uri        # property holding each resource's IRI
+DefaultLabel: Resource  # label given to every resource
+Names: prefixed         # prefixed (foaf__name) or local (name)
+Prefixes:               # namespaces to compact with, besides those in the file
+    sup: http://example.com/supplier#
+
+Every resource is merged on its IRI, as though the default label and key
+property, Resource.uri by default, were unique, so importing the same triples
+again finds the nodes imported before instead of duplicating them. Create that
+uniqueness constraint in the database so that the merges are indexed.
+Blank nodes have no IRI and are keyed by their label within the file, prefixed
+with a hash of the file's content, such as _:3f2a9c01d4e5b6a7-b0. Blank nodes of
+different files are kept apart, while importing the same file again merges
+them. Namespaces without a declared prefix are given one derived from their
+IRI, such as ns1a2b3c4d, which stays the same from one import to the next.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mapping, err := readRdfMapping()
+		if err != nil {
+			return err
+		}
+		graph, err := readGraphInputs("rdf")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph, mapping.Key())
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importRdfCmd)
+
+	importRdfCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("N-Triples or Turtle files"))
+	importRdfCmd.Flags().StringVarP(&mappingPath, "mapping", "m", "", "path to an optional yaml mapping file")
+}
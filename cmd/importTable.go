@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var mappingPath string
+
+// importTableCmd represents the import table command
+var importTableCmd = &cobra.Command{
+	Use:   "table",
+	Short: "import the rows of a delimited table through a mapping file",
+	Long: `Import a delimited table with a header row, such as an extract of KNA1 or
+LFA1, by building nodes and relationships from every row as described by a yaml
+mapping file:
+
+Delimiter: ";"
+Nodes:
+    - Name: customer # referred to by relationships, defaults to the first label
+      Labels: [Customer]
+      Properties:
+          Key: Database + '-' + KUNNR # columns and quoted literals joined by +
+          KUNNR: KUNNR
+          NAME1: NAME1
+          ERDAT: ERDAT
+      Types:
+          ERDAT: date # string (default), integer, float, boolean or date
+    - Name: phone
+      Labels: [Phone]
+      Properties:
+          Value: TELF1
+Relationships:
+    - Type: HAS_PHONE
+      Start: customer
+      End: phone
+
+Empty values are left out, and a node is not built for a row with no values
+in any of its columns, so customers without a phone number have no phone node.
+Identical nodes built from different rows are imported once. Everything built
+is imported through the same constraint-aware merge as every other filetype.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("csv")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importTableCmd)
+
+	importTableCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("delimited tables"))
+	importTableCmd.Flags().StringVarP(&mappingPath, "mapping", "m", "", "path to the yaml mapping file")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/spf13/cobra"
+)
+
+// importXlsxCmd represents the import xlsx command
+var importXlsxCmd = &cobra.Command{
+	Use:   "xlsx",
+	Short: "import the sheets of an excel workbook",
+	Long: `Import reference tables maintained in an excel workbook, such as bank lists
+or tax codes. Without a mapping file (--mapping), every sheet becomes one node
+per row, labelled with the name of the sheet and holding each column of the
+first row as a property.
+
+A yaml mapping file chooses the sheets to import and maps the rows of each as
+the table command maps the rows of a delimited table:
+
+Sheets:
+    - Sheet: Banks
+      HeaderRow: 2 # row holding the column names, defaults to 1
+      Nodes:
+          - Labels: [Bank]
+            Properties:
+                Key: "'DE-' + BANKL"
+                BANKL: BANKL
+                Name: Bank Name
+    - Sheet: Tax Codes # no nodes mapped, so labelled Tax_Codes
+
+Cells keep their type: numbers, booleans and dates formatted as dates are
+imported as such, unless a property joins several columns or is given a type.
+Identical nodes of different rows and sheets are imported once, through the same
+constraint-aware merge as every other filetype.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("xlsx")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+// readWorkbookMapping reads the mapping file, if one is given; otherwise every sheet is imported
+func readWorkbookMapping() (pkg.WorkbookMapping, error) {
+	if mappingPath == "" {
+		return pkg.WorkbookMapping{}, nil
+	}
+	raw, err := os.ReadFile(mappingPath)
+	if err != nil {
+		return pkg.WorkbookMapping{}, err
+	}
+	return pkg.ReadWorkbookMapping(raw)
+}
+
+func init() {
+	importCmd.AddCommand(importXlsxCmd)
+
+	importXlsxCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("xlsx workbooks"))
+	importXlsxCmd.Flags().StringVarP(&mappingPath, "mapping", "m", "", "path to an optional yaml mapping file")
+}
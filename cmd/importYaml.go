@@ -0,0 +1,63 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// importYamlCmd represents the import yaml command
+var importYamlCmd = &cobra.Command{
+	Use:   "yaml",
+	Short: "import a yaml file of nodes and/or relationships",
+	Long: `Import a yaml file containing nodes and/or relationships, in the same
+structure as the json command. Anchors, aliases and merge keys may be used to
+share blocks of properties:
+
+shared: &shared
+    Database: PRD
+nodes:
+    - identity: 1
+      labels: [Label1, Label2]
+      properties:
+          <<: *shared
+          Prop1: Value1
+rels:
+    - identity: 1
+      start: 1
+      end: 2
+      type: Rel_Type
+      properties: {RelProp1: Value1}`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		graph, err := readGraphInputs("yaml")
+		if err != nil {
+			return err
+		}
+		return importGraph(graph)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importYamlCmd)
+
+	importYamlCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("yaml files"))
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+// indexesCmd represents the indexes command
+var indexesCmd = &cobra.Command{
+	Use:   "indexes",
+	Short: "Compare and create the indexes of a neo4j database",
+	Long: `A collection of commands which compare the indexes declared in the
+configuration file with those found in a database, and create those which are
+missing.
+
+Indexes are declared per database next to constraints:
+
+indexes:
+    geno:
+        Range:
+            - Labels: [Customer]
+              Properties: [Database, KUNNR]
+        Text:
+            - Labels: [Name]
+              Properties: [Value]
+        Point:
+            - Labels: [Address]
+              Properties: [Location]
+        Fulltext:
+            - Name: names
+              Labels: [Customer, Vendor]
+              Properties: [NAME1]
+
+Relationship indexes set EntityType: RELATIONSHIP and list types as Labels.`,
+}
+
+func init() {
+	rootCmd.AddCommand(indexesCmd)
+
+	addConnectionFlags(indexesCmd, "Manage the indexes of this database")
+}
+
+// printIndexes lists indexes one per line, prefixed by their type
+func printIndexes(prefix string, indexes geno.Indexes) {
+	indexes.Each(func(t geno.IndexType, idx geno.Index) error {
+		if idx.Name != "" {
+			fmt.Printf("%s %s INDEX %s ON %s\n", prefix, t, idx.Name, idx)
+		} else {
+			fmt.Printf("%s %s INDEX ON %s\n", prefix, t, idx)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+// indexesDiffCmd represents the indexes diff command
+var indexesDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "show indexes which are missing from or extra in the database",
+	Long: `Compare the indexes configured for the database with those found in it.
+Lines starting with + are configured but missing from the database, lines
+starting with - exist in the database but are not configured. Indexes which
+back a constraint are never reported as extra.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		actual, err := driver.GetIndexes(cfg.Database)
+		if err != nil {
+			return err
+		}
+		missing, extra := geno.DiffIndexes(cfg.Indexes[cfg.Database], actual)
+		if missing.Len() == 0 && extra.Len() == 0 {
+			fmt.Println("indexes of", cfg.Database, "match the configuration")
+			return nil
+		}
+		printIndexes("+", missing)
+		printIndexes("-", extra)
+		return nil
+	},
+}
+
+func init() {
+	indexesCmd.AddCommand(indexesDiffCmd)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/spf13/cobra"
+)
+
+var dropExtraIndexes bool
+
+// indexesPushCmd represents the indexes push command
+var indexesPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "create configured indexes which are missing from the database",
+	Long: `Create every index configured for the database which does not already
+exist. With --drop-extra, named indexes which exist in the database but are not
+configured are dropped as well. Indexes backing a constraint are never dropped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		actual, err := driver.GetIndexes(cfg.Database)
+		if err != nil {
+			return err
+		}
+		missing, extra := geno.DiffIndexes(cfg.Indexes[cfg.Database], actual)
+
+		var created, dropped int
+		err = missing.Each(func(t geno.IndexType, idx geno.Index) error {
+			if err := driver.CreateIndex(cfg.Database, t, idx); err != nil {
+				return err
+			}
+			created++
+			fmt.Println("created", t, "index on", idx)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if dropExtraIndexes {
+			err = extra.Each(func(t geno.IndexType, idx geno.Index) error {
+				if err := driver.DropIndex(cfg.Database, idx); err != nil {
+					return err
+				}
+				dropped++
+				fmt.Println("dropped", t, "index", idx.Name)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Println("indexes report:", created, "created,", dropped, "dropped")
+		return nil
+	},
+}
+
+func init() {
+	indexesCmd.AddCommand(indexesPushCmd)
+
+	indexesPushCmd.Flags().BoolVar(&dropExtraIndexes, "drop-extra", false, "drop named indexes which are not configured")
+}
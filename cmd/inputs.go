@@ -0,0 +1,159 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Viking2012/geno/pkg"
+)
+
+// graphReader reads a graph in one of the formats geno understands from every input given
+type graphReader func(inputs []pkg.Input) (pkg.Graph, error)
+
+// eachInput reads every input on its own, combining the graphs read
+func eachInput(read func(r io.Reader) (pkg.Graph, error)) graphReader {
+	return func(inputs []pkg.Input) (pkg.Graph, error) {
+		var graphs []pkg.Graph = make([]pkg.Graph, len(inputs))
+		for i, input := range inputs {
+			g, err := read(input.Reader())
+			if err != nil {
+				return g, fmt.Errorf("%s: %w", input.Name, err)
+			}
+			graphs[i] = g
+		}
+		return pkg.CombineGraphs(graphs), nil
+	}
+}
+
+// joinedInputs reads every input as a single stream, with a line break between each
+func joinedInputs(inputs []pkg.Input) io.Reader {
+	var readers []io.Reader
+	for _, input := range inputs {
+		readers = append(readers, input.Reader(), strings.NewReader("\n"))
+	}
+	return io.MultiReader(readers...)
+}
+
+func rawInputs(inputs []pkg.Input) [][]byte {
+	var docs [][]byte = make([][]byte, len(inputs))
+	for i, input := range inputs {
+		docs[i] = input.Raw
+	}
+	return docs
+}
+
+// graphReaders are the formats a graph can be read from, by name. Formats which refer to nodes by id,
+// or by IRI, read every input as one set, so that relationships may refer to nodes of other inputs.
+// Formats needing options read them from the same flags as their import commands.
+var graphReaders map[string]graphReader = map[string]graphReader{
+	"json": func(inputs []pkg.Input) (pkg.Graph, error) {
+		return pkg.GetGraphFromJsonDocuments(rawInputs(inputs))
+	},
+	"yaml": func(inputs []pkg.Input) (pkg.Graph, error) {
+		return pkg.GetGraphFromYamlDocuments(rawInputs(inputs))
+	},
+	"graphml": eachInput(func(r io.Reader) (pkg.Graph, error) {
+		return pkg.GetGraphFromGraphML(r, graphmlOptions)
+	}),
+	"apoc-json": func(inputs []pkg.Input) (pkg.Graph, error) {
+		return pkg.GetGraphFromApocJson(joinedInputs(inputs))
+	},
+	"browser": eachInput(func(r io.Reader) (pkg.Graph, error) {
+		raw, err := io.ReadAll(r)
+		if err != nil {
+			return pkg.Graph{}, err
+		}
+		return pkg.GetGraphFromBrowserJson(raw)
+	}),
+	"csv": func(inputs []pkg.Input) (pkg.Graph, error) {
+		if mappingPath == "" {
+			return pkg.Graph{}, errors.New("a mapping file (--mapping) must be provided to read a delimited table")
+		}
+		raw, err := os.ReadFile(mappingPath)
+		if err != nil {
+			return pkg.Graph{}, err
+		}
+		mapping, err := pkg.ReadTableMapping(raw)
+		if err != nil {
+			return pkg.Graph{}, err
+		}
+		return eachInput(func(r io.Reader) (pkg.Graph, error) {
+			return pkg.GetGraphFromTable(r, mapping)
+		})(inputs)
+	},
+	"xlsx": func(inputs []pkg.Input) (pkg.Graph, error) {
+		mapping, err := readWorkbookMapping()
+		if err != nil {
+			return pkg.Graph{}, err
+		}
+		return eachInput(func(r io.Reader) (pkg.Graph, error) {
+			return pkg.GetGraphFromXlsx(r, mapping)
+		})(inputs)
+	},
+	"rdf": func(inputs []pkg.Input) (pkg.Graph, error) {
+		mapping, err := readRdfMapping()
+		if err != nil {
+			return pkg.Graph{}, err
+		}
+		return pkg.GetGraphFromRDFDocuments(rawInputs(inputs), mapping)
+	},
+}
+
+// readRdfMapping reads the mapping file, if one is given; otherwise the default mapping is used
+func readRdfMapping() (pkg.RDFMapping, error) {
+	if mappingPath == "" {
+		return pkg.DefaultRDFMapping, nil
+	}
+	raw, err := os.ReadFile(mappingPath)
+	if err != nil {
+		return pkg.RDFMapping{}, err
+	}
+	return pkg.ReadRDFMapping(raw)
+}
+
+// readInputs reads every file named by --filepath: - for stdin, a file, a directory or a glob,
+// decompressing gzip, bzip2 and zstandard files and expanding tar archives
+func readInputs() ([]pkg.Input, error) {
+	if fPath == "" {
+		return nil, errors.New("filepath cannot be empty")
+	}
+	return pkg.ReadInputs(fPath, os.Stdin)
+}
+
+// readGraphInputs reads every file named by --filepath as a single graph in the given format
+func readGraphInputs(format string) (pkg.Graph, error) {
+	inputs, err := readInputs()
+	if err != nil {
+		return pkg.Graph{}, err
+	}
+	return graphReaders[format](inputs)
+}
+
+// inputUsage describes the --filepath flag of a command reading files of the given kind
+func inputUsage(kind string) string {
+	return "path, directory or glob of " + kind + ", or - for stdin; may be compressed or archived with tar"
+}
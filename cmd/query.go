@@ -0,0 +1,208 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Viking2012/geno/pkg"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryFile        string
+	queryParams      []string
+	queryAccess      string
+	queryFormat      string
+	queryGraphFormat string
+)
+
+// resultWriters are the formats the records of a query can be written in, by name
+var resultWriters map[string]func(io.Writer, []string, []*db.Record) error = map[string]func(io.Writer, []string, []*db.Record) error{
+	"table":  pkg.WriteResultTable,
+	"csv":    pkg.WriteResultCsv,
+	"json":   pkg.WriteResultJson,
+	"ndjson": pkg.WriteResultNdjson,
+}
+
+// resultExtensions are the extensions of --out which select a result format over a graph format
+var resultExtensions map[string]string = map[string]string{
+	".txt":    "table",
+	".csv":    "csv",
+	".json":   "json",
+	".ndjson": "ndjson",
+}
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query [cypher]",
+	Short: "Run a single cypher query and write its results",
+	Long: `Run a single cypher query, given as an argument or read from a file with
+--file, and write its results, e.g.
+geno query "MATCH (c:Customer {KUNNR: $kunnr}) RETURN c.NAME1 AS name" --param kunnr=0000001
+
+Parameters are given as --param name=value, once for each. Values are read as
+json when they are valid json, such as 42, true, null or ["a","b"], and as text
+otherwise, so 0000001 stays text. A type may be given after the name, as in
+--param since:date=2024-01-31, and is one of string, integer, float, boolean,
+date, datetime or json.
+
+Queries run in a read transaction, retried should the server ask for it,
+unless --access is write. Writes run in an auto-commit transaction, which is
+never retried, so queries such as CALL {...} IN TRANSACTIONS can run; should a
+write fail part way, check what it wrote before running it again.
+
+Results are written as an aligned table, csv, json (an array of objects) or
+ndjson (one object per line), given by --format or inferred from the extension
+of --out (.txt, .csv, .json or .ndjson), and as a table by default. Nodes,
+relationships and paths are written as text in tables and csv, and as objects
+in json. With --format graph, the nodes, relationships and paths returned are
+written as a graph file instead, in any of the formats geno convert writes,
+given by --graph-format or inferred from the extension of --out, and json by
+default.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var cypher string
+		switch {
+		case len(args) == 1 && queryFile != "":
+			return errors.New("give the query either as an argument or with --file, not both")
+		case len(args) == 1:
+			cypher = args[0]
+		case queryFile != "":
+			raw, err := os.ReadFile(queryFile)
+			if err != nil {
+				return err
+			}
+			cypher = string(raw)
+		}
+		if strings.TrimSpace(cypher) == "" {
+			return errors.New("a query must be given as an argument or with --file")
+		}
+
+		params, err := pkg.ParseQueryParams(queryParams)
+		if err != nil {
+			return err
+		}
+		var mode neo4j.AccessMode
+		switch strings.ToLower(queryAccess) {
+		case "read":
+			mode = neo4j.AccessModeRead
+		case "write":
+			mode = neo4j.AccessModeWrite
+		default:
+			return fmt.Errorf("access mode %s is not read or write", queryAccess)
+		}
+
+		format := strings.ToLower(queryFormat)
+		if format == "" && exportPath != "" {
+			ext := strings.ToLower(filepath.Ext(exportPath))
+			if result, found := resultExtensions[ext]; found {
+				format = result
+			} else if _, found := formatExtensions[ext]; found {
+				format = "graph"
+			}
+		}
+		if format == "" {
+			format = "table"
+		}
+		var writeGraphFile func(io.Writer, pkg.Graph) error
+		if format == "graph" {
+			graphFormat := queryGraphFormat
+			if graphFormat == "" && exportPath != "" {
+				if graphFormat, err = formatOf("", exportPath, "graph-format"); err != nil {
+					return err
+				}
+			} else if graphFormat == "" {
+				graphFormat = "json"
+			}
+			var found bool
+			if writeGraphFile, found = graphWriters[graphFormat]; !found {
+				return fmt.Errorf("%s cannot be written; choose from %s", graphFormat, formatNames(graphWriters))
+			}
+		} else if _, found := resultWriters[format]; !found {
+			return fmt.Errorf("results cannot be written as %s; choose from %s or graph", format, formatNames(resultWriters))
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		keys, records, summary, err := driver.RunQuery(cfg.Database, cypher, params, mode)
+		if err != nil {
+			return err
+		}
+
+		if format == "graph" {
+			graph := pkg.GraphFromRecords(records)
+			if len(graph.Nodes) == 0 && len(graph.Relationships) == 0 {
+				return errors.New("the query returned no nodes or relationships to write as a graph")
+			}
+			if err := writeGraph(graph, writeGraphFile); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "query report:", len(graph.Nodes), "nodes and", len(graph.Relationships), "relationships written")
+		} else {
+			var out io.Writer = os.Stdout
+			if exportPath != "" {
+				f, err := os.Create(exportPath)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			if err := resultWriters[format](out, keys, records); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "query report:", len(records), "records returned")
+		}
+
+		if counters := summary.Counters(); counters.ContainsUpdates() {
+			fmt.Fprintln(os.Stderr, "\tnodes created:", counters.NodesCreated(), "deleted:", counters.NodesDeleted())
+			fmt.Fprintln(os.Stderr, "\trelationships created:", counters.RelationshipsCreated(), "deleted:", counters.RelationshipsDeleted())
+			fmt.Fprintln(os.Stderr, "\tproperties set:", counters.PropertiesSet())
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	addConnectionFlags(queryCmd, "Run the query against this database")
+	queryCmd.Flags().StringVarP(&queryFile, "file", "f", "", "file holding the query to run")
+	queryCmd.Flags().StringArrayVarP(&queryParams, "param", "p", nil, "query parameter as name=value or name:type=value")
+	queryCmd.Flags().StringVar(&queryAccess, "access", "read", "access mode of the query: read, in a retried transaction, or write, auto-committed")
+	queryCmd.Flags().StringVar(&queryFormat, "format", "", "format to write: "+formatNames(resultWriters)+" or graph (default inferred from --out, or table)")
+	queryCmd.Flags().StringVar(&queryGraphFormat, "graph-format", "", "format to write graphs in: "+formatNames(graphWriters)+" (default inferred from --out, or json)")
+	queryCmd.Flags().StringVarP(&exportPath, "out", "o", "", "path of the file to write (default is stdout)")
+	queryCmd.Flags().StringToStringVar(&exportCaptions, "caption", nil, "property to caption the nodes of each label with, e.g. Customer=NAME1")
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/Viking2012/geno/pkg"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreBatchSize int
+	restoreConflict  string
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <directory>",
+	Short: "Load a backup written by geno export all",
+	Long: `Load a backup written by geno export all into a database, e.g.
+geno restore backups/2026-10-19 -d PRD
+
+The checksum of every file is verified before anything is written. The
+constraints recorded with the backup are then created, unless they already
+exist or are of a type the server cannot hold, such as property type
+constraints before neo4j 5.9, which are left out with a warning. The files are loaded one at a time, node files first, --batch-size
+elements at a time, so memory use does not grow with the backup.
+
+Every element of the backup is restored as exactly one element. Nodes and
+relationships are merged on the properties of their key and uniqueness
+constraints, so existing elements with the same keys are reused. Those of
+labels and types without such constraints are created, even when they are
+identical to other elements, so restoring a backup twice duplicates them.
+Existing elements keep their values unless --conflict is overwrite, and with
+fail a batch holding an element with other values stops the restore.
+
+While restoring, each element holds the id it was backed up with in the
+property ` + geno.RestoreIdProperty + `, indexed for each label and type, by which
+relationships find their nodes. The property and indexes are removed once every
+file is loaded; should a restore stop before then, they are left in place.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var policy geno.MergePolicy = geno.MergePolicy(restoreConflict)
+		switch policy {
+		case geno.MERGE_KEEP_SURVIVOR, geno.MERGE_OVERWRITE, geno.MERGE_FAIL:
+		default:
+			return fmt.Errorf("conflict policy %s is not one of keep, overwrite or fail", restoreConflict)
+		}
+		if restoreBatchSize < 1 {
+			return errors.New("the batch size must be at least 1")
+		}
+
+		b, err := pkg.ReadBackup(args[0])
+		if err != nil {
+			return err
+		}
+
+		var (
+			files  []pkg.BackupFile
+			labels []string
+			types  []string
+		)
+		for _, file := range b.Files {
+			if file.Kind == "node" {
+				if file.Name == "" {
+					fmt.Fprintln(os.Stderr, "warning:", file.Count, "nodes without labels in", file.Path, "cannot be found again and are skipped, along with their relationships")
+					continue
+				}
+				files = append(files, file)
+				labels = append(labels, file.Name)
+			}
+		}
+		for _, file := range b.Files {
+			if file.Kind != "node" {
+				files = append(files, file)
+				types = append(types, file.Name)
+			}
+		}
+
+		driver, err := newDriver()
+		if err != nil {
+			return err
+		}
+		defer driver.Close()
+
+		var statements []string = b.Constraints
+		if version, err := driver.GetServerVersion(); err == nil {
+			var unsupported []string
+			statements, unsupported = version.SupportedStatements(b.Constraints)
+			for _, statement := range unsupported {
+				fmt.Fprintln(os.Stderr, "warning: neo4j", version, "cannot hold a constraint of the backup, which is left out:", statement)
+			}
+		}
+		if len(statements) > 0 {
+			script, err := geno.ParseScript(strings.NewReader(strings.Join(statements, ";\n") + ";"))
+			if err != nil {
+				return err
+			}
+			if err := driver.RunScript(cfg.Database, script, nil); err != nil {
+				return fmt.Errorf("constraints could not be created: %w", err)
+			}
+		}
+		c, err := loadConstraints(&driver, true)
+		if err != nil {
+			return err
+		}
+		q := geno.NewQuery(&driver, &c)
+		if err := driver.CreateRestoreIndexes(cfg.Database, labels, types); err != nil {
+			return fmt.Errorf("restore indexes could not be created: %w", err)
+		}
+
+		var nodes, rels, nodesCreated, relsCreated int
+		for _, file := range files {
+			bar := progressbar.Default(int64(file.Count), file.Path)
+			err := pkg.ReadBackupFile(args[0], file, restoreBatchSize,
+				func(batch []geno.Node) error {
+					nodes += len(batch)
+					return q.RestoreNodes(cfg.Database, batch, policy, restoreBatchSize, func(merged []geno.Node, summary neo4j.ResultSummary) {
+						bar.Add(len(merged))
+						nodesCreated += summary.Counters().NodesCreated()
+					})
+				},
+				func(batch []geno.Relationship) error {
+					rels += len(batch)
+					return q.RestoreRelationships(cfg.Database, batch, policy, restoreBatchSize, func(merged []geno.Relationship, summary neo4j.ResultSummary) {
+						bar.Add(len(merged))
+						relsCreated += summary.Counters().RelationshipsCreated()
+					})
+				})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := driver.ClearRestoreIds(cfg.Database, labels, types, restoreBatchSize); err != nil {
+			return fmt.Errorf("backup ids could not be removed: %w", err)
+		}
+		if err := driver.DropRestoreIndexes(cfg.Database, labels, types); err != nil {
+			return fmt.Errorf("restore indexes could not be dropped: %w", err)
+		}
+
+		fmt.Println("restore report:", len(statements), "constraints ensured,", nodesCreated, "of", nodes, "nodes and", relsCreated, "of", rels, "relationships created")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	addConnectionFlags(restoreCmd, "Restore into this database")
+	restoreCmd.Flags().IntVarP(&restoreBatchSize, "batch-size", "b", 1000, "number of nodes or relationships merged by each statement")
+	restoreCmd.Flags().StringVar(&restoreConflict, "conflict", string(geno.MERGE_KEEP_SURVIVOR), "handling of existing elements with other values: keep, overwrite or fail")
+}
@@ -25,7 +25,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Viking2012/geno/geno"
 	"github.com/Viking2012/geno/pkg"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -102,4 +104,49 @@ func initConfig() {
 	if err != nil {
 		panic("Could not unmarshal the provided config file")
 	}
+	cobra.CheckErr(cfg.ValidateConstraints())
+}
+
+// addConnectionFlags registers the flags used to locate a database and the user connecting to it
+func addConnectionFlags(cmd *cobra.Command, databaseUsage string) {
+	cmd.PersistentFlags().StringVarP(&cfg.Database, "database", "d", cfg.Database, databaseUsage)
+
+	cmd.PersistentFlags().StringVarP(&cfg.Server, "server", "s", cfg.Server, "Location of database in format: <SERVER>:<PORT>")
+
+	cmd.PersistentFlags().StringVarP(&cfg.User, "username", "u", cfg.User, "Username used to connect to the server")
+}
+
+// newDriver connects to the configured server with the configured credentials
+func newDriver() (geno.Driver, error) {
+	return newDriverFor(&cfg)
+}
+
+// newDriverFor connects to the server of another configuration, such as that of a profile, with its credentials.
+// Changes are tracked by the timestamp properties of the configuration.
+func newDriverFor(c *pkg.Configuration) (geno.Driver, error) {
+	driver, err := geno.NewDriver("neo4j://"+c.Server, neo4j.BasicAuth(c.User, c.GetPassword(), ""))
+	if err != nil {
+		return driver, err
+	}
+	driver.TrackChanges(c.Timestamps)
+	return driver, nil
+}
+
+// loadConstraints reads constraints from the database when requested, otherwise from the configuration file.
+// Constraints neo4j cannot hold are always taken from the configuration file.
+func loadConstraints(driver *geno.Driver, refresh bool) (geno.Constraints, error) {
+	return loadDatabaseConstraints(driver, cfg.Database, refresh)
+}
+
+// loadDatabaseConstraints reads the constraints of a database other than the configured one, as loadConstraints does
+func loadDatabaseConstraints(driver *geno.Driver, database string, refresh bool) (geno.Constraints, error) {
+	if refresh {
+		c, err := driver.GetConstraints(database)
+		if err != nil {
+			return c, err
+		}
+		c.CopyNonNative(cfg.Constraints[database])
+		return c, nil
+	}
+	return cfg.Constraints[database], nil
 }
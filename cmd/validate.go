@@ -0,0 +1,69 @@
+/*
+Copyright © 2022 Alexander Orban <alexander.orban@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check a file of nodes and relationships against the configured constraints",
+	Long: `Check a json file of nodes and relationships against the constraints
+configured for a database without connecting to it. Every violation of a
+constraint which can be checked offline is reported, including property types,
+property value rules and the labels allowed at either end of each relationship
+type.`,
+	// validation happens offline, so no server or credentials are needed
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Database == "" {
+			return errors.New("database name must be provided either via a configuration file (--config) or via the database flag (-d, --database)")
+		}
+		graph, err := readGraphInputs("json")
+		if err != nil {
+			return err
+		}
+
+		c := cfg.Constraints[cfg.Database]
+		violations := validateGraph(graph, &c)
+		for _, v := range violations {
+			fmt.Println("\t" + v.Error())
+		}
+		fmt.Println("validation report:", len(graph.Nodes), "nodes and", len(graph.Relationships), "relationships checked,", len(violations), "violation(s) found")
+		if len(violations) > 0 {
+			return fmt.Errorf("%d violation(s) were found", len(violations))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringVarP(&cfg.Database, "database", "d", cfg.Database, "Check against the constraints configured for this database")
+	validateCmd.Flags().StringVarP(&fPath, "filepath", "f", "", inputUsage("json files"))
+}
@@ -0,0 +1,247 @@
+package geno
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// unconstrainedProps returns the properties which are not among the constraint keys, which are those set
+// (rather than matched on) by a merge
+func unconstrainedProps(props map[string]any, constraints []string) map[string]any {
+	var unconstrained map[string]any = make(map[string]any, len(props))
+	for key, val := range props {
+		unconstrained[key] = val
+	}
+	for _, key := range constraints {
+		delete(unconstrained, key)
+	}
+	return unconstrained
+}
+
+// onMatchSet builds the clause which overwrites existing values of the unconstrained properties of a merge
+func onMatchSet(props map[string]any, variable string, ref func(key string) string) string {
+	if len(props) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ON MATCH SET %s.", variable) + strings.Join(templatizeRefs(props, "=", ref), fmt.Sprintf(", %s.", variable)) + "\n"
+}
+
+// conflictsWhere builds the condition which holds when an existing element has a value differing from the one merged.
+// Missing values are not conflicts, as merging only fills them in.
+func conflictsWhere(props map[string]any, variable string, ref func(key string) string) string {
+	return fmt.Sprintf("WHERE %s.", variable) + strings.Join(templatizeRefs(props, " <> ", ref), fmt.Sprintf(" OR %s.", variable)) + "\n"
+}
+
+// relationshipKeys builds the property map matching a relationship on its constraint keys, as its merge does
+func relationshipKeys(props map[string]any, keys []string) string {
+	var matched map[string]any = make(map[string]any, len(keys))
+	for _, key := range keys {
+		if val, found := props[key]; found {
+			matched[key] = val
+		}
+	}
+	if len(matched) == 0 {
+		return ""
+	}
+	return " {" + strings.Join(templatizeRefs(matched, ":", rowRef("row.properties")), ", ") + "}"
+}
+
+// mergeBatch runs a merge over every row of a batch within a single transaction. With the MERGE_FAIL policy, conflicts
+// is first run over the same rows, and nothing is merged if it counts any existing element with other values.
+func (q *Query) mergeBatch(database, merge, conflicts string, rows []any, policy MergePolicy, what string) (neo4j.ResultSummary, error) {
+	session := q.d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	defer session.Close()
+
+	summary, err := session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		return mergeRows(tx, merge, conflicts, rows, policy, what)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary.(neo4j.ResultSummary), nil
+}
+
+// mergeRows runs a merge over every row of a batch within a transaction the caller has opened, as mergeBatch does
+func mergeRows(tx neo4j.Transaction, merge, conflicts string, rows []any, policy MergePolicy, what string) (neo4j.ResultSummary, error) {
+	var params map[string]any = map[string]any{"rows": rows}
+	if policy == MERGE_FAIL && conflicts != "" {
+		result, err := tx.Run("UNWIND $rows AS row\n"+conflicts+"RETURN count(*) AS conflicts", params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := result.Single()
+		if err != nil {
+			return nil, err
+		}
+		if count, _ := record.Get("conflicts"); count != nil && count.(int64) > 0 {
+			return nil, fmt.Errorf("%w: %d of %d %s already exist with other values", errMergeConflict, count, len(rows), what)
+		}
+	}
+	result, err := tx.Run("UNWIND $rows AS row\n"+merge, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Consume()
+}
+
+// checkPolicy rejects merge policies other than those understood by MergeNodes and MergeRelationships
+func checkPolicy(policy MergePolicy) error {
+	switch policy {
+	case MERGE_KEEP_SURVIVOR, MERGE_OVERWRITE, MERGE_FAIL:
+		return nil
+	default:
+		return fmt.Errorf("merge policy %s is not supported", policy)
+	}
+}
+
+// batchesOf splits the indexes of count elements into batches of up to size elements sharing a shape,
+// in order of the first appearance of each shape
+func batchesOf(count, size int, shape func(i int) string) [][]int {
+	var (
+		order   []string
+		grouped map[string][]int = make(map[string][]int)
+		batches [][]int
+	)
+	for i := 0; i < count; i++ {
+		key := shape(i)
+		if _, found := grouped[key]; !found {
+			order = append(order, key)
+		}
+		grouped[key] = append(grouped[key], i)
+	}
+	for _, key := range order {
+		members := grouped[key]
+		for size > 0 && len(members) > size {
+			batches = append(batches, members[:size])
+			members = members[size:]
+		}
+		batches = append(batches, members)
+	}
+	return batches
+}
+
+func propertyShape(props map[string]any) string {
+	var keys []string = make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// MergeNodes merges nodes with one UNWIND statement per batch of up to batchSize nodes sharing labels and property keys.
+// The policy decides what happens to nodes which already exist: with MERGE_KEEP_SURVIVOR their values are kept and
+// properties are only set on creation, as MergeNode does; with MERGE_OVERWRITE the merged values replace theirs; and
+// with MERGE_FAIL a batch holding any node whose existing values differ is not written and an error is returned.
+// When the driver tracks changes, nodes are stamped with the time they are created or their values changed.
+// done is called with every batch once it is merged.
+func (q *Query) MergeNodes(database string, nodes []Node, policy MergePolicy, batchSize int, done func(batch []Node, summary neo4j.ResultSummary)) error {
+	return q.mergeNodes(database, nodes, policy, batchSize, q.c.GetNodeConstraints, "", done)
+}
+
+// mergeNodes merges nodes as MergeNodes does, on the keys returned for each batch, adding always to every merge
+func (q *Query) mergeNodes(database string, nodes []Node, policy MergePolicy, batchSize int, keysOf func(n *Node) []string, always string, done func(batch []Node, summary neo4j.ResultSummary)) error {
+	if err := checkPolicy(policy); err != nil {
+		return err
+	}
+	batches := batchesOf(len(nodes), batchSize, func(i int) string {
+		return nodes[i].String() + "|" + propertyShape(nodes[i].Properties)
+	})
+	for _, indexes := range batches {
+		var (
+			first       Node     = nodes[indexes[0]]
+			constraints []string = keysOf(&first)
+			settable    map[string]any
+			merge       string = first.ToCypherUnwindMerge(constraints, "row")
+			conflicts   string
+			batch       []Node = make([]Node, len(indexes))
+			rows        []any  = make([]any, len(indexes))
+		)
+		settable = unconstrainedProps(first.Properties, constraints)
+		delete(settable, RestoreIdProperty) // set on creation, but never compared or overwritten
+		merge += stampMerge("n", q.d.timestampOf(database, first.Labels...), settable, rowRef("row"), policy == MERGE_OVERWRITE)
+		if policy == MERGE_OVERWRITE {
+			merge += onMatchSet(settable, "n", rowRef("row"))
+		}
+		merge += always
+		if len(settable) > 0 {
+			conflicts = first.toCypherMatch(constraints, "n", rowRef("row")) + conflictsWhere(settable, "n", rowRef("row"))
+		}
+		for i, index := range indexes {
+			batch[i] = nodes[index]
+			rows[i] = nodes[index].Properties
+		}
+
+		summary, err := q.mergeBatch(database, merge, conflicts, rows, policy, ":"+first.String()+" nodes")
+		if err != nil {
+			return err
+		}
+		if done != nil {
+			done(batch, summary)
+		}
+	}
+	return nil
+}
+
+// MergeRelationships merges relationships between existing nodes with one UNWIND statement per batch of up to batchSize
+// relationships sharing a type, endpoint labels and property keys. Relationships are merged on the properties of their
+// uniqueness, key and existence constraints, so that parallel relationships of a type stay distinct. The policy decides what happens to relationships
+// which already exist, as it does for MergeNodes, and they are stamped as nodes are. done is called with every batch once it is merged.
+func (q *Query) MergeRelationships(database string, rels []Relationship, policy MergePolicy, batchSize int, done func(batch []Relationship, summary neo4j.ResultSummary)) error {
+	return q.mergeRelationships(database, rels, policy, batchSize, q.c.GetNodeConstraints, q.c.GetRelationshipConstraints, done)
+}
+
+// mergeRelationships merges relationships as MergeRelationships does, finding their endpoints by the keys returned
+// for each node and merging them on those returned for each relationship
+func (q *Query) mergeRelationships(database string, rels []Relationship, policy MergePolicy, batchSize int, nodeKeysOf func(n *Node) []string, keysOf func(r *Relationship) []string, done func(batch []Relationship, summary neo4j.ResultSummary)) error {
+	if err := checkPolicy(policy); err != nil {
+		return err
+	}
+	batches := batchesOf(len(rels), batchSize, func(i int) string {
+		return rels[i].Start.String() + "|" + rels[i].Label + "|" + rels[i].End.String() + "|" + propertyShape(rels[i].Properties)
+	})
+	for _, indexes := range batches {
+		var (
+			first            Relationship   = rels[indexes[0]]
+			merge, conflicts string         = q.relationshipMerge(database, &first, policy, nodeKeysOf(&first.Start), nodeKeysOf(&first.End), keysOf(&first))
+			batch            []Relationship = make([]Relationship, len(indexes))
+			rows             []any          = make([]any, len(indexes))
+		)
+		for i, index := range indexes {
+			batch[i] = rels[index]
+			rows[i] = rels[index].UnwindRow(nodeKeysOf(&rels[index].Start), nodeKeysOf(&rels[index].End))
+		}
+
+		summary, err := q.mergeBatch(database, merge, conflicts, rows, policy, ":"+first.String()+" relationships")
+		if err != nil {
+			return err
+		}
+		if done != nil {
+			done(batch, summary)
+		}
+	}
+	return nil
+}
+
+// relationshipMerge builds the statement merging a batch of relationships shaped as first, whose endpoints are found
+// by the left and right keys and which are merged on their own keys, and the statement finding those which already
+// exist with other values
+func (q *Query) relationshipMerge(database string, first *Relationship, policy MergePolicy, left, right, keys []string) (merge, conflicts string) {
+	var settable map[string]any = unconstrainedProps(first.Properties, keys)
+	delete(settable, RestoreIdProperty)
+	merge = first.ToCypherUnwindMerge(left, right, keys, "row")
+	merge += stampMerge("r", q.d.timestampOf(database, first.Label), settable, rowRef("row.properties"), policy == MERGE_OVERWRITE)
+	if policy == MERGE_OVERWRITE {
+		merge += onMatchSet(settable, "r", rowRef("row.properties"))
+	}
+	if len(settable) > 0 {
+		conflicts = first.Start.toCypherMatch(left, "left", rowRef("row.left")) +
+			first.End.toCypherMatch(right, "right", rowRef("row.right")) +
+			"MATCH (left)-[r:" + first.String() + relationshipKeys(first.Properties, keys) + "]-(right)\n" +
+			conflictsWhere(settable, "r", rowRef("row.properties"))
+	}
+	return merge, conflicts
+}
@@ -0,0 +1,45 @@
+package geno
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_batchesOf(t *testing.T) {
+	shapes := []string{"a", "b", "a", "a", "b", "a"}
+	got := batchesOf(len(shapes), 2, func(i int) string { return shapes[i] })
+	want := [][]int{{0, 2}, {3, 5}, {1, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted batches %v, but got %v", want, got)
+	}
+
+	got = batchesOf(len(shapes), 0, func(i int) string { return shapes[i] })
+	want = [][]int{{0, 2, 3, 5}, {1, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted a single batch per shape without a batch size, %v, but got %v", want, got)
+	}
+}
+
+func Test_policyClauses(t *testing.T) {
+	props := unconstrainedProps(map[string]any{"KUNNR": "1", "NAME1": "ACME", "ORT01": "Berlin"}, []string{"KUNNR"})
+	if _, found := props["KUNNR"]; found || len(props) != 2 {
+		t.Fatalf("wanted only the unconstrained properties, but got %v", props)
+	}
+
+	want := "ON MATCH SET n.NAME1=row.`NAME1`, n.ORT01=row.`ORT01`\n"
+	if got := onMatchSet(props, "n", rowRef("row")); got != want {
+		t.Errorf("wanted %q, but got %q", want, got)
+	}
+	if got := onMatchSet(map[string]any{}, "n", rowRef("row")); got != "" {
+		t.Errorf("wanted no clause without properties to set, but got %q", got)
+	}
+
+	want = "WHERE r.NAME1 <> row.properties.`NAME1` OR r.ORT01 <> row.properties.`ORT01`\n"
+	if got := conflictsWhere(props, "r", rowRef("row.properties")); got != want {
+		t.Errorf("wanted %q, but got %q", want, got)
+	}
+
+	if err := checkPolicy("merge"); err == nil {
+		t.Error("wanted an unknown policy to be rejected")
+	}
+}
@@ -0,0 +1,309 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+type Direction string
+type CardinalityPolicy string
+
+const (
+	CARDINALITY_CONSTRAINT ConstraintType = "CARDINALITY"
+	// Directions
+	OUTGOING Direction = "OUTGOING"
+	INCOMING Direction = "INCOMING"
+	// Cardinality Policies
+	CARDINALITY_REJECT  CardinalityPolicy = "reject"  // the new relationship is not written
+	CARDINALITY_REPLACE CardinalityPolicy = "replace" // the node's other relationships of the type are deleted first
+)
+
+var errCardinalityRejected error = errors.New("the relationship would exceed the maximum degree of a cardinality constraint")
+
+// CardinalityConstraint bounds the number of relationships of a type every node of a label has in one direction,
+// e.g. every Customer has at most one outgoing HAS_TAXCODE. Without a Max the degree is unbounded, while a Max of zero
+// allows none.
+type CardinalityConstraint struct {
+	Label       string            `json:"Label" yaml:"Label"`
+	Type        string            `json:"Type" yaml:"Type"`
+	Direction   Direction         `json:"Direction,omitempty" yaml:"Direction,omitempty"` // defaults to OUTGOING
+	Min         int               `json:"Min,omitempty" yaml:"Min,omitempty"`
+	Max         *int              `json:"Max,omitempty" yaml:"Max,omitempty"`
+	OnViolation CardinalityPolicy `json:"OnViolation,omitempty" yaml:"OnViolation,omitempty"` // defaults to reject
+}
+
+func (c CardinalityConstraint) direction() Direction {
+	if c.Direction == "" {
+		return OUTGOING
+	}
+	return Direction(strings.ToUpper(string(c.Direction)))
+}
+
+func (c CardinalityConstraint) policy() CardinalityPolicy {
+	if c.OnViolation == "" {
+		return CARDINALITY_REJECT
+	}
+	return CardinalityPolicy(strings.ToLower(string(c.OnViolation)))
+}
+
+// Validate returns an error when the constraint's direction or policy is not one geno understands, regardless of
+// case, or its bounds cannot be met
+func (c CardinalityConstraint) Validate() error {
+	switch c.direction() {
+	case OUTGOING, INCOMING:
+	default:
+		return fmt.Errorf("%s %s: direction %s is not one of OUTGOING or INCOMING", c.Label, c.Type, c.Direction)
+	}
+	switch c.policy() {
+	case CARDINALITY_REJECT, CARDINALITY_REPLACE:
+	default:
+		return fmt.Errorf("%s %s: policy %s is not one of reject or replace", c.Label, c.Type, c.OnViolation)
+	}
+	if c.Min < 0 || (c.Max != nil && (*c.Max < 0 || c.Min > *c.Max)) {
+		return fmt.Errorf("%s %s: bounds %d to %s cannot be met", c.Label, c.Type, c.Min, c.maxText())
+	}
+	if c.Max != nil && *c.Max == 0 && c.policy() == CARDINALITY_REPLACE {
+		return fmt.Errorf("%s %s: a maximum of 0 cannot be kept by replacing relationships", c.Label, c.Type)
+	}
+	return nil
+}
+
+// pattern returns the relationship pattern from n to the far end of the constraint
+func (c CardinalityConstraint) pattern(n, r, other string) string {
+	if c.direction() == INCOMING {
+		return fmt.Sprintf("(%s)<-[%s:%s]-(%s)", n, r, escapeName(c.Type), other)
+	}
+	return fmt.Sprintf("(%s)-[%s:%s]->(%s)", n, r, escapeName(c.Type), other)
+}
+
+// maxText writes the maximum, or unbounded when there is none
+func (c CardinalityConstraint) maxText() string {
+	if c.Max == nil {
+		return "unbounded"
+	}
+	return fmt.Sprint(*c.Max)
+}
+
+func (c CardinalityConstraint) String() string {
+	var bounds string
+	switch {
+	case c.Max == nil:
+		bounds = fmt.Sprintf("at least %d", c.Min)
+	case *c.Max == 0:
+		bounds = "no"
+	case c.Min == *c.Max:
+		bounds = fmt.Sprintf("exactly %d", c.Min)
+	case c.Min == 0:
+		bounds = fmt.Sprintf("at most %d", *c.Max)
+	default:
+		bounds = fmt.Sprintf("between %d and %d", c.Min, *c.Max)
+	}
+	return fmt.Sprintf("every %s has %s %s %s", c.Label, bounds, strings.ToLower(string(c.direction())), c.Type)
+}
+
+// GetCardinalityConstraints returns the constraints which bound either endpoint of the relationship,
+// along with the variable ("left" or "right") the bounded endpoint has in relationship queries
+func (constraints *Constraints) GetCardinalityConstraints(r *Relationship) (applicable []CardinalityConstraint, anchors []string) {
+	hasLabel := func(n Node, label string) bool {
+		for _, l := range n.Labels {
+			if l == label {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range constraints.Cardinality {
+		if c.Type != r.Label {
+			continue
+		}
+		if c.direction() == OUTGOING && hasLabel(r.Start, c.Label) {
+			applicable = append(applicable, c)
+			anchors = append(anchors, "left")
+		}
+		if c.direction() == INCOMING && hasLabel(r.End, c.Label) {
+			applicable = append(applicable, c)
+			anchors = append(anchors, "right")
+		}
+	}
+	return applicable, anchors
+}
+
+// BoundsDegree reports whether a cardinality constraint limits the maximum degree of either endpoint of the
+// relationship, so that merging it could exceed the maximum
+func (q *Query) BoundsDegree(r *Relationship) bool {
+	applicable, _ := q.c.GetCardinalityConstraints(r)
+	for _, c := range applicable {
+		if c.Max != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeBoundedRelationship checks that merging a relationship would not exceed the maximum degree of either endpoint
+// and merges it, as MergeRelationships does, within the same transaction. Constraints with the replace policy delete
+// the endpoint's other relationships of the type, which are only deleted should the relationship be merged, while
+// those with the reject policy return a violation and nothing is written. Minimum degrees cannot be enforced one
+// relationship at a time and are left to Driver.AuditCardinality. When the driver tracks changes, the relationships
+// deleted leave tombstones. done is called once the relationship is merged.
+//
+// Relationships of the same node must be merged one after another for each check to count those merged before it,
+// and imports running at the same time may together exceed the maximum. Such imports should not run concurrently;
+// Driver.AuditCardinality finds any node which exceeds it.
+func (q *Query) MergeBoundedRelationship(database string, r Relationship, policy MergePolicy, done func(batch []Relationship, summary neo4j.ResultSummary)) ([]Violation, error) {
+	if err := checkPolicy(policy); err != nil {
+		return nil, err
+	}
+	var (
+		left, right      []string = q.c.GetNodeConstraints(&r.Start), q.c.GetNodeConstraints(&r.End)
+		merge, conflicts string   = q.relationshipMerge(database, &r, policy, left, right, q.c.GetRelationshipConstraints(&r))
+		rows             []any    = []any{r.UnwindRow(left, right)}
+		violations       []Violation
+		summary          neo4j.ResultSummary
+	)
+
+	session := q.d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	defer session.Close()
+
+	_, err := session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		var txErr error
+		if violations, txErr = q.enforceCardinality(tx, database, r); txErr != nil {
+			return nil, txErr
+		}
+		if len(violations) > 0 {
+			return nil, errCardinalityRejected // rolls back anything replaced for another constraint
+		}
+		summary, txErr = mergeRows(tx, merge, conflicts, rows, policy, ":"+r.String()+" relationships")
+		return nil, txErr
+	})
+	if errors.Is(err, errCardinalityRejected) {
+		return violations, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if done != nil {
+		done([]Relationship{r}, summary)
+	}
+	return nil, nil
+}
+
+// enforceCardinality counts the relationships each endpoint of r already has for every constraint bounding its
+// maximum degree, replacing them or returning violations according to the constraint's policy
+func (q *Query) enforceCardinality(tx neo4j.Transaction, database string, r Relationship) ([]Violation, error) {
+	applicable, anchors := q.c.GetCardinalityConstraints(&r)
+	if len(applicable) == 0 {
+		return nil, nil
+	}
+
+	var violations []Violation
+
+	leftQuery, leftParams := r.Start.ToCypherMatch(q.c.GetNodeConstraints(&r.Start), "left")
+	rightQuery, rightParams := r.End.ToCypherMatch(q.c.GetNodeConstraints(&r.End), "right")
+	params := make(map[string]any, len(leftParams)+len(rightParams))
+	for key, val := range leftParams {
+		params[key] = val
+	}
+	for key, val := range rightParams {
+		params[key] = val
+	}
+
+	for i, c := range applicable {
+		if c.Max == nil {
+			continue
+		}
+		var (
+			other  string = "right"
+			anchor Node   = r.Start
+		)
+		if anchors[i] == "right" {
+			other = "left"
+			anchor = r.End
+		}
+		existing := c.pattern(anchors[i], "existing", "x")
+
+		result, err := tx.Run(leftQuery+rightQuery+"OPTIONAL MATCH "+existing+" WHERE x <> "+other+"\nRETURN count(existing) AS degree", params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := result.Single()
+		if err != nil {
+			return nil, err
+		}
+		rawDegree, _ := record.Get("degree")
+		degree, _ := rawDegree.(int64)
+		if degree < int64(*c.Max) {
+			continue
+		}
+
+		switch c.policy() {
+		case CARDINALITY_REPLACE:
+			if q.d.tracksChanges(database) {
+				err = recordTombstones(tx, leftQuery+rightQuery+"MATCH "+existing+" WHERE x <> "+other+"\nRETURN existing AS deleted, startNode(existing) AS start, endNode(existing) AS end", params)
+				if err != nil {
+					return nil, err
+				}
+			}
+			_, err = tx.Run(leftQuery+rightQuery+"MATCH "+existing+" WHERE x <> "+other+"\nDELETE existing", params)
+			if err != nil {
+				return nil, err
+			}
+		case CARDINALITY_REJECT:
+			violations = append(violations, Violation{
+				Constraint: CARDINALITY_CONSTRAINT,
+				Label:      c.Label,
+				Message:    fmt.Sprintf("%s %v already has %d %s relationship(s), but %s", anchor, anchor.Properties, degree, c.Type, c),
+			})
+		default:
+			return nil, fmt.Errorf("cardinality policy %s is not supported", c.OnViolation)
+		}
+	}
+	return violations, nil
+}
+
+// AuditCardinality finds up to limit nodes which violate the constraint, identifying each by its key properties
+func (d *Driver) AuditCardinality(database string, c CardinalityConstraint, keys []string, limit int) ([]Violation, error) {
+	if c.Label == "" || c.Type == "" {
+		return nil, errors.New("a cardinality constraint must have a label and a relationship type")
+	}
+	var (
+		q          strings.Builder = strings.Builder{}
+		projection []string        = make([]string, len(keys))
+		violations []Violation
+	)
+	for i, key := range keys {
+		projection[i] = "." + escapeName(key)
+	}
+
+	q.WriteString("MATCH (n:")
+	q.WriteString(escapeName(c.Label))
+	q.WriteString(")\nWITH n, size([")
+	q.WriteString(c.pattern("n", "", ""))
+	q.WriteString(" | 1]) AS degree\nWHERE degree < $min OR ($max >= 0 AND degree > $max)\n")
+	q.WriteString("RETURN id(n) AS id, degree, n {")
+	q.WriteString(strings.Join(projection, ", "))
+	q.WriteString("} AS key\nLIMIT $limit")
+
+	var max int = -1 // unbounded
+	if c.Max != nil {
+		max = *c.Max
+	}
+	records, err := d.ReadRecords(database, q.String(), map[string]any{"min": c.Min, "max": max, "limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		id, _ := record.Get("id")
+		degree, _ := record.Get("degree")
+		key, _ := record.Get("key")
+		violations = append(violations, Violation{
+			Constraint: CARDINALITY_CONSTRAINT,
+			Label:      c.Label,
+			Message:    fmt.Sprintf("node %v %v has %v %s relationship(s), but %s", id, key, degree, c.Type, c),
+		})
+	}
+	return violations, nil
+}
@@ -0,0 +1,70 @@
+package geno
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetCardinalityConstraints(t *testing.T) {
+	var (
+		one       int                   = 1
+		customer  Node                  = NewNode(1, []string{"Customer"}, map[string]any{"Key": "A"})
+		taxCode   Node                  = NewNode(2, []string{"TaxCode"}, map[string]any{"Value": "DE1"})
+		atMostOne CardinalityConstraint = CardinalityConstraint{Label: "Customer", Type: "HAS_TAXCODE", Max: &one}
+		incoming  CardinalityConstraint = CardinalityConstraint{Label: "TaxCode", Type: "HAS_TAXCODE", Direction: INCOMING, Min: 1}
+		other     CardinalityConstraint = CardinalityConstraint{Label: "Customer", Type: "HAS_PHONE", Max: &one}
+		c         Constraints           = Constraints{Cardinality: []CardinalityConstraint{atMostOne, incoming, other}}
+		rel       Relationship          = NewRelationship(3, customer, taxCode, "HAS_TAXCODE", nil)
+	)
+
+	gotConstraints, gotAnchors := c.GetCardinalityConstraints(&rel)
+	if !reflect.DeepEqual([]CardinalityConstraint{atMostOne, incoming}, gotConstraints) {
+		t.Errorf("wanted the HAS_TAXCODE constraints but got %v", gotConstraints)
+	}
+	if !reflect.DeepEqual([]string{"left", "right"}, gotAnchors) {
+		t.Errorf("wanted anchors left and right but got %v", gotAnchors)
+	}
+}
+
+func TestCardinalityConstraintString(t *testing.T) {
+	type test struct {
+		c    CardinalityConstraint
+		want string
+	}
+
+	var zero, one int = 0, 1
+	tests := []test{
+		{c: CardinalityConstraint{Label: "Customer", Type: "HAS_TAXCODE", Max: &one}, want: "every Customer has at most 1 outgoing HAS_TAXCODE"},
+		{c: CardinalityConstraint{Label: "Vendor", Type: "HAS_ADDRESS", Min: 1, Max: &one}, want: "every Vendor has exactly 1 outgoing HAS_ADDRESS"},
+		{c: CardinalityConstraint{Label: "Vendor", Type: "BLOCKED_BY", Max: &zero}, want: "every Vendor has no outgoing BLOCKED_BY"},
+		{c: CardinalityConstraint{Label: "Bank", Type: "HAS_BANK", Direction: INCOMING, Min: 1}, want: "every Bank has at least 1 incoming HAS_BANK"},
+	}
+
+	for _, tc := range tests {
+		if got := tc.c.String(); got != tc.want {
+			t.Errorf("wanted %q but got %q", tc.want, got)
+		}
+	}
+}
+
+func TestCardinalityConstraintValidate(t *testing.T) {
+	var zero, one int = 0, 1
+	c := CardinalityConstraint{Label: "Customer", Type: "HAS_TAXCODE", Direction: "outgoing", Max: &one, OnViolation: "Replace"}
+	if err := c.Validate(); err != nil {
+		t.Errorf("wanted directions and policies read regardless of case, but got %v", err)
+	}
+	if c.policy() != CARDINALITY_REPLACE {
+		t.Errorf("wanted policy replace, but got %s", c.policy())
+	}
+
+	for _, invalid := range []CardinalityConstraint{
+		{Label: "Customer", Type: "HAS_TAXCODE", Direction: "sideways"},
+		{Label: "Customer", Type: "HAS_TAXCODE", OnViolation: "ignore"},
+		{Label: "Customer", Type: "HAS_TAXCODE", Min: 2, Max: &one},
+		{Label: "Customer", Type: "HAS_TAXCODE", Max: &zero, OnViolation: "replace"},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("wanted %+v to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package geno
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// TombstoneLabel labels the nodes recording elements deleted by geno in databases whose changes are tracked
+const TombstoneLabel string = "GenoTombstone"
+
+// Timestamp names the property holding when the nodes of a label, or the relationships of a type, were last written
+type Timestamp struct {
+	Label    string `json:"Label" yaml:"Label"` // label or relationship type
+	Property string `json:"Property" yaml:"Property"`
+}
+
+// TrackChanges sets, by database, the timestamp properties of labels and relationship types. Batched merges then
+// stamp the elements they create or change with the time, and every node or relationship deleted by geno in a
+// database with any timestamp property is recorded by a TombstoneLabel node, holding its kind, id, labels or type,
+// and its properties and those of its start and end nodes as json. Database names are matched regardless of case,
+// as neo4j matches them.
+func (d *Driver) TrackChanges(timestamps map[string][]Timestamp) {
+	d.timestamps = make(map[string][]Timestamp, len(timestamps))
+	for database, ts := range timestamps {
+		d.timestamps[strings.ToLower(database)] = append(d.timestamps[strings.ToLower(database)], ts...)
+	}
+}
+
+// timestampOf returns the timestamp property of the first of the labels or types given which has one
+func (d *Driver) timestampOf(database string, names ...string) string {
+	return TimestampOf(d.timestamps[strings.ToLower(database)], names...)
+}
+
+// TimestampOf returns the property of the first of the labels or types given which has a timestamp, or an empty
+// string when none has
+func TimestampOf(timestamps []Timestamp, names ...string) string {
+	for _, name := range names {
+		for _, ts := range timestamps {
+			if ts.Label == name {
+				return ts.Property
+			}
+		}
+	}
+	return ""
+}
+
+// tracksChanges reports whether deletes within a database must leave tombstones
+func (d *Driver) tracksChanges(database string) bool {
+	return len(d.timestamps[strings.ToLower(database)]) > 0
+}
+
+// stampMerge builds the clauses stamping an element with the time when a merge creates it, or, when it overwrites
+// existing values, when any of the values set differs from those the element held. It must precede the ON MATCH SET
+// clause of the overwrite so that the existing values are compared.
+func stampMerge(variable, timestamp string, settable map[string]any, ref func(key string) string, overwrite bool) string {
+	if timestamp == "" {
+		return ""
+	}
+	var stamp string = variable + "." + escapeName(timestamp)
+	var clauses string = "ON CREATE SET " + stamp + " = datetime()\n"
+	if !overwrite || len(settable) == 0 {
+		return clauses
+	}
+	var (
+		keys    []string = make([]string, 0, len(settable))
+		changed []string = make([]string, 0, len(settable))
+	)
+	for key := range settable {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property := variable + "." + escapeName(key)
+		changed = append(changed, property+" IS NULL OR "+property+" <> "+ref(key))
+	}
+	return clauses + "ON MATCH SET " + stamp + " = CASE WHEN " + strings.Join(changed, " OR ") + " THEN datetime() ELSE " + stamp + " END\n"
+}
+
+// StampCreate builds the clause stamping an element with the time when a merge creates it, as merges which keep
+// the values of existing elements do, or nothing when timestamp is empty
+func StampCreate(variable, timestamp string) string {
+	return stampMerge(variable, timestamp, nil, nil, false)
+}
+
+// stampSet builds the clause, following a SET, which stamps an element with the time
+func stampSet(variable, timestamp string) string {
+	if timestamp == "" {
+		return ""
+	}
+	return ", " + variable + "." + escapeName(timestamp) + " = datetime()"
+}
+
+// jsonText writes a value as json, or as text when it cannot be
+func jsonText(value any) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
+
+func nodeIdentity(n neo4j.Node) string {
+	return jsonText(map[string]any{"labels": n.Labels, "properties": n.Props})
+}
+
+// recordTombstones runs a query returning the elements about to be deleted, as deleted, along with the start and end
+// nodes of relationships, as start and end, and records a tombstone for each
+func recordTombstones(tx neo4j.Transaction, cypher string, params map[string]any) error {
+	result, err := tx.Run(cypher, params)
+	if err != nil {
+		return err
+	}
+	records, err := result.Collect()
+	if err != nil {
+		return err
+	}
+	var tombstones []any
+	for _, record := range records {
+		deleted, _ := record.Get("deleted")
+		switch element := deleted.(type) {
+		case neo4j.Node:
+			tombstones = append(tombstones, map[string]any{
+				"kind": "node", "id": element.Id, "labels": element.Labels, "properties": jsonText(element.Props),
+			})
+		case neo4j.Relationship:
+			start, _ := record.Get("start")
+			end, _ := record.Get("end")
+			startNode, _ := start.(neo4j.Node)
+			endNode, _ := end.(neo4j.Node)
+			tombstones = append(tombstones, map[string]any{
+				"kind": "relationship", "id": element.Id, "type": element.Type, "properties": jsonText(element.Props),
+				"start": nodeIdentity(startNode), "end": nodeIdentity(endNode),
+			})
+		}
+	}
+	if len(tombstones) == 0 {
+		return nil
+	}
+	_, err = tx.Run("UNWIND $tombstones AS tombstone\nCREATE (t:"+escapeName(TombstoneLabel)+")\nSET t = tombstone, t.deleted = datetime()",
+		map[string]any{"tombstones": tombstones})
+	return err
+}
+
+// ChangesQuery builds the query reading every node of the labels, and relationship of the types, whose timestamp
+// property is at or after $since, along with every relationship of the nodes and the nodes at either end of them,
+// and the tombstones of elements deleted since then. Labels and types map to their timestamp properties.
+func ChangesQuery(labels, types map[string]string) string {
+	var parts []string
+	for _, label := range sortedKeys(labels) {
+		parts = append(parts, "MATCH (n:"+escapeName(label)+") WHERE n."+escapeName(labels[label])+" >= $since\n"+
+			"OPTIONAL MATCH (n)-[r]-(m)\nRETURN n, r, m")
+	}
+	for _, relType := range sortedKeys(types) {
+		parts = append(parts, "MATCH (n)-[r:"+escapeName(relType)+"]->(m) WHERE r."+escapeName(types[relType])+" >= $since\n"+
+			"RETURN n, r, m")
+	}
+	parts = append(parts, "MATCH (n:"+escapeName(TombstoneLabel)+") WHERE n.deleted >= $since\nRETURN n, null AS r, null AS m")
+	return strings.Join(parts, "\nUNION\n")
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string = make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package geno
+
+import "testing"
+
+func Test_stampMerge(t *testing.T) {
+	props := map[string]any{"NAME1": "ACME", "ORT01": "Berlin"}
+
+	if got := stampMerge("n", "", props, rowRef("row"), true); got != "" {
+		t.Errorf("wanted nothing stamped without a timestamp property, but got %s", got)
+	}
+
+	want := "ON CREATE SET n.`UPDATED_AT` = datetime()\n"
+	if got := stampMerge("n", "UPDATED_AT", props, rowRef("row"), false); got != want {
+		t.Errorf("wanted only creations stamped when existing values are kept, %s, but got %s", want, got)
+	}
+
+	want += "ON MATCH SET n.`UPDATED_AT` = CASE WHEN n.`NAME1` IS NULL OR n.`NAME1` <> row.`NAME1` OR n.`ORT01` IS NULL OR n.`ORT01` <> row.`ORT01` THEN datetime() ELSE n.`UPDATED_AT` END\n"
+	if got := stampMerge("n", "UPDATED_AT", props, rowRef("row"), true); got != want {
+		t.Errorf("wanted changed values stamped when they are overwritten, wanted\n%s\nbut got\n%s", want, got)
+	}
+}
+
+func Test_timestampOf(t *testing.T) {
+	var d Driver
+	if d.timestampOf("PRD", "Customer") != "" || d.tracksChanges("PRD") {
+		t.Error("wanted no changes tracked by default")
+	}
+	d.TrackChanges(map[string][]Timestamp{"prd": {{Label: "Vendor", Property: "CHANGED_AT"}, {Label: "Customer", Property: "UPDATED_AT"}}})
+	if got := d.timestampOf("PRD", "Bank", "Customer", "Vendor"); got != "UPDATED_AT" {
+		t.Errorf("wanted the property of the first label with one, but got %s", got)
+	}
+	if !d.tracksChanges("PRD") || d.tracksChanges("QAS") {
+		t.Error("wanted changes tracked only in the databases with timestamp properties")
+	}
+	if got := stampSet("s", "UPDATED_AT"); got != ", s.`UPDATED_AT` = datetime()" {
+		t.Errorf("wanted the stamp appended to a SET clause, but got %s", got)
+	}
+}
+
+func TestChangesQuery(t *testing.T) {
+	want := "MATCH (n:`Customer`) WHERE n.`UPDATED_AT` >= $since\nOPTIONAL MATCH (n)-[r]-(m)\nRETURN n, r, m" +
+		"\nUNION\n" +
+		"MATCH (n)-[r:`HAS_PHONE`]->(m) WHERE r.`CHANGED` >= $since\nRETURN n, r, m" +
+		"\nUNION\n" +
+		"MATCH (n:`GenoTombstone`) WHERE n.deleted >= $since\nRETURN n, null AS r, null AS m"
+	if got := ChangesQuery(map[string]string{"Customer": "UPDATED_AT"}, map[string]string{"HAS_PHONE": "CHANGED"}); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+}
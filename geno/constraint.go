@@ -24,11 +24,31 @@ const (
 	REL_UNIQUE_CONSTRAINT           ConstraintType = "RELATIONSHIP_UNIQUENESS"
 	REL_KEY_CONSTRAINT              ConstraintType = "RELATIONSHIP_KEY"
 	REL_PROPERTY_EXISTS_CONSTRAINT  ConstraintType = "RELATIONSHIP_PROPERTY_EXISTENCE"
+	NODE_PROPERTY_TYPE_CONSTRAINT   ConstraintType = "NODE_PROPERTY_TYPE"         // Neo4j 5.9 and later
+	REL_PROPERTY_TYPE_CONSTRAINT    ConstraintType = "RELATIONSHIP_PROPERTY_TYPE" // Neo4j 5.9 and later
 )
 
+// isKnownConstraintType reports whether geno is able to hold a constraint of the given type
+func isKnownConstraintType(t ConstraintType) bool {
+	switch t {
+	case NODE_UNIQUE_CONSTRAINT, NODE_KEY_CONSTRAINT, NODE_PROPERTY_EXISTS_CONSTRAINT, NODE_PROPERTY_TYPE_CONSTRAINT,
+		REL_UNIQUE_CONSTRAINT, REL_KEY_CONSTRAINT, REL_PROPERTY_EXISTS_CONSTRAINT, REL_PROPERTY_TYPE_CONSTRAINT:
+		return true
+	default:
+		return false
+	}
+}
+
+// constraintTypeAliases maps the names later Neo4j 5 releases report for existing constraint types
+var constraintTypeAliases map[string]ConstraintType = map[string]ConstraintType{
+	"NODE_PROPERTY_UNIQUENESS":         NODE_UNIQUE_CONSTRAINT,
+	"RELATIONSHIP_PROPERTY_UNIQUENESS": REL_UNIQUE_CONSTRAINT,
+}
+
 type Constraint struct {
-	Label      string   `json:"Label" yaml:"Label"`
-	Properties []string `json:"Properties" yaml:"Properties"`
+	Label        string   `json:"Label" yaml:"Label"`
+	Properties   []string `json:"Properties" yaml:"Properties"`
+	PropertyType string   `json:"PropertyType,omitempty" yaml:"PropertyType,omitempty"` // only used by property type constraints, e.g. STRING or INTEGER | FLOAT
 }
 
 type Constraints struct {
@@ -38,6 +58,11 @@ type Constraints struct {
 	RelationshipUniqueness        []Constraint
 	RelationshipKeys              []Constraint
 	RelationshipPropertyExistence []Constraint
+	NodePropertyTypes             []Constraint
+	RelationshipPropertyTypes     []Constraint
+	Cardinality                   []CardinalityConstraint
+	RelationshipEndpoints         []EndpointConstraint
+	PropertyRules                 []PropertyRule
 }
 
 func (c *Constraints) AddConstraint(entityType EntityType, constraintType ConstraintType, newConstraint Constraint) error {
@@ -50,6 +75,8 @@ func (c *Constraints) AddConstraint(entityType EntityType, constraintType Constr
 			c.NodeKeys = append(c.NodeKeys, newConstraint)
 		case NODE_PROPERTY_EXISTS_CONSTRAINT:
 			c.NodePropertyExistence = append(c.NodePropertyExistence, newConstraint)
+		case NODE_PROPERTY_TYPE_CONSTRAINT:
+			c.NodePropertyTypes = append(c.NodePropertyTypes, newConstraint)
 		default:
 			return fmt.Errorf("node constraint type %s could not be added as a constraint", constraintType)
 		}
@@ -61,6 +88,8 @@ func (c *Constraints) AddConstraint(entityType EntityType, constraintType Constr
 			c.RelationshipKeys = append(c.RelationshipKeys, newConstraint)
 		case REL_PROPERTY_EXISTS_CONSTRAINT:
 			c.RelationshipPropertyExistence = append(c.RelationshipPropertyExistence, newConstraint)
+		case REL_PROPERTY_TYPE_CONSTRAINT:
+			c.RelationshipPropertyTypes = append(c.RelationshipPropertyTypes, newConstraint)
 		default:
 			return fmt.Errorf("relationship constraint type %s could not be added as a constraint", constraintType)
 		}
@@ -70,7 +99,18 @@ func (c *Constraints) AddConstraint(entityType EntityType, constraintType Constr
 	return nil
 }
 
+// ConstraintsFromRecords parses the output of SHOW CONSTRAINTS, inferring the server version from the columns returned
 func ConstraintsFromRecords(records []*db.Record) (Constraints, error) {
+	var version ServerVersion = ServerVersion{Major: 4}
+	if len(records) > 0 {
+		version = versionFromConstraintColumns(records[0].Keys)
+	}
+	return ConstraintsFromVersionedRecords(records, version)
+}
+
+// ConstraintsFromVersionedRecords parses the output of SHOW CONSTRAINTS as returned by a server of the given version.
+// Constraint types which geno does not know about are skipped, as they cannot change how entities are merged.
+func ConstraintsFromVersionedRecords(records []*db.Record, version ServerVersion) (Constraints, error) {
 	var c Constraints
 
 	for _, record := range records {
@@ -80,7 +120,8 @@ func ConstraintsFromRecords(records []*db.Record) (Constraints, error) {
 			labelOrTypes   []string
 			entityType     string
 			properties     []string
-			constraintType string
+			constraintType ConstraintType
+			propertyType   string
 			ok             bool
 		)
 		rawField, found := record.Get("labelsOrTypes")
@@ -92,9 +133,6 @@ func ConstraintsFromRecords(records []*db.Record) (Constraints, error) {
 			return c, errors.New("a constraint was found which did not have a properly formatted label or type")
 		}
 		labelOrTypes = make([]string, len(listField))
-		if len(labelOrTypes) > 1 {
-			return c, errors.New("a constraint was applied to more than one node label or type")
-		}
 		for i, l := range listField {
 			s, ok := l.(string)
 			if !ok {
@@ -133,14 +171,32 @@ func ConstraintsFromRecords(records []*db.Record) (Constraints, error) {
 		if !found {
 			return c, errors.New("a constraint was found which did not have a type")
 		}
-		constraintType, ok = rawField.(string)
+		rawType, ok := rawField.(string)
 		if !ok {
 			return c, errors.New("a constraint was found which did not have a properly formatted type")
 		}
+		constraintType = version.normalizeConstraintType(rawType)
+
+		if constraintType == NODE_PROPERTY_TYPE_CONSTRAINT || constraintType == REL_PROPERTY_TYPE_CONSTRAINT {
+			rawField, found = record.Get("propertyType")
+			if !found {
+				return c, fmt.Errorf("a %s constraint was found which did not have a property type", constraintType)
+			}
+			propertyType, ok = rawField.(string)
+			if !ok {
+				return c, errors.New("a constraint was found which did not have a properly formatted property type")
+			}
+		}
+
+		if !isKnownConstraintType(constraintType) {
+			continue
+		}
 
 		for _, label := range labelOrTypes {
-			var newConstraint Constraint = Constraint{Label: label, Properties: properties}
-			c.AddConstraint(EntityType(entityType), ConstraintType(constraintType), newConstraint)
+			var newConstraint Constraint = Constraint{Label: label, Properties: properties, PropertyType: propertyType}
+			if err := c.AddConstraint(EntityType(entityType), constraintType, newConstraint); err != nil {
+				return c, err
+			}
 		}
 
 	}
@@ -199,10 +255,54 @@ func (c *Constraints) String() string {
 			ret.WriteString(p)
 		}
 	}
+	ret.WriteString("\nNODE PROPERTY TYPES")
+	for _, u := range c.NodePropertyTypes {
+		ret.WriteString("\n\t")
+		ret.WriteString(u.Label)
+		ret.WriteString(": ")
+		ret.WriteString(strings.Join(u.Properties, ", "))
+		ret.WriteString(" :: ")
+		ret.WriteString(u.PropertyType)
+	}
+	ret.WriteString("\nREL PROPERTY TYPES")
+	for _, u := range c.RelationshipPropertyTypes {
+		ret.WriteString("\n\t")
+		ret.WriteString(u.Label)
+		ret.WriteString(": ")
+		ret.WriteString(strings.Join(u.Properties, ", "))
+		ret.WriteString(" :: ")
+		ret.WriteString(u.PropertyType)
+	}
+
+	ret.WriteString("\nRELATIONSHIP CARDINALITY")
+	for _, u := range c.Cardinality {
+		ret.WriteString("\n\t")
+		ret.WriteString(u.String())
+	}
+	ret.WriteString("\nRELATIONSHIP ENDPOINTS")
+	for _, u := range c.RelationshipEndpoints {
+		ret.WriteString("\n\t")
+		ret.WriteString(u.String())
+	}
+	ret.WriteString("\nPROPERTY RULES")
+	for _, u := range c.PropertyRules {
+		ret.WriteString("\n\t")
+		ret.WriteString(u.Label)
+		ret.WriteString(".")
+		ret.WriteString(u.Property)
+	}
 
 	return ret.String()
 }
 
+// CopyNonNative copies the constraints which neo4j cannot hold from another set of constraints,
+// so that constraints read from a database can be combined with those of a configuration file
+func (c *Constraints) CopyNonNative(from Constraints) {
+	c.Cardinality = from.Cardinality
+	c.RelationshipEndpoints = from.RelationshipEndpoints
+	c.PropertyRules = from.PropertyRules
+}
+
 func (constraints *Constraints) GetNodeConstraints(n *Node) []string {
 	var (
 		reducer        map[string]byte = make(map[string]byte)
@@ -312,3 +412,107 @@ func GetConstraintsFromDb(driver neo4j.Driver, database string) (Constraints, er
 
 	return c, nil
 }
+
+// ToCypherCreate builds an idempotent statement creating the constraint. Existence and property type constraints
+// hold a single property, so one statement is built for each of their properties.
+func (c Constraint) ToCypherCreate(entityType EntityType, constraintType ConstraintType) ([]string, error) {
+	if c.Label == "" || len(c.Properties) == 0 {
+		return nil, fmt.Errorf("a %s constraint must have a label and at least one property", constraintType)
+	}
+	var (
+		pattern    string
+		props      []string = make([]string, len(c.Properties))
+		statements []string
+	)
+	if entityType == IS_RELATIONSHIP {
+		pattern = "()-[e:" + escapeName(c.Label) + "]-()"
+	} else {
+		pattern = "(e:" + escapeName(c.Label) + ")"
+	}
+	for i, p := range c.Properties {
+		props[i] = "e." + escapeName(p)
+	}
+	prefix := "CREATE CONSTRAINT IF NOT EXISTS FOR " + pattern + " REQUIRE "
+
+	switch constraintType {
+	case NODE_UNIQUE_CONSTRAINT, REL_UNIQUE_CONSTRAINT:
+		statements = append(statements, prefix+"("+strings.Join(props, ", ")+") IS UNIQUE")
+	case NODE_KEY_CONSTRAINT:
+		statements = append(statements, prefix+"("+strings.Join(props, ", ")+") IS NODE KEY")
+	case REL_KEY_CONSTRAINT:
+		statements = append(statements, prefix+"("+strings.Join(props, ", ")+") IS RELATIONSHIP KEY")
+	case NODE_PROPERTY_EXISTS_CONSTRAINT, REL_PROPERTY_EXISTS_CONSTRAINT:
+		for _, p := range props {
+			statements = append(statements, prefix+p+" IS NOT NULL")
+		}
+	case NODE_PROPERTY_TYPE_CONSTRAINT, REL_PROPERTY_TYPE_CONSTRAINT:
+		if c.PropertyType == "" {
+			return nil, fmt.Errorf("property type constraint on %s has no property type", c.Label)
+		}
+		for _, p := range props {
+			statements = append(statements, prefix+p+" IS :: "+c.PropertyType)
+		}
+	default:
+		return nil, fmt.Errorf("constraint type %s cannot be created in neo4j", constraintType)
+	}
+	return statements, nil
+}
+
+// createdConstraintType reads the type of the constraint created by a statement built by Constraint.ToCypherCreate
+func createdConstraintType(statement string) (ConstraintType, bool) {
+	var onRelationship bool = strings.HasPrefix(statement, "CREATE CONSTRAINT IF NOT EXISTS FOR ()-[")
+	switch {
+	case !strings.HasPrefix(statement, "CREATE CONSTRAINT IF NOT EXISTS FOR "):
+		return "", false
+	case strings.HasSuffix(statement, ") IS NODE KEY"):
+		return NODE_KEY_CONSTRAINT, true
+	case strings.HasSuffix(statement, ") IS RELATIONSHIP KEY"):
+		return REL_KEY_CONSTRAINT, true
+	case strings.HasSuffix(statement, ") IS UNIQUE") && onRelationship:
+		return REL_UNIQUE_CONSTRAINT, true
+	case strings.HasSuffix(statement, ") IS UNIQUE"):
+		return NODE_UNIQUE_CONSTRAINT, true
+	case strings.HasSuffix(statement, " IS NOT NULL") && onRelationship:
+		return REL_PROPERTY_EXISTS_CONSTRAINT, true
+	case strings.HasSuffix(statement, " IS NOT NULL"):
+		return NODE_PROPERTY_EXISTS_CONSTRAINT, true
+	case strings.Contains(statement, " IS :: ") && onRelationship:
+		return REL_PROPERTY_TYPE_CONSTRAINT, true
+	case strings.Contains(statement, " IS :: "):
+		return NODE_PROPERTY_TYPE_CONSTRAINT, true
+	default:
+		return "", false
+	}
+}
+
+// ToCypherCreate builds statements creating every constraint neo4j is able to hold.
+// Constraints which only geno enforces, such as cardinality, are left out.
+func (c *Constraints) ToCypherCreate() ([]string, error) {
+	var (
+		statements []string
+		groups     = []struct {
+			entityType     EntityType
+			constraintType ConstraintType
+			constraints    []Constraint
+		}{
+			{IS_NODE, NODE_UNIQUE_CONSTRAINT, c.NodeUniqueness},
+			{IS_NODE, NODE_KEY_CONSTRAINT, c.NodeKeys},
+			{IS_NODE, NODE_PROPERTY_EXISTS_CONSTRAINT, c.NodePropertyExistence},
+			{IS_NODE, NODE_PROPERTY_TYPE_CONSTRAINT, c.NodePropertyTypes},
+			{IS_RELATIONSHIP, REL_UNIQUE_CONSTRAINT, c.RelationshipUniqueness},
+			{IS_RELATIONSHIP, REL_KEY_CONSTRAINT, c.RelationshipKeys},
+			{IS_RELATIONSHIP, REL_PROPERTY_EXISTS_CONSTRAINT, c.RelationshipPropertyExistence},
+			{IS_RELATIONSHIP, REL_PROPERTY_TYPE_CONSTRAINT, c.RelationshipPropertyTypes},
+		}
+	)
+	for _, group := range groups {
+		for _, constraint := range group.constraints {
+			created, err := constraint.ToCypherCreate(group.entityType, group.constraintType)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, created...)
+		}
+	}
+	return statements, nil
+}
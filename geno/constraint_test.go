@@ -0,0 +1,128 @@
+package geno
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+)
+
+var (
+	v4Columns []string = []string{"id", "name", "type", "entityType", "labelsOrTypes", "properties", "ownedIndexId"}
+	v5Columns []string = []string{"id", "name", "type", "entityType", "labelsOrTypes", "properties", "ownedIndex", "propertyType"}
+)
+
+func TestConstraintsFromRecords(t *testing.T) {
+	type test struct {
+		name    string
+		records []*db.Record
+		want    Constraints
+	}
+
+	tests := []test{
+		{
+			name: "neo4j 4.4",
+			records: []*db.Record{
+				{Keys: v4Columns, Values: []any{int64(1), "c1", "UNIQUENESS", "NODE", []any{"Customer"}, []any{"Key"}, int64(2)}},
+				{Keys: v4Columns, Values: []any{int64(3), "c2", "NODE_KEY", "NODE", []any{"Vendor"}, []any{"Database", "LIFNR"}, int64(4)}},
+			},
+			want: Constraints{
+				NodeUniqueness: []Constraint{{Label: "Customer", Properties: []string{"Key"}}},
+				NodeKeys:       []Constraint{{Label: "Vendor", Properties: []string{"Database", "LIFNR"}}},
+			},
+		},
+		{
+			name: "neo4j 5 property types and unknown types",
+			records: []*db.Record{
+				{Keys: v5Columns, Values: []any{int64(1), "c1", "NODE_PROPERTY_UNIQUENESS", "NODE", []any{"Customer"}, []any{"Key"}, "index1", nil}},
+				{Keys: v5Columns, Values: []any{int64(2), "c2", "NODE_PROPERTY_TYPE", "NODE", []any{"Customer"}, []any{"KUNNR"}, nil, "STRING"}},
+				{Keys: v5Columns, Values: []any{int64(3), "c3", "RELATIONSHIP_PROPERTY_TYPE", "RELATIONSHIP", []any{"HAS_PHONE"}, []any{"Since"}, nil, "DATE"}},
+				{Keys: v5Columns, Values: []any{int64(4), "c4", "SOME_FUTURE_TYPE", "NODE", []any{"Customer"}, []any{"Name"}, nil, nil}},
+			},
+			want: Constraints{
+				NodeUniqueness:            []Constraint{{Label: "Customer", Properties: []string{"Key"}}},
+				NodePropertyTypes:         []Constraint{{Label: "Customer", Properties: []string{"KUNNR"}, PropertyType: "STRING"}},
+				RelationshipPropertyTypes: []Constraint{{Label: "HAS_PHONE", Properties: []string{"Since"}, PropertyType: "DATE"}},
+			},
+		},
+		{
+			name: "constraint spanning several labels",
+			records: []*db.Record{
+				{Keys: v5Columns, Values: []any{int64(1), "c1", "UNIQUENESS", "NODE", []any{"Customer", "Vendor"}, []any{"Key"}, "index1", nil}},
+			},
+			want: Constraints{
+				NodeUniqueness: []Constraint{{Label: "Customer", Properties: []string{"Key"}}, {Label: "Vendor", Properties: []string{"Key"}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		got, err := ConstraintsFromRecords(tc.records)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf("%s: wanted\n%v\nbut got\n%v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	type test struct {
+		raw     string
+		want    ServerVersion
+		wantErr bool
+	}
+
+	tests := []test{
+		{raw: "4.4.11", want: ServerVersion{Major: 4, Minor: 4}},
+		{raw: "5.13.0", want: ServerVersion{Major: 5, Minor: 13}},
+		{raw: "5.9-aura", want: ServerVersion{Major: 5, Minor: 9}},
+		{raw: "five", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseServerVersion(tc.raw)
+		if tc.wantErr != (err != nil) {
+			t.Errorf("%s: wanted error %v but got %v", tc.raw, tc.wantErr, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: wanted %v but got %v", tc.raw, tc.want, got)
+		}
+	}
+}
+
+func TestConstraintsToCypherCreate(t *testing.T) {
+	one := 1
+	c := Constraints{
+		NodeKeys:                  []Constraint{{Label: "Customer", Properties: []string{"Database", "KUNNR"}}},
+		NodePropertyExistence:     []Constraint{{Label: "Customer", Properties: []string{"NAME1", "LAND1"}}},
+		RelationshipUniqueness:    []Constraint{{Label: "BUYS_FROM", Properties: []string{"Id"}}},
+		RelationshipPropertyTypes: []Constraint{{Label: "BUYS_FROM", Properties: []string{"Since"}, PropertyType: "DATE"}},
+		Cardinality:               []CardinalityConstraint{{Label: "Customer", Type: "BUYS_FROM", Direction: OUTGOING, Max: &one}},
+	}
+	want := []string{
+		"CREATE CONSTRAINT IF NOT EXISTS FOR (e:`Customer`) REQUIRE (e.`Database`, e.`KUNNR`) IS NODE KEY",
+		"CREATE CONSTRAINT IF NOT EXISTS FOR (e:`Customer`) REQUIRE e.`NAME1` IS NOT NULL",
+		"CREATE CONSTRAINT IF NOT EXISTS FOR (e:`Customer`) REQUIRE e.`LAND1` IS NOT NULL",
+		"CREATE CONSTRAINT IF NOT EXISTS FOR ()-[e:`BUYS_FROM`]-() REQUIRE (e.`Id`) IS UNIQUE",
+		"CREATE CONSTRAINT IF NOT EXISTS FOR ()-[e:`BUYS_FROM`]-() REQUIRE e.`Since` IS :: DATE",
+	}
+	got, err := c.ToCypherCreate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted\n%v\nbut got\n%v", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	supported, unsupported := ServerVersion{Major: 5, Minor: 7}.SupportedStatements(got)
+	if !reflect.DeepEqual(want[:4], supported) || !reflect.DeepEqual(want[4:], unsupported) {
+		t.Errorf("a 5.7 server should support every statement but the property type constraint, but got\n%v\nand\n%v", supported, unsupported)
+	}
+	supported, unsupported = ServerVersion{Major: 4, Minor: 4}.SupportedStatements(got)
+	if !reflect.DeepEqual(want[:3], supported) || !reflect.DeepEqual(want[3:], unsupported) {
+		t.Errorf("a 4.4 server should support only the node constraints, but got\n%v\nand\n%v", supported, unsupported)
+	}
+}
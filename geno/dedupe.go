@@ -0,0 +1,314 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+type SurvivorRule string
+type MergePolicy string
+
+const (
+	// Survivor Rules
+	SURVIVOR_LOWEST_ID          SurvivorRule = "lowest-id"
+	SURVIVOR_MOST_RELATIONSHIPS SurvivorRule = "most-relationships"
+	SURVIVOR_MOST_PROPERTIES    SurvivorRule = "most-properties"
+	// Merge Policies
+	MERGE_KEEP_SURVIVOR MergePolicy = "keep"      // the survivor's values win, losers only fill in missing properties
+	MERGE_OVERWRITE     MergePolicy = "overwrite" // the losers' values replace the survivor's
+	MERGE_FAIL          MergePolicy = "fail"      // any conflicting value leaves the group untouched
+)
+
+var errMergeConflict error = errors.New("duplicate nodes have conflicting property values")
+
+// DuplicateMember is a single node within a group of duplicates
+type DuplicateMember struct {
+	Id         int64
+	Degree     int64
+	Properties map[string]any
+}
+
+// DuplicateGroup is a set of nodes sharing a label and identical values for every key property
+type DuplicateGroup struct {
+	Label   string
+	Key     map[string]any
+	Members []DuplicateMember
+}
+
+// DedupeSummary counts the changes made while consolidating duplicate groups
+type DedupeSummary struct {
+	Groups               int
+	NodesDeleted         int
+	RelationshipsMoved   int
+	RelationshipsDropped int
+}
+
+func (g DuplicateGroup) String() string {
+	var (
+		keys  []string = make([]string, 0, len(g.Key))
+		pairs []string = make([]string, 0, len(g.Key))
+	)
+	for key := range g.Key {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, g.Key[key]))
+	}
+	return fmt.Sprintf("%s {%s}", g.Label, strings.Join(pairs, ", "))
+}
+
+// Survivor picks the member of the group which all others will be merged into.
+// Ties are always broken in favour of the lowest internal id. Neo4j reuses the ids of deleted nodes, so the lowest
+// id is not necessarily the node created first.
+func (g *DuplicateGroup) Survivor(rule SurvivorRule) (survivor DuplicateMember, losers []DuplicateMember, err error) {
+	if len(g.Members) == 0 {
+		return survivor, losers, errors.New("a duplicate group must have at least one member")
+	}
+	var members []DuplicateMember = make([]DuplicateMember, len(g.Members))
+	copy(members, g.Members)
+
+	var score func(m DuplicateMember) int64
+	switch rule {
+	case SURVIVOR_LOWEST_ID:
+		score = func(m DuplicateMember) int64 { return 0 }
+	case SURVIVOR_MOST_RELATIONSHIPS:
+		score = func(m DuplicateMember) int64 { return m.Degree }
+	case SURVIVOR_MOST_PROPERTIES:
+		score = func(m DuplicateMember) int64 { return int64(len(m.Properties)) }
+	default:
+		return survivor, losers, fmt.Errorf("survivor rule %s is not supported", rule)
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if score(members[i]) != score(members[j]) {
+			return score(members[i]) > score(members[j])
+		}
+		return members[i].Id < members[j].Id
+	})
+
+	return members[0], members[1:], nil
+}
+
+// MergeProperties combines the properties of every loser into those of the survivor according to the merge policy.
+// Losers are applied in the order given.
+func MergeProperties(survivor DuplicateMember, losers []DuplicateMember, policy MergePolicy) (map[string]any, error) {
+	var merged map[string]any = make(map[string]any, len(survivor.Properties))
+	for key, val := range survivor.Properties {
+		merged[key] = val
+	}
+
+	for _, loser := range losers {
+		for key, val := range loser.Properties {
+			existing, found := merged[key]
+			if !found {
+				merged[key] = val
+				continue
+			}
+			if reflect.DeepEqual(existing, val) {
+				continue
+			}
+			switch policy {
+			case MERGE_KEEP_SURVIVOR:
+			case MERGE_OVERWRITE:
+				merged[key] = val
+			case MERGE_FAIL:
+				return nil, fmt.Errorf("%w: property %s is %v on node %d but %v on node %d", errMergeConflict, key, existing, survivor.Id, val, loser.Id)
+			default:
+				return nil, fmt.Errorf("merge policy %s is not supported", policy)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// FindDuplicates returns up to limit groups of nodes with the given label that share values for every key property.
+// Groups are ordered by their key values so that batches are stable between calls; skip is the number of groups to pass over.
+func (d *Driver) FindDuplicates(database, label string, keys []string, skip, limit int) ([]DuplicateGroup, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key properties were provided to find duplicates of %s", label)
+	}
+	var (
+		q          strings.Builder = strings.Builder{}
+		conditions []string        = make([]string, len(keys))
+		groupings  []string        = make([]string, len(keys))
+		keyVars    []string        = make([]string, len(keys))
+		groups     []DuplicateGroup
+	)
+	sort.Strings(keys)
+	for i, key := range keys {
+		conditions[i] = fmt.Sprintf("n.%s IS NOT NULL", escapeName(key))
+		groupings[i] = fmt.Sprintf("n.%s AS k%d", escapeName(key), i)
+		keyVars[i] = fmt.Sprintf("k%d", i)
+	}
+
+	q.WriteString("MATCH (n:")
+	q.WriteString(escapeName(label))
+	q.WriteString(")\nWHERE ")
+	q.WriteString(strings.Join(conditions, " AND "))
+	q.WriteString("\nWITH ")
+	q.WriteString(strings.Join(groupings, ", "))
+	q.WriteString(", collect(n) AS members\nWHERE size(members) > 1\n")
+	q.WriteString("RETURN [")
+	q.WriteString(strings.Join(keyVars, ", "))
+	q.WriteString("] AS key, [m IN members | {id: id(m), degree: size([(m)--() | 1]), properties: properties(m)}] AS members\n")
+	q.WriteString("ORDER BY ")
+	q.WriteString(strings.Join(keyVars, ", "))
+	q.WriteString("\nSKIP $skip LIMIT $limit")
+
+	records, err := d.ReadRecords(database, q.String(), map[string]any{"skip": skip, "limit": limit})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		rawKey, _ := record.Get("key")
+		rawMembers, _ := record.Get("members")
+		keyValues, ok := rawKey.([]any)
+		if !ok || len(keyValues) != len(keys) {
+			return nil, errors.New("a duplicate group was returned without a properly formatted key")
+		}
+		memberList, ok := rawMembers.([]any)
+		if !ok {
+			return nil, errors.New("a duplicate group was returned without a properly formatted list of members")
+		}
+
+		var group DuplicateGroup = DuplicateGroup{Label: label, Key: make(map[string]any, len(keys))}
+		for i, key := range keys {
+			group.Key[key] = keyValues[i]
+		}
+		for _, rawMember := range memberList {
+			m, ok := rawMember.(map[string]any)
+			if !ok {
+				return nil, errors.New("a duplicate group was returned with an improperly formatted member")
+			}
+			id, _ := m["id"].(int64)
+			degree, _ := m["degree"].(int64)
+			props, _ := m["properties"].(map[string]any)
+			group.Members = append(group.Members, DuplicateMember{Id: id, Degree: degree, Properties: props})
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// ConsolidateDuplicates merges every loser of a duplicate group into its survivor within a single transaction.
+// Relationships of the losers are recreated on the survivor, properties are merged according to the policy
+// and the losers are deleted. Relationships between two members of the group are dropped rather than becoming self loops.
+// When the driver tracks changes, the survivor and recreated relationships are stamped, and the losers and every
+// relationship deleted with them leave tombstones.
+func (d *Driver) ConsolidateDuplicates(database string, group DuplicateGroup, rule SurvivorRule, policy MergePolicy) (DedupeSummary, error) {
+	var summary DedupeSummary
+
+	survivor, losers, err := group.Survivor(rule)
+	if err != nil {
+		return summary, err
+	}
+	merged, err := MergeProperties(survivor, losers, policy)
+	if err != nil {
+		return summary, err
+	}
+
+	var (
+		members  map[int64]bool = map[int64]bool{survivor.Id: true}
+		loserIds []int64        = make([]int64, len(losers))
+	)
+	for i, loser := range losers {
+		members[loser.Id] = true
+		loserIds[i] = loser.Id
+	}
+
+	session := d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	defer session.Close()
+
+	_, err = session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		summary = DedupeSummary{Groups: 1}
+		var seen map[int64]bool = make(map[int64]bool)
+
+		result, txErr := tx.Run(`MATCH (l)-[r]-(o) WHERE id(l) IN $losers
+RETURN DISTINCT id(r) AS id, type(r) AS type, id(startNode(r)) AS start, id(endNode(r)) AS end, properties(r) AS properties`,
+			map[string]any{"losers": loserIds})
+		if txErr != nil {
+			return nil, txErr
+		}
+		records, txErr := result.Collect()
+		if txErr != nil {
+			return nil, txErr
+		}
+
+		for _, record := range records {
+			rawId, _ := record.Get("id")
+			relType, _ := record.Get("type")
+			rawStart, _ := record.Get("start")
+			rawEnd, _ := record.Get("end")
+			props, _ := record.Get("properties")
+			id, _ := rawId.(int64)
+			start, _ := rawStart.(int64)
+			end, _ := rawEnd.(int64)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			if members[start] && members[end] {
+				summary.RelationshipsDropped++
+				continue
+			}
+			if members[start] {
+				start = survivor.Id
+			}
+			if members[end] {
+				end = survivor.Id
+			}
+			_, txErr = tx.Run(fmt.Sprintf(`MATCH (s) WHERE id(s) = $start
+MATCH (e) WHERE id(e) = $end
+CREATE (s)-[r:%s]->(e)
+SET r = $properties%s`, escapeName(fmt.Sprint(relType)), stampSet("r", d.timestampOf(database, fmt.Sprint(relType)))),
+				map[string]any{"start": start, "end": end, "properties": props})
+			if txErr != nil {
+				return nil, txErr
+			}
+			summary.RelationshipsMoved++
+		}
+
+		if d.tracksChanges(database) {
+			// every relationship of the losers is deleted with them, including those moved to the survivor
+			txErr = recordTombstones(tx, `MATCH (l)-[r]-() WHERE id(l) IN $losers
+RETURN DISTINCT r AS deleted, startNode(r) AS start, endNode(r) AS end`, map[string]any{"losers": loserIds})
+			if txErr != nil {
+				return nil, txErr
+			}
+			txErr = recordTombstones(tx, "MATCH (l) WHERE id(l) IN $losers RETURN l AS deleted", map[string]any{"losers": loserIds})
+			if txErr != nil {
+				return nil, txErr
+			}
+		}
+		_, txErr = tx.Run("MATCH (l) WHERE id(l) IN $losers DETACH DELETE l", map[string]any{"losers": loserIds})
+		if txErr != nil {
+			return nil, txErr
+		}
+		_, txErr = tx.Run("MATCH (s) WHERE id(s) = $survivor SET s = $properties"+stampSet("s", d.timestampOf(database, group.Label)), map[string]any{"survivor": survivor.Id, "properties": merged})
+		if txErr != nil {
+			return nil, txErr
+		}
+		summary.NodesDeleted = len(loserIds)
+		return nil, nil
+	})
+	if err != nil {
+		return DedupeSummary{}, err
+	}
+
+	return summary, nil
+}
+
+// IsMergeConflict reports whether an error was caused by the MERGE_FAIL policy encountering conflicting values
+func IsMergeConflict(err error) bool {
+	return errors.Is(err, errMergeConflict)
+}
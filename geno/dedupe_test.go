@@ -0,0 +1,80 @@
+package geno
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	dupOld     DuplicateMember = DuplicateMember{Id: 1, Degree: 1, Properties: map[string]any{"Key": "A", "Name": "Old"}}
+	dupLinked  DuplicateMember = DuplicateMember{Id: 2, Degree: 5, Properties: map[string]any{"Key": "A", "Name": "Linked"}}
+	dupVerbose DuplicateMember = DuplicateMember{Id: 3, Degree: 0, Properties: map[string]any{"Key": "A", "Name": "Verbose", "Phone": "123"}}
+	dupGroup   DuplicateGroup  = DuplicateGroup{Label: "Customer", Key: map[string]any{"Key": "A"}, Members: []DuplicateMember{dupVerbose, dupLinked, dupOld}}
+)
+
+func TestDuplicateGroupSurvivor(t *testing.T) {
+	type test struct {
+		name       string
+		rule       SurvivorRule
+		wantId     int64
+		wantLosers []int64
+	}
+
+	tests := []test{
+		{name: "lowest id", rule: SURVIVOR_LOWEST_ID, wantId: 1, wantLosers: []int64{2, 3}},
+		{name: "most relationships", rule: SURVIVOR_MOST_RELATIONSHIPS, wantId: 2, wantLosers: []int64{1, 3}},
+		{name: "most properties", rule: SURVIVOR_MOST_PROPERTIES, wantId: 3, wantLosers: []int64{1, 2}},
+	}
+
+	for _, tc := range tests {
+		survivor, losers, err := dupGroup.Survivor(tc.rule)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if survivor.Id != tc.wantId {
+			t.Errorf("%s: wanted survivor %d but got %d", tc.name, tc.wantId, survivor.Id)
+		}
+		var gotLosers []int64
+		for _, l := range losers {
+			gotLosers = append(gotLosers, l.Id)
+		}
+		if !reflect.DeepEqual(tc.wantLosers, gotLosers) {
+			t.Errorf("%s: wanted losers %v but got %v", tc.name, tc.wantLosers, gotLosers)
+		}
+	}
+
+	if _, _, err := dupGroup.Survivor("newest"); err == nil {
+		t.Error("an unknown survivor rule should return an error")
+	}
+}
+
+func TestMergeProperties(t *testing.T) {
+	type test struct {
+		name    string
+		policy  MergePolicy
+		want    map[string]any
+		wantErr bool
+	}
+
+	tests := []test{
+		{name: "keep survivor", policy: MERGE_KEEP_SURVIVOR, want: map[string]any{"Key": "A", "Name": "Old", "Phone": "123"}},
+		{name: "overwrite", policy: MERGE_OVERWRITE, want: map[string]any{"Key": "A", "Name": "Verbose", "Phone": "123"}},
+		{name: "fail", policy: MERGE_FAIL, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := MergeProperties(dupOld, []DuplicateMember{dupLinked, dupVerbose}, tc.policy)
+		if tc.wantErr {
+			if !IsMergeConflict(err) {
+				t.Errorf("%s: wanted a merge conflict but got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf("%s: wanted %v but got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package geno
 
 import (
+	"errors"
+
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
 
 type Driver struct {
 	neo4j.Driver
+	timestamps map[string][]Timestamp
 }
 
 func NewDriver(uri string, auth neo4j.AuthToken) (Driver, error) {
@@ -13,17 +16,38 @@ func NewDriver(uri string, auth neo4j.AuthToken) (Driver, error) {
 	if err != nil {
 		return Driver{}, err
 	}
-	return Driver{driver}, nil
+	return Driver{Driver: driver}, nil
 }
 
 func (d *Driver) GetConstraints(database string) (Constraints, error) {
+	records, err := d.ReadRecords(database, "SHOW CONSTRAINTS", nil)
+	if err != nil {
+		return Constraints{}, err
+	}
+
+	// not every user may call dbms.components, in which case the version is inferred from the columns returned
+	version, err := d.GetServerVersion()
+	if err != nil {
+		return ConstraintsFromRecords(records)
+	}
+
+	c, err := ConstraintsFromVersionedRecords(records, version)
+	if err != nil {
+		return Constraints{}, err
+	}
+
+	return c, nil
+}
+
+// ReadRecords runs a single query in a read transaction and collects every record it returns
+func (d *Driver) ReadRecords(database, cypher string, params map[string]any) ([]*neo4j.Record, error) {
 	session := d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: database})
 	defer session.Close()
 
 	var records []*neo4j.Record
 
 	_, err := session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
-		result, txErr := tx.Run("SHOW CONSTRAINTS", nil)
+		result, txErr := tx.Run(cypher, params)
 		if txErr != nil {
 			return nil, txErr
 		}
@@ -36,13 +60,88 @@ func (d *Driver) GetConstraints(database string) (Constraints, error) {
 		return result.Consume()
 	})
 	if err != nil {
-		return Constraints{}, err
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// StreamRecords runs a single read query and calls page with every pageSize records as they arrive, so that memory use
+// does not grow with the number of records. The query runs in an auto-commit transaction, which is never retried, as
+// the pages already handled could not be taken back.
+func (d *Driver) StreamRecords(database, cypher string, params map[string]any, pageSize int, page func(records []*neo4j.Record) error) error {
+	if pageSize < 1 {
+		return errors.New("the page size must be at least 1")
 	}
+	session := d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead, DatabaseName: database})
+	defer session.Close()
 
-	c, err := ConstraintsFromRecords(records)
+	result, err := session.Run(cypher, params)
 	if err != nil {
-		return Constraints{}, err
+		return err
+	}
+	var records []*neo4j.Record = make([]*neo4j.Record, 0, pageSize)
+	for result.Next() {
+		records = append(records, result.Record())
+		if len(records) == pageSize {
+			if err := page(records); err != nil {
+				return err
+			}
+			records = make([]*neo4j.Record, 0, pageSize)
+		}
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+	if len(records) > 0 {
+		return page(records)
 	}
+	return nil
+}
 
-	return c, nil
+// RunQuery runs a single query, collecting the keys and every record it returns along with its summary. Reads run in
+// a read transaction, which is retried on transient errors. Writes run in an auto-commit transaction, which is never
+// retried, so that they are not applied twice, and which lets queries such as CALL {...} IN TRANSACTIONS commit
+// their own transactions.
+func (d *Driver) RunQuery(database, cypher string, params map[string]any, mode neo4j.AccessMode) (keys []string, records []*neo4j.Record, summary neo4j.ResultSummary, err error) {
+	session := d.NewSession(neo4j.SessionConfig{AccessMode: mode, DatabaseName: database})
+	defer session.Close()
+
+	collect := func(result neo4j.Result) (neo4j.ResultSummary, error) {
+		var err error
+		keys, records = nil, nil
+		if keys, err = result.Keys(); err != nil {
+			return nil, err
+		}
+		for result.Next() {
+			records = append(records, result.Record())
+		}
+		if err = result.Err(); err != nil {
+			return nil, err
+		}
+		return result.Consume()
+	}
+
+	if mode == neo4j.AccessModeWrite {
+		result, err := session.Run(cypher, params)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if summary, err = collect(result); err != nil {
+			return nil, nil, nil, err
+		}
+		return keys, records, summary, nil
+	}
+
+	consumed, err := session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		result, txErr := tx.Run(cypher, params)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return collect(result)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return keys, records, consumed.(neo4j.ResultSummary), nil
 }
@@ -0,0 +1,125 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const ENDPOINT_CONSTRAINT ConstraintType = "RELATIONSHIP_ENDPOINTS"
+
+// EndpointConstraint allows relationships of a type to start at a node with any of the Start labels and end
+// at a node with any of the End labels. An empty list allows any label. Once a type has an endpoint constraint,
+// each of its relationships must conform to at least one of the type's endpoint constraints.
+type EndpointConstraint struct {
+	Type  string   `json:"Type" yaml:"Type"`
+	Start []string `json:"Start" yaml:"Start"`
+	End   []string `json:"End" yaml:"End"`
+}
+
+func (e EndpointConstraint) String() string {
+	describe := func(labels []string) string {
+		if len(labels) == 0 {
+			return "()"
+		}
+		return "(:" + strings.Join(labels, "|") + ")"
+	}
+	return fmt.Sprintf("%s-[:%s]->%s", describe(e.Start), e.Type, describe(e.End))
+}
+
+// Allows reports whether a relationship between the two nodes conforms to the constraint
+func (e EndpointConstraint) Allows(start, end *Node) bool {
+	return anyLabelIn(start.Labels, e.Start) && anyLabelIn(end.Labels, e.End)
+}
+
+func anyLabelIn(labels, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, l := range labels {
+		for _, a := range allowed {
+			if l == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetEndpointConstraints returns every endpoint constraint declared for a relationship type
+func (constraints *Constraints) GetEndpointConstraints(relType string) []EndpointConstraint {
+	var found []EndpointConstraint
+	for _, e := range constraints.RelationshipEndpoints {
+		if e.Type == relType {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// checkEndpoints returns a violation if the relationship's type has endpoint constraints and it conforms to none of them
+func (constraints *Constraints) checkEndpoints(r *Relationship) []Violation {
+	allowed := constraints.GetEndpointConstraints(r.Label)
+	if len(allowed) == 0 {
+		return nil
+	}
+	var descriptions []string = make([]string, len(allowed))
+	for i, e := range allowed {
+		if e.Allows(&r.Start, &r.End) {
+			return nil
+		}
+		descriptions[i] = e.String()
+	}
+	return []Violation{{
+		Constraint: ENDPOINT_CONSTRAINT,
+		Label:      r.Label,
+		Message:    fmt.Sprintf("(:%s)-[:%s]->(:%s) is not one of %s", r.Start, r.Label, r.End, strings.Join(descriptions, ", ")),
+	}}
+}
+
+// AuditEndpoints finds up to limit relationships of a type which conform to none of the allowed endpoint constraints
+func (d *Driver) AuditEndpoints(database, relType string, allowed []EndpointConstraint, limit int) ([]Violation, error) {
+	if len(allowed) == 0 {
+		return nil, errors.New("no endpoint constraints were provided to audit " + relType)
+	}
+	var (
+		q          strings.Builder = strings.Builder{}
+		conditions []string        = make([]string, len(allowed))
+		violations []Violation
+	)
+	labelCondition := func(variable string, labels []string) string {
+		if len(labels) == 0 {
+			return "true"
+		}
+		var options []string = make([]string, len(labels))
+		for i, l := range labels {
+			options[i] = variable + ":" + escapeName(l)
+		}
+		return "(" + strings.Join(options, " OR ") + ")"
+	}
+	for i, e := range allowed {
+		conditions[i] = fmt.Sprintf("NOT (%s AND %s)", labelCondition("a", e.Start), labelCondition("b", e.End))
+	}
+
+	q.WriteString("MATCH (a)-[r:")
+	q.WriteString(escapeName(relType))
+	q.WriteString("]->(b)\nWHERE ")
+	q.WriteString(strings.Join(conditions, " AND "))
+	q.WriteString("\nRETURN id(r) AS id, labels(a) AS start, labels(b) AS end\nLIMIT $limit")
+
+	records, err := d.ReadRecords(database, q.String(), map[string]any{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		id, _ := record.Get("id")
+		start, _ := stringList(record, "start")
+		end, _ := stringList(record, "end")
+		violations = append(violations, Violation{
+			Constraint: ENDPOINT_CONSTRAINT,
+			Label:      relType,
+			Message:    fmt.Sprintf("relationship %v (:%s)-[:%s]->(:%s) has endpoints which are not allowed", id, strings.Join(start, ":"), relType, strings.Join(end, ":")),
+		})
+	}
+	return violations, nil
+}
@@ -0,0 +1,36 @@
+package geno
+
+import "testing"
+
+func TestValidateRelationshipEndpoints(t *testing.T) {
+	var (
+		customer Node        = NewNode(1, []string{"Customer"}, nil)
+		phone    Node        = NewNode(2, []string{"Phone"}, nil)
+		bank     Node        = NewNode(3, []string{"Bank"}, nil)
+		address  Node        = NewNode(4, []string{"Address"}, nil)
+		c        Constraints = Constraints{RelationshipEndpoints: []EndpointConstraint{
+			{Type: "HAS_ADDRESS", Start: []string{"Customer", "Vendor"}, End: []string{"Address"}},
+			{Type: "HAS_ADDRESS", Start: []string{"Bank"}},
+		}}
+	)
+
+	type test struct {
+		name string
+		rel  Relationship
+		want int
+	}
+
+	tests := []test{
+		{name: "allowed pair", rel: NewRelationship(1, customer, address, "HAS_ADDRESS", nil), want: 0},
+		{name: "any end label", rel: NewRelationship(2, bank, phone, "HAS_ADDRESS", nil), want: 0},
+		{name: "not allowed", rel: NewRelationship(3, phone, bank, "HAS_ADDRESS", nil), want: 1},
+		{name: "unconstrained type", rel: NewRelationship(4, phone, bank, "HAS_PHONE", nil), want: 0},
+	}
+
+	for _, tc := range tests {
+		got := c.ValidateRelationship(&tc.rel)
+		if len(got) != tc.want {
+			t.Errorf("%s: wanted %d violation(s) but got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+)
+
+type IndexType string
+
+const (
+	RANGE_INDEX    IndexType = "RANGE"
+	TEXT_INDEX     IndexType = "TEXT"
+	POINT_INDEX    IndexType = "POINT"
+	FULLTEXT_INDEX IndexType = "FULLTEXT"
+	LOOKUP_INDEX   IndexType = "LOOKUP"
+	BTREE_INDEX    IndexType = "BTREE" // Neo4j 4.x equivalent of a range index
+)
+
+// Index is a single index over one or more properties. Only fulltext indexes may span several labels or types.
+type Index struct {
+	Name       string     `json:"Name,omitempty" yaml:"Name,omitempty"`
+	EntityType EntityType `json:"EntityType,omitempty" yaml:"EntityType,omitempty"` // defaults to NODE
+	Labels     []string   `json:"Labels" yaml:"Labels"`
+	Properties []string   `json:"Properties" yaml:"Properties"`
+	Owned      bool       `json:"-" yaml:"-" mapstructure:"-"` // backs a constraint, so is managed along with it
+}
+
+type Indexes struct {
+	Range    []Index
+	Text     []Index
+	Point    []Index
+	Fulltext []Index
+}
+
+func (idx Index) entityType() EntityType {
+	if idx.EntityType == "" {
+		return IS_NODE
+	}
+	return idx.EntityType
+}
+
+// Equal reports whether two indexes cover the same entities and properties, regardless of their names
+func (idx Index) Equal(other Index) bool {
+	if idx.entityType() != other.entityType() || len(idx.Labels) != len(other.Labels) || len(idx.Properties) != len(other.Properties) {
+		return false
+	}
+	var labels, otherLabels []string = append([]string{}, idx.Labels...), append([]string{}, other.Labels...)
+	sort.Strings(labels)
+	sort.Strings(otherLabels)
+	for i := range labels {
+		if labels[i] != otherLabels[i] {
+			return false
+		}
+	}
+	for i := range idx.Properties {
+		if idx.Properties[i] != other.Properties[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (idx Index) String() string {
+	return fmt.Sprintf("%s %s(%s)", idx.entityType(), strings.Join(idx.Labels, "|"), strings.Join(idx.Properties, ", "))
+}
+
+// isManagedIndexType reports whether geno reads and manages indexes of a type. Others, such as lookup and vector
+// indexes, are left alone.
+func isManagedIndexType(t IndexType) bool {
+	switch t {
+	case RANGE_INDEX, BTREE_INDEX, TEXT_INDEX, POINT_INDEX, FULLTEXT_INDEX:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *Indexes) AddIndex(indexType IndexType, newIndex Index) error {
+	switch indexType {
+	case RANGE_INDEX, BTREE_INDEX:
+		i.Range = append(i.Range, newIndex)
+	case TEXT_INDEX:
+		i.Text = append(i.Text, newIndex)
+	case POINT_INDEX:
+		i.Point = append(i.Point, newIndex)
+	case FULLTEXT_INDEX:
+		i.Fulltext = append(i.Fulltext, newIndex)
+	default:
+		return fmt.Errorf("index type %s could not be added as an index", indexType)
+	}
+	return nil
+}
+
+// byType returns the indexes of each type in a stable order
+func (i *Indexes) byType() map[IndexType][]Index {
+	return map[IndexType][]Index{
+		RANGE_INDEX:    i.Range,
+		TEXT_INDEX:     i.Text,
+		POINT_INDEX:    i.Point,
+		FULLTEXT_INDEX: i.Fulltext,
+	}
+}
+
+// Each calls fn for every index, grouped by type in the order range, text, point, fulltext
+func (i *Indexes) Each(fn func(IndexType, Index) error) error {
+	all := i.byType()
+	for _, t := range []IndexType{RANGE_INDEX, TEXT_INDEX, POINT_INDEX, FULLTEXT_INDEX} {
+		for _, idx := range all[t] {
+			if err := fn(t, idx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Len returns the number of indexes of all types
+func (i *Indexes) Len() int {
+	return len(i.Range) + len(i.Text) + len(i.Point) + len(i.Fulltext)
+}
+
+// Covers reports whether a range index on the label or type can serve equality lookups on the given properties,
+// which is the case when every property of the index is among them
+func (i *Indexes) Covers(entityType EntityType, label string, properties []string) bool {
+	var available map[string]bool = make(map[string]bool, len(properties))
+	for _, p := range properties {
+		available[p] = true
+	}
+	for _, idx := range i.Range {
+		if idx.entityType() != entityType || len(idx.Properties) == 0 {
+			continue
+		}
+		var labelMatches bool
+		for _, l := range idx.Labels {
+			if l == label {
+				labelMatches = true
+			}
+		}
+		if !labelMatches {
+			continue
+		}
+		var covered bool = true
+		for _, p := range idx.Properties {
+			if !available[p] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffIndexes compares the desired indexes with those found in a database.
+// Indexes which back a constraint are never reported as extra.
+func DiffIndexes(desired, actual Indexes) (missing Indexes, extra Indexes) {
+	desiredByType := desired.byType()
+	actualByType := actual.byType()
+	contains := func(list []Index, idx Index) bool {
+		for _, other := range list {
+			if idx.Equal(other) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for t, list := range desiredByType {
+		for _, idx := range list {
+			if !contains(actualByType[t], idx) {
+				missing.AddIndex(t, idx)
+			}
+		}
+	}
+	for t, list := range actualByType {
+		for _, idx := range list {
+			if !idx.Owned && !contains(desiredByType[t], idx) {
+				extra.AddIndex(t, idx)
+			}
+		}
+	}
+	return missing, extra
+}
+
+// ToCypherCreate builds an idempotent statement creating the index
+func (idx Index) ToCypherCreate(indexType IndexType) (string, error) {
+	if len(idx.Labels) == 0 || len(idx.Properties) == 0 {
+		return "", fmt.Errorf("index %s must have at least one label and one property", idx)
+	}
+	if indexType != FULLTEXT_INDEX && len(idx.Labels) > 1 {
+		return "", fmt.Errorf("only fulltext indexes may span more than one label or type, %s does not", idx)
+	}
+	var (
+		q       strings.Builder = strings.Builder{}
+		labels  []string        = make([]string, len(idx.Labels))
+		props   []string        = make([]string, len(idx.Properties))
+		pattern string
+	)
+	for i, l := range idx.Labels {
+		labels[i] = escapeName(l)
+	}
+	for i, p := range idx.Properties {
+		props[i] = "e." + escapeName(p)
+	}
+	if idx.entityType() == IS_RELATIONSHIP {
+		pattern = "()-[e:" + strings.Join(labels, "|") + "]-()"
+	} else {
+		pattern = "(e:" + strings.Join(labels, "|") + ")"
+	}
+
+	q.WriteString("CREATE ")
+	q.WriteString(string(indexType))
+	q.WriteString(" INDEX ")
+	if idx.Name != "" {
+		q.WriteString(escapeName(idx.Name))
+		q.WriteString(" ")
+	}
+	q.WriteString("IF NOT EXISTS FOR ")
+	q.WriteString(pattern)
+	if indexType == FULLTEXT_INDEX {
+		q.WriteString(" ON EACH [")
+		q.WriteString(strings.Join(props, ", "))
+		q.WriteString("]")
+	} else {
+		q.WriteString(" ON (")
+		q.WriteString(strings.Join(props, ", "))
+		q.WriteString(")")
+	}
+	return q.String(), nil
+}
+
+// ToCypherDrop builds a statement dropping the index, which must be named
+func (idx Index) ToCypherDrop() (string, error) {
+	if idx.Name == "" {
+		return "", fmt.Errorf("index %s cannot be dropped without a name", idx)
+	}
+	return "DROP INDEX " + escapeName(idx.Name) + " IF EXISTS", nil
+}
+
+// IndexesFromRecords parses the output of SHOW INDEXES. Token lookup indexes, and those of types geno does not
+// manage, such as vector indexes, are skipped.
+func IndexesFromRecords(records []*db.Record) (Indexes, error) {
+	var i Indexes
+
+	for _, record := range records {
+		var (
+			idx       Index
+			indexType string
+			ok        bool
+		)
+		rawField, _ := record.Get("name")
+		idx.Name, _ = rawField.(string)
+
+		rawField, found := record.Get("type")
+		if !found {
+			return i, errors.New("an index was found which did not have a type")
+		}
+		indexType, ok = rawField.(string)
+		if !ok {
+			return i, errors.New("an index was found which did not have a properly formatted type")
+		}
+		if !isManagedIndexType(IndexType(indexType)) {
+			continue
+		}
+
+		rawField, found = record.Get("entityType")
+		if !found {
+			return i, errors.New("an index was found which did not have an entity type")
+		}
+		entityType, ok := rawField.(string)
+		if !ok {
+			return i, errors.New("an index was found which did not have a properly formatted entity type")
+		}
+		idx.EntityType = EntityType(entityType)
+
+		idx.Labels, ok = stringList(record, "labelsOrTypes")
+		if !ok {
+			return i, errors.New("an index was found which did not have a properly formatted label or type")
+		}
+		idx.Properties, ok = stringList(record, "properties")
+		if !ok {
+			return i, errors.New("an index was found which did not have a properly formatted list of properties")
+		}
+
+		// Neo4j 5 names the owning constraint, 4.4 only reports uniqueness
+		if owner, found := record.Get("owningConstraint"); found && owner != nil {
+			idx.Owned = true
+		}
+		if uniqueness, found := record.Get("uniqueness"); found && uniqueness == "UNIQUE" {
+			idx.Owned = true
+		}
+
+		if err := i.AddIndex(IndexType(indexType), idx); err != nil {
+			return i, err
+		}
+	}
+
+	return i, nil
+}
+
+func stringList(record *db.Record, key string) ([]string, bool) {
+	rawField, found := record.Get(key)
+	if !found {
+		return nil, false
+	}
+	listField, ok := rawField.([]any)
+	if !ok {
+		return nil, false
+	}
+	list := make([]string, len(listField))
+	for i, l := range listField {
+		s, ok := l.(string)
+		if !ok {
+			return nil, false
+		}
+		list[i] = s
+	}
+	return list, true
+}
+
+func (d *Driver) GetIndexes(database string) (Indexes, error) {
+	records, err := d.ReadRecords(database, "SHOW INDEXES", nil)
+	if err != nil {
+		return Indexes{}, err
+	}
+	return IndexesFromRecords(records)
+}
+
+// CreateIndex creates the index if an equivalent one does not already exist
+func (d *Driver) CreateIndex(database string, indexType IndexType, idx Index) error {
+	cypher, err := idx.ToCypherCreate(indexType)
+	if err != nil {
+		return err
+	}
+	return d.runSchema(database, cypher)
+}
+
+func (d *Driver) DropIndex(database string, idx Index) error {
+	cypher, err := idx.ToCypherDrop()
+	if err != nil {
+		return err
+	}
+	return d.runSchema(database, cypher)
+}
+
+// runSchema runs a single schema statement in its own write transaction
+func (d *Driver) runSchema(database, cypher string) error {
+	session := d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	defer session.Close()
+
+	_, err := session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		result, txErr := tx.Run(cypher, nil)
+		if txErr != nil {
+			return nil, txErr
+		}
+		return result.Consume()
+	})
+	return err
+}
@@ -0,0 +1,105 @@
+package geno
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+)
+
+func TestIndexesFromRecords(t *testing.T) {
+	columns := []string{"id", "name", "state", "type", "entityType", "labelsOrTypes", "properties", "owningConstraint"}
+	records := []*db.Record{
+		{Keys: columns, Values: []any{int64(1), "index_lookup", "ONLINE", "LOOKUP", "NODE", nil, nil, nil}},
+		{Keys: columns, Values: []any{int64(2), "customer_key", "ONLINE", "RANGE", "NODE", []any{"Customer"}, []any{"Key"}, "customer_unique"}},
+		{Keys: columns, Values: []any{int64(3), "names", "ONLINE", "FULLTEXT", "NODE", []any{"Customer", "Vendor"}, []any{"NAME1"}, nil}},
+		{Keys: columns, Values: []any{int64(4), "embeddings", "ONLINE", "VECTOR", "NODE", []any{"Document"}, []any{"embedding"}, nil}},
+	}
+	want := Indexes{
+		Range:    []Index{{Name: "customer_key", EntityType: IS_NODE, Labels: []string{"Customer"}, Properties: []string{"Key"}, Owned: true}},
+		Fulltext: []Index{{Name: "names", EntityType: IS_NODE, Labels: []string{"Customer", "Vendor"}, Properties: []string{"NAME1"}}},
+	}
+
+	got, err := IndexesFromRecords(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted\n%v\nbut got\n%v", want, got)
+	}
+}
+
+func TestDiffIndexes(t *testing.T) {
+	desired := Indexes{
+		Range: []Index{{Labels: []string{"Customer"}, Properties: []string{"Database", "KUNNR"}}},
+		Text:  []Index{{Labels: []string{"Name"}, Properties: []string{"Value"}}},
+	}
+	actual := Indexes{
+		Range: []Index{
+			{Name: "owned", EntityType: IS_NODE, Labels: []string{"Customer"}, Properties: []string{"Key"}, Owned: true},
+			{Name: "kunnr", EntityType: IS_NODE, Labels: []string{"Customer"}, Properties: []string{"Database", "KUNNR"}},
+			{Name: "stale", EntityType: IS_NODE, Labels: []string{"Vendor"}, Properties: []string{"LIFNR"}},
+		},
+	}
+
+	missing, extra := DiffIndexes(desired, actual)
+	if !reflect.DeepEqual(missing, Indexes{Text: desired.Text}) {
+		t.Errorf("wanted missing text index but got %v", missing)
+	}
+	if len(extra.Range) != 1 || extra.Range[0].Name != "stale" {
+		t.Errorf("wanted only the stale index as extra but got %v", extra)
+	}
+}
+
+func TestIndexesCovers(t *testing.T) {
+	indexes := Indexes{Range: []Index{{Labels: []string{"Customer"}, Properties: []string{"Key"}}}}
+	if !indexes.Covers(IS_NODE, "Customer", []string{"Database", "Key"}) {
+		t.Error("an index on Customer(Key) should cover merges on Key and Database")
+	}
+	if indexes.Covers(IS_NODE, "Customer", []string{"Database"}) {
+		t.Error("an index on Customer(Key) should not cover merges on Database alone")
+	}
+	if indexes.Covers(IS_NODE, "Vendor", []string{"Key"}) {
+		t.Error("an index on Customer should not cover Vendor")
+	}
+}
+
+func TestIndexToCypherCreate(t *testing.T) {
+	type test struct {
+		name      string
+		index     Index
+		indexType IndexType
+		want      string
+	}
+
+	tests := []test{
+		{
+			name:      "range",
+			index:     Index{Labels: []string{"Customer"}, Properties: []string{"Database", "KUNNR"}},
+			indexType: RANGE_INDEX,
+			want:      "CREATE RANGE INDEX IF NOT EXISTS FOR (e:`Customer`) ON (e.`Database`, e.`KUNNR`)",
+		},
+		{
+			name:      "relationship text",
+			index:     Index{Name: "since", EntityType: IS_RELATIONSHIP, Labels: []string{"HAS_PHONE"}, Properties: []string{"Since"}},
+			indexType: TEXT_INDEX,
+			want:      "CREATE TEXT INDEX `since` IF NOT EXISTS FOR ()-[e:`HAS_PHONE`]-() ON (e.`Since`)",
+		},
+		{
+			name:      "fulltext",
+			index:     Index{Name: "names", Labels: []string{"Customer", "Vendor"}, Properties: []string{"NAME1"}},
+			indexType: FULLTEXT_INDEX,
+			want:      "CREATE FULLTEXT INDEX `names` IF NOT EXISTS FOR (e:`Customer`|`Vendor`) ON EACH [e.`NAME1`]",
+		},
+	}
+
+	for _, tc := range tests {
+		got, err := tc.index.ToCypherCreate(tc.indexType)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if tc.want != got {
+			t.Errorf("%s: wanted\n%s\nbut got\n%s", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package geno
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+// CypherLiteral writes a value as a cypher expression evaluating to the same value, so that values can be
+// embedded in scripts, e.g. as a :param of cypher-shell. Temporal and spatial values are written as calls
+// to their constructor functions.
+func CypherLiteral(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return floatLiteral(float64(v))
+	case float64:
+		return floatLiteral(v)
+	case dbtype.Date:
+		return "date(" + quoteString(v.Time().Format("2006-01-02")) + ")"
+	case dbtype.LocalTime:
+		return "localtime(" + quoteString(v.Time().Format("15:04:05.999999999")) + ")"
+	case dbtype.LocalDateTime:
+		return "localdatetime(" + quoteString(v.Time().Format("2006-01-02T15:04:05.999999999")) + ")"
+	case dbtype.Time:
+		return "time(" + quoteString(v.Time().Format("15:04:05.999999999Z07:00")) + ")"
+	case time.Time:
+		return "datetime(" + quoteString(v.Format(time.RFC3339Nano)) + ")"
+	case dbtype.Duration:
+		return "duration(" + quoteString(v.String()) + ")"
+	case dbtype.Point2D:
+		return fmt.Sprintf("point({x: %s, y: %s, srid: %d})", floatLiteral(v.X), floatLiteral(v.Y), v.SpatialRefId)
+	case dbtype.Point3D:
+		return fmt.Sprintf("point({x: %s, y: %s, z: %s, srid: %d})", floatLiteral(v.X), floatLiteral(v.Y), floatLiteral(v.Z), v.SpatialRefId)
+	case map[string]any:
+		var (
+			keys  []string = make([]string, 0, len(v))
+			pairs []string = make([]string, 0, len(v))
+		)
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			pairs = append(pairs, escapeName(key)+": "+CypherLiteral(v[key]))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+
+	// lists of any element type
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		var items []string = make([]string, rv.Len())
+		for i := range items {
+			items[i] = CypherLiteral(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return quoteString(fmt.Sprint(val))
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s) + "'"
+}
+
+// floatLiteral always includes a decimal point or exponent, so that whole numbers remain floats
+func floatLiteral(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "0.0/0.0"
+	case math.IsInf(f, 1):
+		return "1.0/0.0"
+	case math.IsInf(f, -1):
+		return "-1.0/0.0"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package geno
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// FindNodesByKey reads, as n, the nodes with the label whose properties match any of the keys given, at most limit of
+// them in the order of their ids when limit is above 0. Every key must hold the same properties.
+func (d *Driver) FindNodesByKey(database, label string, keys []map[string]any, limit int) ([]*neo4j.Record, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var (
+		props   []string = make([]string, 0, len(keys[0]))
+		keyRows []any    = make([]any, len(keys))
+	)
+	for prop := range keys[0] {
+		props = append(props, prop)
+	}
+	for i, key := range keys {
+		keyRows[i] = key
+	}
+	return d.ReadRecords(database, findByKeyQuery(label, props, limit > 0), map[string]any{"keys": keyRows, "limit": limit})
+}
+
+func findByKeyQuery(label string, props []string, limited bool) string {
+	var conditions []string = make([]string, 0, len(props))
+	sort.Strings(props)
+	for _, prop := range props {
+		conditions = append(conditions, "n."+escapeName(prop)+" = key."+escapeName(prop))
+	}
+	var q string = "UNWIND $keys AS key\nMATCH (n:" + escapeName(label) + ")\nWHERE " + strings.Join(conditions, " AND ") + "\nRETURN DISTINCT n"
+	if limited {
+		q += " ORDER BY id(n) LIMIT $limit"
+	}
+	return q
+}
+
+// ExpandNodes reads, as n, r and m, every relationship of the nodes with the ids given, in either direction, along with
+// the node m at its other end. When types are given only relationships of those types are read, and when labels are
+// given only those reaching a node with any of the labels.
+func (d *Driver) ExpandNodes(database string, ids []int64, types, labels []string) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, expandQuery(types, labels), map[string]any{"ids": ids})
+}
+
+// ExpandNodesWithin reads what ExpandNodes does, but reaches at most limit nodes other than the known ones, those with
+// the lowest ids, so that no more than limit new nodes are ever read. Every record also holds, as truncated, whether
+// any reachable node was left out; when no relationship is read a single record holds null for n, r and m.
+func (d *Driver) ExpandNodesWithin(database string, ids, known []int64, types, labels []string, limit int) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, boundedExpandQuery(types, labels), map[string]any{"ids": ids, "known": known, "limit": limit})
+}
+
+func expandQuery(types, labels []string) string {
+	return expandMatch(types, labels, "") + "\nRETURN n, r, m ORDER BY id(n), id(r)"
+}
+
+// boundedExpandQuery first collects the ids of the nodes reachable which are not yet known, and then reads only the
+// relationships reaching a known node or one of the first $limit of them
+func boundedExpandQuery(types, labels []string) string {
+	return expandMatch(types, labels, "NOT id(m) IN $known") +
+		"\nWITH DISTINCT id(m) AS reachable ORDER BY reachable" +
+		"\nWITH collect(reachable) AS reachable" +
+		"\nWITH reachable[..$limit] + $known AS reached, size(reachable) > $limit AS truncated" +
+		"\nOPTIONAL " + expandMatch(types, labels, "id(m) IN reached") +
+		"\nRETURN n, r, m, truncated ORDER BY id(n), id(r)"
+}
+
+// expandMatch matches the relationships r of the nodes n with the ids given, of the types given and reaching a node m
+// with any of the labels given, which also satisfy the condition on m when one is given
+func expandMatch(types, labels []string, condition string) string {
+	var q strings.Builder
+	q.WriteString("MATCH (n)-[r")
+	for i, t := range types {
+		if i == 0 {
+			q.WriteString(":")
+		} else {
+			q.WriteString("|")
+		}
+		q.WriteString(escapeName(t))
+	}
+	q.WriteString("]-(m)\nWHERE id(n) IN $ids")
+	if condition != "" {
+		q.WriteString(" AND " + condition)
+	}
+	for i, label := range labels {
+		if i == 0 {
+			q.WriteString(" AND (")
+		} else {
+			q.WriteString(" OR ")
+		}
+		q.WriteString("m:" + escapeName(label))
+	}
+	if len(labels) > 0 {
+		q.WriteString(")")
+	}
+	return q.String()
+}
@@ -0,0 +1,49 @@
+package geno
+
+import "testing"
+
+func Test_findByKeyQuery(t *testing.T) {
+	want := "UNWIND $keys AS key\nMATCH (n:`Customer`)\nWHERE n.`Country` = key.`Country` AND n.`Key` = key.`Key`\nRETURN DISTINCT n"
+	if got := findByKeyQuery("Customer", []string{"Key", "Country"}, false); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+	want += " ORDER BY id(n) LIMIT $limit"
+	if got := findByKeyQuery("Customer", []string{"Key", "Country"}, true); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+}
+
+func Test_expandQuery(t *testing.T) {
+	type test struct {
+		name   string
+		types  []string
+		labels []string
+		want   string
+	}
+	var tests []test = []test{
+		{name: "unfiltered", want: "MATCH (n)-[r]-(m)\nWHERE id(n) IN $ids\nRETURN n, r, m ORDER BY id(n), id(r)"},
+		{
+			name:   "filtered",
+			types:  []string{"HAS_PHONE", "HAS_BANK"},
+			labels: []string{"Phone", "Bank"},
+			want:   "MATCH (n)-[r:`HAS_PHONE`|`HAS_BANK`]-(m)\nWHERE id(n) IN $ids AND (m:`Phone` OR m:`Bank`)\nRETURN n, r, m ORDER BY id(n), id(r)",
+		},
+	}
+	for _, tc := range tests {
+		if got := expandQuery(tc.types, tc.labels); got != tc.want {
+			t.Errorf("%s: wanted\n%s\nbut got\n%s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func Test_boundedExpandQuery(t *testing.T) {
+	want := "MATCH (n)-[r:`HAS_PHONE`]-(m)\nWHERE id(n) IN $ids AND NOT id(m) IN $known AND (m:`Phone`)" +
+		"\nWITH DISTINCT id(m) AS reachable ORDER BY reachable" +
+		"\nWITH collect(reachable) AS reachable" +
+		"\nWITH reachable[..$limit] + $known AS reached, size(reachable) > $limit AS truncated" +
+		"\nOPTIONAL MATCH (n)-[r:`HAS_PHONE`]-(m)\nWHERE id(n) IN $ids AND id(m) IN reached AND (m:`Phone`)" +
+		"\nRETURN n, r, m, truncated ORDER BY id(n), id(r)"
+	if got := boundedExpandQuery([]string{"HAS_PHONE"}, []string{"Phone"}); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+}
@@ -35,21 +35,39 @@ func NewNode(id int64, labels []string, props map[string]any) (n Node) {
 }
 
 func (n *Node) ToCypherMerge(constraints []string, paramPrefix string) (query string, params map[string]any) {
+	var nodeVariable string
+	params = make(map[string]any, len(n.Properties)) // all properties are eventually parameritized
+	if paramPrefix != "" {
+		nodeVariable = paramPrefix
+	} else {
+		nodeVariable = "n"
+	}
+
+	query = n.toCypherMerge(constraints, nodeVariable, func(key string) string { return "$" + paramPrefix + key })
+
+	for key, val := range n.Properties {
+		params[paramPrefix+key] = val
+	}
+
+	return query, params
+}
+
+// ToCypherUnwindMerge builds the same merge as ToCypherMerge, but reads property values from a map held by the row
+// variable, so that a single query can merge every node of a list with UNWIND. Every node merged this way must have the
+// same labels and property keys as n.
+func (n *Node) ToCypherUnwindMerge(constraints []string, row string) string {
+	return n.toCypherMerge(constraints, "n", rowRef(row))
+}
+
+func (n *Node) toCypherMerge(constraints []string, nodeVariable string, ref func(key string) string) string {
 	var (
 		q                          strings.Builder = strings.Builder{}
 		constrainedProps           map[string]any  = make(map[string]any)
 		constrainedPropsTemplate   []string
 		unconstrainedProps         map[string]any = make(map[string]any)
 		unconstrainedPropsTemplate []string
-		nodeVariable               string
 	)
-	sort.Strings(constraints)                        // for more stable testing/query generation
-	params = make(map[string]any, len(n.Properties)) // all properties are eventually parameritized
-	if paramPrefix != "" {
-		nodeVariable = paramPrefix
-	} else {
-		nodeVariable = "n"
-	}
+	sort.Strings(constraints) // for more stable testing/query generation
 
 	// segregate constrained props from unconstrained props
 	for key, val := range n.Properties {
@@ -67,8 +85,8 @@ func (n *Node) ToCypherMerge(constraints []string, paramPrefix string) (query st
 		}
 	}
 
-	constrainedPropsTemplate = templatizeProps(constrainedProps, ":", paramPrefix)
-	unconstrainedPropsTemplate = templatizeProps(unconstrainedProps, "=", paramPrefix)
+	constrainedPropsTemplate = templatizeRefs(constrainedProps, ":", ref)
+	unconstrainedPropsTemplate = templatizeRefs(unconstrainedProps, "=", ref)
 
 	q.WriteString("MERGE (")
 	q.WriteString(nodeVariable) // use the param prefix as the node variable (matters on the relationship side, but not much here)
@@ -86,21 +104,11 @@ func (n *Node) ToCypherMerge(constraints []string, paramPrefix string) (query st
 	}
 	q.WriteString("\n")
 
-	for key, val := range n.Properties {
-		params[paramPrefix+key] = val
-	}
-
-	return q.String(), params
+	return q.String()
 }
 
 func (n *Node) ToCypherMatch(constraints []string, paramPrefix string) (query string, params map[string]interface{}) {
-	var (
-		q                        strings.Builder = strings.Builder{}
-		constrainedProps         map[string]any  = make(map[string]any)
-		constrainedPropsTemplate []string
-		nodeVariable             string
-	)
-	sort.Strings(constraints)                        // for more stable testing/query generation
+	var nodeVariable string
 	params = make(map[string]any, len(n.Properties)) // all properties are eventually parameritized
 	if paramPrefix != "" {
 		nodeVariable = paramPrefix
@@ -108,21 +116,38 @@ func (n *Node) ToCypherMatch(constraints []string, paramPrefix string) (query st
 		nodeVariable = "n"
 	}
 
-	// segregate constrained props from unconstrained props
+	query = n.toCypherMatch(constraints, nodeVariable, func(key string) string { return "$" + paramPrefix + key })
+
+	for key, val := range n.constrainedProps(constraints) {
+		params[paramPrefix+key] = val
+	}
+
+	return query, params
+}
+
+// constrainedProps returns the properties of the node which are named by the constraints
+func (n *Node) constrainedProps(constraints []string) map[string]any {
+	var constrainedProps map[string]any = make(map[string]any)
 	for key, val := range n.Properties {
-		var isConstrained bool = false
 		for _, constrainedKey := range constraints {
 			if key == constrainedKey {
-				isConstrained = true
+				constrainedProps[key] = val
 				break
 			}
 		}
-		if isConstrained {
-			constrainedProps[key] = val
-		}
 	}
+	return constrainedProps
+}
 
-	constrainedPropsTemplate = templatizeProps(constrainedProps, ":", paramPrefix)
+func (n *Node) toCypherMatch(constraints []string, nodeVariable string, ref func(key string) string) string {
+	var (
+		q                        strings.Builder = strings.Builder{}
+		constrainedProps         map[string]any  = n.constrainedProps(constraints)
+		constrainedPropsTemplate []string
+	)
+	sort.Strings(constraints) // for more stable testing/query generation
+
+	constrainedPropsTemplate = templatizeRefs(constrainedProps, ":", ref)
 
 	q.WriteString("MATCH (")
 	q.WriteString(nodeVariable)
@@ -135,11 +160,7 @@ func (n *Node) ToCypherMatch(constraints []string, paramPrefix string) (query st
 	}
 	q.WriteString(")\n")
 
-	for key, val := range constrainedProps {
-		params[paramPrefix+key] = val
-	}
-
-	return q.String(), params
+	return q.String()
 }
 
 func (n *Node) ToCypherCreate(paramPrefix string) (query string, params map[string]interface{}) {
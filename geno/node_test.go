@@ -167,3 +167,11 @@ func TestNodeToCypherCreate(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeToCypherUnwindMerge(t *testing.T) {
+	want := "MERGE (n:TestLabel {ConstrainedProp1:row.`ConstrainedProp1`, ConstrainedProp2:row.`ConstrainedProp2`})\n" +
+		"ON CREATE SET n.UnconstrainedProp1=row.`UnconstrainedProp1`, n.UnconstrainedProp2=row.`UnconstrainedProp2`\n"
+	if got := testNode.ToCypherUnwindMerge([]string{"ConstrainedProp1", "ConstrainedProp2"}, "row"); got != want {
+		t.Errorf("wanted query \n%s\nbut got \n%s", want, got)
+	}
+}
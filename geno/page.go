@@ -0,0 +1,94 @@
+package geno
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// readStrings reads a single column of strings
+func (d *Driver) readStrings(database, cypher, column string) ([]string, error) {
+	records, err := d.ReadRecords(database, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+	var values []string = make([]string, 0, len(records))
+	for _, record := range records {
+		value, _ := record.Get(column)
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s %v is not a string", column, value)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// GetLabels returns every node label in use within a database, in order
+func (d *Driver) GetLabels(database string) ([]string, error) {
+	return d.readStrings(database, "CALL db.labels() YIELD label RETURN label ORDER BY label", "label")
+}
+
+// GetRelationshipTypes returns every relationship type in use within a database, in order
+func (d *Driver) GetRelationshipTypes(database string) ([]string, error) {
+	return d.readStrings(database, "CALL db.relationshipTypes() YIELD relationshipType RETURN relationshipType ORDER BY relationshipType", "relationshipType")
+}
+
+// PageNodesByLabel reads up to limit nodes, as n, whose first label in order is the one given, ordered by internal id
+// and starting after the id given. Each node is therefore read under exactly one of its labels.
+func (d *Driver) PageNodesByLabel(database, label string, after int64, limit int) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, "MATCH (n:"+escapeName(label)+") WHERE id(n) > $after AND NOT any(l IN labels(n) WHERE l < $label)\n"+
+		"RETURN n ORDER BY id(n) LIMIT $limit",
+		map[string]any{"label": label, "after": after, "limit": limit})
+}
+
+// PageRelationshipsByType reads up to limit relationships of a type between nodes with labels, as r, along with their
+// start and end nodes, as a and b, ordered by internal id and starting after the id given
+func (d *Driver) PageRelationshipsByType(database, relType string, after int64, limit int) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, "MATCH (a)-[r:"+escapeName(relType)+"]->(b) WHERE id(r) > $after AND size(labels(a)) > 0 AND size(labels(b)) > 0\n"+
+		"RETURN a, r, b ORDER BY id(r) LIMIT $limit",
+		map[string]any{"after": after, "limit": limit})
+}
+
+// PageNodes reads up to limit nodes of a label, as n, in order of the given property, or of their internal id when
+// it is empty, starting after the value given; a nil value starts from the first. Nodes without the property are not
+// read. When since is not nil only nodes whose timestamp property is at or after it are read.
+func (d *Driver) PageNodes(database, label, orderBy string, after any, limit int, timestamp string, since any) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, pageQuery("MATCH (n:"+escapeName(label)+")", "n", orderBy, after, timestamp, since),
+		map[string]any{"after": after, "limit": limit, "since": since})
+}
+
+// PageRelationships reads up to limit relationships of a type, as r, along with their start and end nodes, as a and b,
+// in order of their internal id and starting after the id given, as PageNodes does for nodes
+func (d *Driver) PageRelationships(database, relType string, after any, limit int, timestamp string, since any) ([]*neo4j.Record, error) {
+	return d.ReadRecords(database, pageQuery("MATCH (a)-[r:"+escapeName(relType)+"]->(b)", "r", "", after, timestamp, since),
+		map[string]any{"after": after, "limit": limit, "since": since})
+}
+
+func pageQuery(match, variable, orderBy string, after any, timestamp string, since any) string {
+	var (
+		cursor     string = "id(" + variable + ")"
+		conditions []string
+		returned   string = variable
+	)
+	if orderBy != "" {
+		cursor = variable + "." + escapeName(orderBy)
+		conditions = append(conditions, cursor+" IS NOT NULL")
+	}
+	if after != nil {
+		conditions = append(conditions, cursor+" > $after")
+	}
+	if since != nil {
+		conditions = append(conditions, variable+"."+escapeName(timestamp)+" >= $since")
+	}
+	if variable == "r" {
+		returned = "a, r, b"
+	}
+
+	var q string = match + "\n"
+	if len(conditions) > 0 {
+		q += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+	return q + "RETURN " + returned + " ORDER BY " + cursor + " LIMIT $limit"
+}
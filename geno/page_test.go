@@ -0,0 +1,37 @@
+package geno
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_pageQuery(t *testing.T) {
+	type test struct {
+		name     string
+		match    string
+		variable string
+		orderBy  string
+		after    any
+		since    any
+		want     string
+	}
+	var tests []test = []test{
+		{
+			name: "first page by id", match: "MATCH (n:`Customer`)", variable: "n",
+			want: "MATCH (n:`Customer`)\nRETURN n ORDER BY id(n) LIMIT $limit",
+		},
+		{
+			name: "later page by key since a timestamp", match: "MATCH (n:`Customer`)", variable: "n", orderBy: "KUNNR", after: "0000001", since: time.Now(),
+			want: "MATCH (n:`Customer`)\nWHERE n.`KUNNR` IS NOT NULL AND n.`KUNNR` > $after AND n.`UPDATED_AT` >= $since\nRETURN n ORDER BY n.`KUNNR` LIMIT $limit",
+		},
+		{
+			name: "relationships", match: "MATCH (a)-[r:`HAS_PHONE`]->(b)", variable: "r", after: int64(10),
+			want: "MATCH (a)-[r:`HAS_PHONE`]->(b)\nWHERE id(r) > $after\nRETURN a, r, b ORDER BY id(r) LIMIT $limit",
+		},
+	}
+	for _, tc := range tests {
+		if got := pageQuery(tc.match, tc.variable, tc.orderBy, tc.after, "UPDATED_AT", tc.since); got != tc.want {
+			t.Errorf("%s: wanted\n%s\nbut got\n%s", tc.name, tc.want, got)
+		}
+	}
+}
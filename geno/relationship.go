@@ -34,22 +34,64 @@ func NewRelationship(id int64, from, to Node, label string, props map[string]any
 
 func (r *Relationship) ToCypherMerge(leftNodeConstraints, rightNodeConstraints, relConstraints []string) (query string, params map[string]interface{}) {
 	var (
-		leftMatchQuery             string
-		leftMatchParams            map[string]any
-		rightMatchQuery            string
-		rightMatchParams           map[string]any
+		leftMatchParams  map[string]any
+		rightMatchParams map[string]any
+		paramPrefix      string = "rel"
+	)
+	params = make(map[string]interface{}) // check the length of the parameters at the end and panic if not lengths of r.Properties + leftMatchParams + rightMatchParams
+
+	_, leftMatchParams = r.Start.ToCypherMatch(leftNodeConstraints, "left")
+	_, rightMatchParams = r.End.ToCypherMatch(rightNodeConstraints, "right")
+
+	query = r.toCypherMerge(leftNodeConstraints, rightNodeConstraints, relConstraints,
+		func(key string) string { return "$left" + key },
+		func(key string) string { return "$right" + key },
+		func(key string) string { return "$" + paramPrefix + key })
+
+	for key, val := range r.Properties {
+		params[paramPrefix+key] = val
+	}
+	for key, val := range leftMatchParams {
+		params[key] = val //do not use the paramPrefix here, as it is statically set to "rel" and templatize should have already set them
+	}
+	for key, val := range rightMatchParams {
+		params[key] = val //do not use the paramPrefix here, as it is statically set to "rel" and templatize should have already set them
+	}
+
+	if len(params) != (len(r.Properties) + len(rightMatchParams) + len(leftMatchParams)) {
+		panic("Relationship templating failed because of mismatch in query parameters")
+	}
+
+	return query, params
+}
+
+// ToCypherUnwindMerge builds the same merge as ToCypherMerge, but reads values from a map held by the row variable
+// in the shape returned by UnwindRow, so that a single query can merge every relationship of a list with UNWIND.
+// Every relationship merged this way must have the same type, endpoint labels and property keys as r.
+func (r *Relationship) ToCypherUnwindMerge(leftNodeConstraints, rightNodeConstraints, relConstraints []string, row string) string {
+	return r.toCypherMerge(leftNodeConstraints, rightNodeConstraints, relConstraints,
+		rowRef(row+".left"), rowRef(row+".right"), rowRef(row+".properties"))
+}
+
+// UnwindRow returns the values read by ToCypherUnwindMerge: the constrained properties of either endpoint
+// and every property of the relationship
+func (r *Relationship) UnwindRow(leftNodeConstraints, rightNodeConstraints []string) map[string]any {
+	return map[string]any{
+		"left":       r.Start.constrainedProps(leftNodeConstraints),
+		"right":      r.End.constrainedProps(rightNodeConstraints),
+		"properties": r.Properties,
+	}
+}
+
+func (r *Relationship) toCypherMerge(leftNodeConstraints, rightNodeConstraints, relConstraints []string, leftRef, rightRef, relRef func(key string) string) string {
+	var (
 		q                          strings.Builder = strings.Builder{}
 		constrainedProps           map[string]any  = make(map[string]any)
 		constrainedPropsTemplate   []string
 		unconstrainedProps         map[string]any = make(map[string]any)
 		unconstrainedPropsTemplate []string
-		paramPrefix                string = "rel"
 	)
 	sort.Strings(relConstraints)
-	params = make(map[string]interface{}) // check the length of the parameters at the end and panic if not lengths of r.Properties + leftMatchParams + rightMatchParams
-
-	leftMatchQuery, leftMatchParams = r.Start.ToCypherMatch(leftNodeConstraints, "left")
-	rightMatchQuery, rightMatchParams = r.End.ToCypherMatch(rightNodeConstraints, "right")
 
 	// segregate constrained props from unconstrained props
 	for key, val := range r.Properties {
@@ -67,11 +109,11 @@ func (r *Relationship) ToCypherMerge(leftNodeConstraints, rightNodeConstraints,
 		}
 	}
 
-	constrainedPropsTemplate = templatizeProps(constrainedProps, ":", paramPrefix)
-	unconstrainedPropsTemplate = templatizeProps(unconstrainedProps, "=", paramPrefix)
+	constrainedPropsTemplate = templatizeRefs(constrainedProps, ":", relRef)
+	unconstrainedPropsTemplate = templatizeRefs(unconstrainedProps, "=", relRef)
 
-	q.WriteString(leftMatchQuery)
-	q.WriteString(rightMatchQuery)
+	q.WriteString(r.Start.toCypherMatch(leftNodeConstraints, "left", leftRef))
+	q.WriteString(r.End.toCypherMatch(rightNodeConstraints, "right", rightRef))
 	q.WriteString("MERGE (left)-[r:")
 	q.WriteString(r.String())
 	if len(constrainedProps) > 0 {
@@ -81,26 +123,12 @@ func (r *Relationship) ToCypherMerge(leftNodeConstraints, rightNodeConstraints,
 	}
 	q.WriteString("]-(right)")
 	if len(unconstrainedProps) > 0 {
-		q.WriteString("\nON CREATE SET n.")
-		q.WriteString(strings.Join(unconstrainedPropsTemplate, ", n."))
+		q.WriteString("\nON CREATE SET r.")
+		q.WriteString(strings.Join(unconstrainedPropsTemplate, ", r."))
 	}
 	q.WriteString("\n")
 
-	for key, val := range r.Properties {
-		params[paramPrefix+key] = val
-	}
-	for key, val := range leftMatchParams {
-		params[key] = val //do not use the paramPrefix here, as it is statically set to "rel" and templatize should have already set them
-	}
-	for key, val := range rightMatchParams {
-		params[key] = val //do not use the paramPrefix here, as it is statically set to "rel" and templatize should have already set them
-	}
-
-	if len(params) != (len(r.Properties) + len(rightMatchParams) + len(leftMatchParams)) {
-		panic("Relationship templating failed because of mismatch in query parameters")
-	}
-
-	return q.String(), params
+	return q.String()
 }
 
 func (r *Relationship) ToCypherMatch(leftNodeConstraints, rightNodeConstraints, relConstraints []string) (query string, params map[string]interface{}) {
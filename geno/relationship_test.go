@@ -38,6 +38,22 @@ MERGE (left)-[r:TypeA {Prop1:$relProp1, Prop2:$relProp2}]-(right)
 				"relProp2":   "Value2A",
 			},
 		},
+		{
+			name:        "unconstrained properties",
+			rel:         relA,
+			constraints: []string{"Prop1"},
+			wantedQuery: `MATCH (left:TypeA {Prop1:$leftProp1})
+MATCH (right:TypeB {Prop1:$rightProp1})
+MERGE (left)-[r:TypeA {Prop1:$relProp1}]-(right)
+ON CREATE SET r.Prop2=$relProp2
+`,
+			wantedParams: map[string]any{
+				"leftProp1":  "Value1A",
+				"rightProp1": "Value1B",
+				"relProp1":   "Value1A",
+				"relProp2":   "Value2A",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -117,3 +133,20 @@ CREATE (left)-[r:TypeA {Prop1:$relProp1, Prop2:$relProp2}]-(right)
 
 	}
 }
+
+func TestRelationshipToCypherUnwindMerge(t *testing.T) {
+	rel := NewRelationship(1, nodeA, nodeB, "TypeA", map[string]any{"Prop1": "Value1A"})
+	want := "MATCH (left:TypeA {Prop1:row.left.`Prop1`})\nMATCH (right:TypeB {Prop2:row.right.`Prop2`})\nMERGE (left)-[r:TypeA]-(right)\nON CREATE SET r.Prop1=row.properties.`Prop1`\n"
+	if got := rel.ToCypherUnwindMerge([]string{"Prop1"}, []string{"Prop2"}, []string{}, "row"); got != want {
+		t.Errorf("wanted query \n%s\nbut got \n%s", want, got)
+	}
+
+	wantRow := map[string]any{
+		"left":       map[string]any{"Prop1": "Value1A"},
+		"right":      map[string]any{"Prop2": "Value2B"},
+		"properties": map[string]any{"Prop1": "Value1A"},
+	}
+	if got := rel.UnwindRow([]string{"Prop1"}, []string{"Prop2"}); !reflect.DeepEqual(wantRow, got) {
+		t.Errorf("wanted row \n%v\nbut got \n%v", wantRow, got)
+	}
+}
@@ -0,0 +1,163 @@
+package geno
+
+import (
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// RestoreIdProperty holds the id an element had in the database it was backed up from while it is restored. Elements
+// without a key or uniqueness constraint are merged on it, so that each element of a backup is restored as exactly
+// one element, and relationships find their endpoints by it. ClearRestoreIds removes it once a restore is complete.
+const RestoreIdProperty string = "__geno_backup_id"
+
+// restoreIndexPrefix names the indexes created on RestoreIdProperty for the duration of a restore
+const restoreIndexPrefix string = "geno_restore_"
+
+// restoreKeys returns the properties a restored element is merged on: those of the key and uniqueness constraints
+// of its labels or type when it holds every one of them, otherwise its backup id. Existence constraints are not
+// keys, as elements sharing their values are still distinct.
+func restoreKeys(props map[string]any, names []string, constraints ...[]Constraint) []string {
+	var (
+		found map[string]bool = make(map[string]bool)
+		keys  []string
+	)
+	for _, name := range names {
+		for _, cs := range constraints {
+			for _, c := range cs {
+				if c.Label != name {
+					continue
+				}
+				for _, p := range c.Properties {
+					if !found[p] {
+						found[p] = true
+						keys = append(keys, p)
+					}
+				}
+			}
+		}
+	}
+	for _, key := range keys {
+		if _, present := props[key]; !present {
+			return []string{RestoreIdProperty}
+		}
+	}
+	if len(keys) == 0 {
+		return []string{RestoreIdProperty}
+	}
+	return keys
+}
+
+func (q *Query) restoreNodeKeys(n *Node) []string {
+	return restoreKeys(n.Properties, n.Labels, q.c.NodeKeys, q.c.NodeUniqueness)
+}
+
+func (q *Query) restoreRelationshipKeys(r *Relationship) []string {
+	return restoreKeys(r.Properties, []string{r.Label}, q.c.RelationshipKeys, q.c.RelationshipUniqueness)
+}
+
+// RestoreNodes merges nodes read from a backup, each holding its backup id under RestoreIdProperty. Nodes are merged
+// on their key and uniqueness constraints, or on their backup id when they have none, and the backup id is set on
+// every node merged, including existing ones, so that RestoreRelationships can find them. The policy decides what
+// happens to existing nodes as it does for MergeNodes.
+func (q *Query) RestoreNodes(database string, nodes []Node, policy MergePolicy, batchSize int, done func(batch []Node, summary neo4j.ResultSummary)) error {
+	return q.mergeNodes(database, nodes, policy, batchSize, q.restoreNodeKeys,
+		"SET n."+escapeName(RestoreIdProperty)+" = row."+escapeName(RestoreIdProperty)+"\n", done)
+}
+
+// RestoreRelationships merges relationships read from a backup between the nodes restored by RestoreNodes. Their
+// endpoints need only hold their labels and their backup id, under RestoreIdProperty, which is how they are found.
+// Relationships without a key or uniqueness constraint must hold their own backup id, on which they are merged.
+func (q *Query) RestoreRelationships(database string, rels []Relationship, policy MergePolicy, batchSize int, done func(batch []Relationship, summary neo4j.ResultSummary)) error {
+	return q.mergeRelationships(database, rels, policy, batchSize, func(n *Node) []string { return []string{RestoreIdProperty} },
+		q.restoreRelationshipKeys, done)
+}
+
+// CreateRestoreIndexes indexes RestoreIdProperty for each label and relationship type, so that restored relationships
+// find their endpoints, and ClearRestoreIds the elements holding it, without scanning every element of a label or
+// type, and waits for the indexes to come online. DropRestoreIndexes removes them.
+func (d *Driver) CreateRestoreIndexes(database string, labels, types []string) error {
+	for _, label := range labels {
+		if err := d.runSchema(database, "CREATE INDEX "+escapeName(restoreIndexPrefix+"node_"+label)+" IF NOT EXISTS FOR (e:"+
+			escapeName(label)+") ON (e."+escapeName(RestoreIdProperty)+")"); err != nil {
+			return err
+		}
+	}
+	for _, relType := range types {
+		if err := d.runSchema(database, "CREATE INDEX "+escapeName(restoreIndexPrefix+"relationship_"+relType)+" IF NOT EXISTS FOR ()-[e:"+
+			escapeName(relType)+"]-() ON (e."+escapeName(RestoreIdProperty)+")"); err != nil {
+			return err
+		}
+	}
+	return d.runSchema(database, "CALL db.awaitIndexes(300)")
+}
+
+// DropRestoreIndexes drops the indexes created by CreateRestoreIndexes
+func (d *Driver) DropRestoreIndexes(database string, labels, types []string) error {
+	var names []string
+	for _, label := range labels {
+		names = append(names, restoreIndexPrefix+"node_"+label)
+	}
+	for _, relType := range types {
+		names = append(names, restoreIndexPrefix+"relationship_"+relType)
+	}
+	for _, name := range names {
+		if err := d.runSchema(database, "DROP INDEX "+escapeName(name)+" IF EXISTS"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearRestoreIds removes RestoreIdProperty from every node of the labels and relationship of the types given,
+// batchSize elements per transaction
+func (d *Driver) ClearRestoreIds(database string, labels, types []string, batchSize int) error {
+	if batchSize < 1 {
+		return errors.New("the batch size must be at least 1")
+	}
+	var matches []string
+	for _, label := range labels {
+		matches = append(matches, "MATCH (e:"+escapeName(label)+")")
+	}
+	for _, relType := range types {
+		matches = append(matches, "MATCH ()-[e:"+escapeName(relType)+"]->()")
+	}
+	var property string = escapeName(RestoreIdProperty)
+	for _, match := range matches {
+		for {
+			removed, err := d.runCount(database, match+" WHERE e."+property+" IS NOT NULL\nWITH e LIMIT $limit\nREMOVE e."+property+"\nRETURN count(e) AS removed",
+				map[string]any{"limit": batchSize})
+			if err != nil {
+				return err
+			}
+			if removed < int64(batchSize) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// runCount runs a write returning a single count, as removed, in its own transaction
+func (d *Driver) runCount(database, cypher string, params map[string]any) (int64, error) {
+	session := d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+	defer session.Close()
+
+	removed, err := session.WriteTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		result, txErr := tx.Run(cypher, params)
+		if txErr != nil {
+			return nil, txErr
+		}
+		record, txErr := result.Single()
+		if txErr != nil {
+			return nil, txErr
+		}
+		count, _ := record.Get("removed")
+		return count, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	count, _ := removed.(int64)
+	return count, nil
+}
@@ -0,0 +1,37 @@
+package geno
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_restoreKeys(t *testing.T) {
+	q := Query{c: &Constraints{
+		NodeKeys:              []Constraint{{Label: "Customer", Properties: []string{"KUNNR"}}},
+		NodeUniqueness:        []Constraint{{Label: "Customer", Properties: []string{"EMAIL"}}},
+		NodePropertyExistence: []Constraint{{Label: "Note", Properties: []string{"Text"}}},
+	}}
+
+	customer := NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "1", "EMAIL": "a@example.com", RestoreIdProperty: int64(1)})
+	got := q.restoreNodeKeys(&customer)
+	sort.Strings(got)
+	if want := []string{"EMAIL", "KUNNR"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted nodes merged on their key and uniqueness constraints, %v, but got %v", want, got)
+	}
+
+	partial := NewNode(2, []string{"Customer"}, map[string]any{"KUNNR": "2", RestoreIdProperty: int64(2)})
+	if got := q.restoreNodeKeys(&partial); !reflect.DeepEqual(got, []string{RestoreIdProperty}) {
+		t.Errorf("wanted a node missing a constrained property merged on its backup id, but got %v", got)
+	}
+
+	note := NewNode(3, []string{"Note"}, map[string]any{"Text": "call back", RestoreIdProperty: int64(3)})
+	if got := q.restoreNodeKeys(&note); !reflect.DeepEqual(got, []string{RestoreIdProperty}) {
+		t.Errorf("wanted existence constraints not used as keys, but got %v", got)
+	}
+
+	rel := NewRelationship(4, customer, note, "HAS_NOTE", map[string]any{"Since": "2020", RestoreIdProperty: int64(4)})
+	if got := q.restoreRelationshipKeys(&rel); !reflect.DeepEqual(got, []string{RestoreIdProperty}) {
+		t.Errorf("wanted relationships without constraints merged on their backup id, but got %v", got)
+	}
+}
@@ -0,0 +1,268 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+const PROPERTY_RULE_CONSTRAINT ConstraintType = "PROPERTY_RULE"
+
+// dateLayouts are the formats accepted for dates held as strings, including the SAP DATS format
+var dateLayouts []string = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02", "20060102"}
+
+var (
+	regexCache   map[string]*regexp.Regexp = make(map[string]*regexp.Regexp)
+	regexCacheMu sync.Mutex
+)
+
+// PropertyRule restricts the values a property of a node label or relationship type may take.
+// Every rule other than NonEmpty ignores missing and null values.
+type PropertyRule struct {
+	Label      string     `json:"Label" yaml:"Label"`
+	EntityType EntityType `json:"EntityType,omitempty" yaml:"EntityType,omitempty"` // defaults to NODE
+	Property   string     `json:"Property" yaml:"Property"`
+	Regex      string     `json:"Regex,omitempty" yaml:"Regex,omitempty"`         // must match the whole value
+	Enum       []string   `json:"Enum,omitempty" yaml:"Enum,omitempty"`           // allowed values, compared as strings
+	Min        *float64   `json:"Min,omitempty" yaml:"Min,omitempty"`             // inclusive numeric range
+	Max        *float64   `json:"Max,omitempty" yaml:"Max,omitempty"`             //
+	After      string     `json:"After,omitempty" yaml:"After,omitempty"`         // inclusive date range, e.g. 2000-01-01
+	Before     string     `json:"Before,omitempty" yaml:"Before,omitempty"`       //
+	MaxLength  int        `json:"MaxLength,omitempty" yaml:"MaxLength,omitempty"` // characters of a string or items of a list
+	NonEmpty   bool       `json:"NonEmpty,omitempty" yaml:"NonEmpty,omitempty"`
+}
+
+func (r PropertyRule) entityType() EntityType {
+	if r.EntityType == "" {
+		return IS_NODE
+	}
+	return r.EntityType
+}
+
+// Check returns a description of every way in which the value breaks the rule
+func (r PropertyRule) Check(val any, found bool) []string {
+	var problems []string
+
+	if !found || val == nil {
+		if r.NonEmpty {
+			problems = append(problems, "value is missing")
+		}
+		return problems
+	}
+	if r.NonEmpty {
+		if s, ok := val.(string); ok && strings.TrimSpace(s) == "" {
+			problems = append(problems, "value is empty")
+		}
+	}
+
+	if r.Regex != "" {
+		re, err := compileRule(r.Regex)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if !re.MatchString(fmt.Sprint(val)) {
+			problems = append(problems, fmt.Sprintf("value %v does not match %s", val, r.Regex))
+		}
+	}
+
+	if len(r.Enum) > 0 {
+		var allowed bool
+		for _, e := range r.Enum {
+			if fmt.Sprint(val) == e {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			problems = append(problems, fmt.Sprintf("value %v is not one of %s", val, strings.Join(r.Enum, ", ")))
+		}
+	}
+
+	if r.Min != nil || r.Max != nil {
+		f, ok := numericValue(val)
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("value %v is not a number", val))
+		case r.Min != nil && f < *r.Min:
+			problems = append(problems, fmt.Sprintf("value %v is less than %v", val, *r.Min))
+		case r.Max != nil && f > *r.Max:
+			problems = append(problems, fmt.Sprintf("value %v is greater than %v", val, *r.Max))
+		}
+	}
+
+	if r.After != "" || r.Before != "" {
+		t, ok := dateValue(val)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("value %v is not a date", val))
+		} else {
+			if after, ok := dateValue(r.After); r.After != "" && !ok {
+				problems = append(problems, fmt.Sprintf("rule date %s is not a date", r.After))
+			} else if ok && t.Before(after) {
+				problems = append(problems, fmt.Sprintf("value %v is before %s", val, r.After))
+			}
+			if before, ok := dateValue(r.Before); r.Before != "" && !ok {
+				problems = append(problems, fmt.Sprintf("rule date %s is not a date", r.Before))
+			} else if ok && t.After(before) {
+				problems = append(problems, fmt.Sprintf("value %v is after %s", val, r.Before))
+			}
+		}
+	}
+
+	if r.MaxLength > 0 {
+		var length int
+		if s, ok := val.(string); ok {
+			length = len([]rune(s))
+		} else if rv := reflect.ValueOf(val); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			length = rv.Len()
+		} else {
+			length = len([]rune(fmt.Sprint(val)))
+		}
+		if length > r.MaxLength {
+			problems = append(problems, fmt.Sprintf("value %v is longer than %d", val, r.MaxLength))
+		}
+	}
+
+	return problems
+}
+
+// Validate returns an error when the rule cannot be checked, such as when its regular expression does not compile
+// or its dates cannot be read
+func (r PropertyRule) Validate() error {
+	if r.Regex != "" {
+		if _, err := compileRule(r.Regex); err != nil {
+			return fmt.Errorf("%s.%s: %w", r.Label, r.Property, err)
+		}
+	}
+	for _, bound := range []string{r.After, r.Before} {
+		if _, ok := dateValue(bound); bound != "" && !ok {
+			return fmt.Errorf("%s.%s: rule date %s is not one of the formats 2006-01-02, 20060102 or RFC 3339", r.Label, r.Property, bound)
+		}
+	}
+	return nil
+}
+
+func compileRule(expr string) (*regexp.Regexp, error) {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
+	if re, found := regexCache[expr]; found {
+		return re, nil
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("rule %s is not a valid regular expression", expr)
+	}
+	regexCache[expr] = re
+	return re, nil
+}
+
+func numericValue(val any) (float64, bool) {
+	switch t := val.(type) {
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return interfaceToFloat(t), true
+	default:
+		return 0, false
+	}
+}
+
+func dateValue(val any) (time.Time, bool) {
+	switch t := val.(type) {
+	case time.Time:
+		return t, true
+	case dbtype.Date:
+		return t.Time(), true
+	case dbtype.LocalDateTime:
+		return t.Time(), true
+	case string:
+		for _, layout := range dateLayouts {
+			if parsed, err := time.Parse(layout, strings.TrimSpace(t)); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func checkPropertyRules(entityType EntityType, label string, props map[string]any, rules []PropertyRule) []Violation {
+	var violations []Violation
+	for _, r := range rules {
+		if r.Label != label || r.entityType() != entityType {
+			continue
+		}
+		val, found := props[r.Property]
+		for _, problem := range r.Check(val, found) {
+			violations = append(violations, Violation{
+				Constraint: PROPERTY_RULE_CONSTRAINT,
+				Label:      label,
+				Property:   r.Property,
+				Message:    problem,
+			})
+		}
+	}
+	return violations
+}
+
+// AuditPropertyRule pages through every node (or relationship) of the rule's label and checks the property
+// with the same rule used during import, returning up to limit violations identified by the given keys.
+// Nodes are read pageSize at a time, which must be at least 1.
+func (d *Driver) AuditPropertyRule(database string, rule PropertyRule, keys []string, limit, pageSize int) ([]Violation, error) {
+	if pageSize < 1 {
+		return nil, errors.New("the page size must be at least 1")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	var (
+		pattern    string
+		projection []string = make([]string, len(keys))
+		violations []Violation
+		after      int64 = -1
+	)
+	if rule.entityType() == IS_RELATIONSHIP {
+		pattern = "()-[e:" + escapeName(rule.Label) + "]->()"
+	} else {
+		pattern = "(e:" + escapeName(rule.Label) + ")"
+	}
+	for i, key := range keys {
+		projection[i] = "." + escapeName(key)
+	}
+	cypher := fmt.Sprintf(`MATCH %s WHERE id(e) > $after
+RETURN id(e) AS id, e.%s AS value, e {%s} AS key
+ORDER BY id(e) LIMIT $page`, pattern, escapeName(rule.Property), strings.Join(projection, ", "))
+
+	for len(violations) < limit {
+		records, err := d.ReadRecords(database, cypher, map[string]any{"after": after, "page": pageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			rawId, _ := record.Get("id")
+			after, _ = rawId.(int64)
+			val, _ := record.Get("value")
+			key, _ := record.Get("key")
+			for _, problem := range rule.Check(val, val != nil) {
+				violations = append(violations, Violation{
+					Constraint: PROPERTY_RULE_CONSTRAINT,
+					Label:      rule.Label,
+					Property:   rule.Property,
+					Message:    fmt.Sprintf("%s %v %v: %s", rule.entityType(), after, key, problem),
+				})
+			}
+		}
+		if len(records) < pageSize {
+			break
+		}
+	}
+	if len(violations) > limit {
+		violations = violations[:limit]
+	}
+	return violations, nil
+}
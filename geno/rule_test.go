@@ -0,0 +1,72 @@
+package geno
+
+import "testing"
+
+func TestPropertyRuleCheck(t *testing.T) {
+	var (
+		zero    float64 = 0
+		hundred float64 = 100
+	)
+
+	type test struct {
+		name  string
+		rule  PropertyRule
+		value any
+		found bool
+		want  int
+	}
+
+	tests := []test{
+		{name: "regex match", rule: PropertyRule{Regex: "[0-9]{10}"}, value: "0249697900", found: true, want: 0},
+		{name: "regex must match whole value", rule: PropertyRule{Regex: "[0-9]{10}"}, value: "0249697900X", found: true, want: 1},
+		{name: "enum", rule: PropertyRule{Enum: []string{"Hybrid_Germany"}}, value: "Hybrid_France", found: true, want: 1},
+		{name: "numeric range", rule: PropertyRule{Min: &zero, Max: &hundred}, value: float64(101), found: true, want: 1},
+		{name: "numeric string", rule: PropertyRule{Min: &zero}, value: "42", found: true, want: 0},
+		{name: "not a number", rule: PropertyRule{Max: &hundred}, value: "abc", found: true, want: 1},
+		{name: "SAP date in range", rule: PropertyRule{After: "2000-01-01", Before: "2030-12-31"}, value: "20221017", found: true, want: 0},
+		{name: "date before range", rule: PropertyRule{After: "2000-01-01"}, value: "1999-12-31", found: true, want: 1},
+		{name: "max length", rule: PropertyRule{MaxLength: 3}, value: "abcd", found: true, want: 1},
+		{name: "non empty missing", rule: PropertyRule{NonEmpty: true}, found: false, want: 1},
+		{name: "non empty blank", rule: PropertyRule{NonEmpty: true}, value: "  ", found: true, want: 1},
+		{name: "missing values are otherwise ignored", rule: PropertyRule{Regex: "[0-9]+"}, found: false, want: 0},
+	}
+
+	for _, tc := range tests {
+		got := tc.rule.Check(tc.value, tc.found)
+		if len(got) != tc.want {
+			t.Errorf("%s: wanted %d problem(s) but got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestValidateNodePropertyRules(t *testing.T) {
+	c := Constraints{PropertyRules: []PropertyRule{
+		{Label: "Customer", Property: "KUNNR", Regex: "[0-9]{10}"},
+		{Label: "HAS_BANK", EntityType: IS_RELATIONSHIP, Property: "KUNNR", Regex: "[a-z]+"},
+	}}
+
+	n := NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "12345"})
+	if got := c.ValidateNode(&n); len(got) != 1 {
+		t.Errorf("wanted one violation but got %v", got)
+	}
+}
+
+func TestPropertyRuleValidate(t *testing.T) {
+	if err := (PropertyRule{Label: "HAS_BANK", Property: "ValidFrom", After: "1990-01-01", Before: "20301231"}).Validate(); err != nil {
+		t.Errorf("wanted dates in the accepted formats to be valid, but got %v", err)
+	}
+	if err := (PropertyRule{Label: "HAS_BANK", Property: "ValidFrom", After: "01.01.1990"}).Validate(); err == nil {
+		t.Error("wanted a date which cannot be read to be rejected")
+	}
+	if err := (PropertyRule{Label: "Customer", Property: "KUNNR", Regex: "[0-9"}).Validate(); err == nil {
+		t.Error("wanted a regular expression which does not compile to be rejected")
+	}
+
+	c := Constraints{PropertyRules: []PropertyRule{{Label: "Customer", Property: "ERDAT", Before: "tomorrow"}}}
+	if err := c.Validate(); err == nil {
+		t.Error("wanted the constraints holding an invalid rule to be rejected")
+	}
+	if got := c.PropertyRules[0].Check("2020-01-01", true); len(got) != 1 {
+		t.Errorf("wanted a date which cannot be read reported rather than ignored, but got %v", got)
+	}
+}
@@ -0,0 +1,285 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+type ScriptCommand string
+
+const (
+	SCRIPT_CYPHER   ScriptCommand = ""
+	SCRIPT_PARAM    ScriptCommand = ":param"
+	SCRIPT_BEGIN    ScriptCommand = ":begin"
+	SCRIPT_COMMIT   ScriptCommand = ":commit"
+	SCRIPT_ROLLBACK ScriptCommand = ":rollback"
+	SCRIPT_USE      ScriptCommand = ":use"
+)
+
+// ScriptStatement is a single cypher statement or cypher-shell directive of a script
+type ScriptStatement struct {
+	Line    int           // line of the script the statement starts on
+	Command ScriptCommand // SCRIPT_CYPHER for cypher statements, otherwise the directive
+	Text    string        // the cypher statement, or the arguments of the directive
+}
+
+func (s ScriptStatement) String() string {
+	if s.Command == SCRIPT_CYPHER {
+		return s.Text
+	}
+	return strings.TrimSpace(string(s.Command) + " " + s.Text)
+}
+
+// ParseScript splits a cypher script, as accepted by cypher-shell, into statements. Cypher statements end with
+// a semicolon outside of strings, quoted names and comments. Directives start with a colon and end with their line;
+// :param, :params, :begin, :commit, :rollback and :use are understood.
+func ParseScript(r io.Reader) ([]ScriptStatement, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		src        []rune = []rune(string(raw))
+		statements []ScriptStatement
+		current    strings.Builder
+		line       int = 1
+		startLine  int = 0
+		quote      rune
+	)
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			statements = append(statements, ScriptStatement{Line: startLine, Command: SCRIPT_CYPHER, Text: text})
+		}
+		current.Reset()
+		startLine = 0
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		next := rune(0)
+		if i+1 < len(src) {
+			next = src[i+1]
+		}
+
+		switch {
+		case quote != 0:
+			current.WriteRune(c)
+			if c == '\n' {
+				line++
+			}
+			if c == '\\' && quote != '`' && next != 0 {
+				current.WriteRune(next)
+				i++
+				if next == '\n' {
+					line++
+				}
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '/' && next == '/':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			i-- // leave the newline to be counted
+		case c == '/' && next == '*':
+			j := i + 2
+			for ; j+1 < len(src) && !(src[j] == '*' && src[j+1] == '/'); j++ {
+				if src[j] == '\n' {
+					line++
+				}
+			}
+			if j+1 >= len(src) {
+				return nil, fmt.Errorf("line %d: comment is never closed", line)
+			}
+			i = j + 1
+			current.WriteRune(' ')
+		case c == ':' && strings.TrimSpace(current.String()) == "":
+			end := i
+			for end < len(src) && src[end] != '\n' {
+				end++
+			}
+			directive := strings.TrimSpace(string(src[i:end]))
+			directive = strings.TrimSpace(strings.TrimSuffix(directive, ";"))
+			statement, err := parseDirective(directive, line)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, statement)
+			current.Reset()
+			i = end - 1
+		case c == ';':
+			flush()
+		default:
+			if c == '\'' || c == '"' || c == '`' {
+				quote = c
+			}
+			if startLine == 0 && !isSpace(c) {
+				startLine = line
+			}
+			if c == '\n' {
+				line++
+			}
+			current.WriteRune(c)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("line %d: %c is never closed", startLine, quote)
+	}
+	flush()
+	return statements, nil
+}
+
+func isSpace(c rune) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+
+func parseDirective(directive string, line int) (ScriptStatement, error) {
+	name, args, _ := strings.Cut(directive, " ")
+	switch strings.ToLower(name) {
+	case ":param", ":params":
+		return ScriptStatement{Line: line, Command: SCRIPT_PARAM, Text: strings.TrimSpace(args)}, nil
+	case ":begin":
+		return ScriptStatement{Line: line, Command: SCRIPT_BEGIN}, nil
+	case ":commit":
+		return ScriptStatement{Line: line, Command: SCRIPT_COMMIT}, nil
+	case ":rollback":
+		return ScriptStatement{Line: line, Command: SCRIPT_ROLLBACK}, nil
+	case ":use":
+		return ScriptStatement{Line: line, Command: SCRIPT_USE, Text: strings.Trim(strings.TrimSpace(args), "`")}, nil
+	default:
+		return ScriptStatement{}, fmt.Errorf("line %d: directive %s is not supported", line, name)
+	}
+}
+
+// paramExpression turns the arguments of :param into an expression evaluating to a map of parameters.
+// Both `name => expression` and `{name: expression}` are accepted.
+func paramExpression(args string) (string, error) {
+	if strings.HasPrefix(args, "{") {
+		return args, nil
+	}
+	name, expr, found := strings.Cut(args, "=>")
+	if !found {
+		name, expr, found = strings.Cut(args, " ")
+	}
+	name = strings.TrimSpace(name)
+	if !found || name == "" || strings.TrimSpace(expr) == "" {
+		return "", fmt.Errorf(":param %s is not formatted as <NAME> => <EXPRESSION> or {<NAME>: <EXPRESSION>}", args)
+	}
+	return "{" + escapeName(strings.Trim(name, "`")) + ": " + strings.TrimSpace(expr) + "}", nil
+}
+
+// ScriptProgress is called after each statement of a script is run; err is the error it failed with, if any
+type ScriptProgress func(statement ScriptStatement, summary neo4j.ResultSummary, err error)
+
+// RunScript executes statements in order against a database, as cypher-shell would. Cypher statements outside of
+// :begin and :commit are committed individually. Parameters set by :param are passed to every later statement,
+// and :use changes the database later statements run against. Execution stops at the first failing statement;
+// an open transaction is rolled back.
+func (d *Driver) RunScript(database string, statements []ScriptStatement, progress ScriptProgress) error {
+	var (
+		params  map[string]any = make(map[string]any)
+		session neo4j.Session  = d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: database})
+		tx      neo4j.Transaction
+	)
+	defer func() {
+		if tx != nil {
+			tx.Rollback()
+			tx.Close()
+		}
+		session.Close()
+	}()
+
+	for _, statement := range statements {
+		var (
+			summary neo4j.ResultSummary
+			err     error
+		)
+		switch statement.Command {
+		case SCRIPT_CYPHER:
+			var result neo4j.Result
+			if tx != nil {
+				result, err = tx.Run(statement.Text, params)
+			} else {
+				result, err = session.Run(statement.Text, params)
+			}
+			if err == nil {
+				summary, err = result.Consume()
+			}
+		case SCRIPT_PARAM:
+			var expr string
+			expr, err = paramExpression(statement.Text)
+			if err == nil {
+				err = evaluateParams(session, tx, expr, params)
+			}
+		case SCRIPT_BEGIN:
+			if tx != nil {
+				err = errors.New("a transaction is already open")
+				break
+			}
+			tx, err = session.BeginTransaction()
+		case SCRIPT_COMMIT, SCRIPT_ROLLBACK:
+			if tx == nil {
+				err = errors.New("there is no open transaction")
+				break
+			}
+			if statement.Command == SCRIPT_COMMIT {
+				err = tx.Commit()
+			} else {
+				err = tx.Rollback()
+			}
+			tx.Close()
+			tx = nil
+		case SCRIPT_USE:
+			if tx != nil {
+				err = errors.New("the database cannot be changed within a transaction")
+				break
+			}
+			session.Close()
+			session = d.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite, DatabaseName: statement.Text})
+		default:
+			err = fmt.Errorf("directive %s is not supported", statement.Command)
+		}
+
+		if progress != nil {
+			progress(statement, summary, err)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", statement.Line, err)
+		}
+	}
+	if tx != nil {
+		return errors.New("the script ended without committing its last transaction, which was rolled back")
+	}
+	return nil
+}
+
+// evaluateParams evaluates a map expression on the server, as cypher-shell does, and adds its entries to params
+func evaluateParams(session neo4j.Session, tx neo4j.Transaction, expr string, params map[string]any) error {
+	var (
+		result neo4j.Result
+		err    error
+		q      string = "RETURN " + expr + " AS params"
+	)
+	if tx != nil {
+		result, err = tx.Run(q, params)
+	} else {
+		result, err = session.Run(q, params)
+	}
+	if err != nil {
+		return err
+	}
+	record, err := result.Single()
+	if err != nil {
+		return err
+	}
+	values, ok := record.Values[0].(map[string]any)
+	if !ok {
+		return fmt.Errorf("parameters %s did not evaluate to a map", expr)
+	}
+	for key, val := range values {
+		params[key] = val
+	}
+	return nil
+}
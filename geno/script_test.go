@@ -0,0 +1,92 @@
+package geno
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+func TestParseScript(t *testing.T) {
+	const script = `// create the constraint first
+CREATE CONSTRAINT IF NOT EXISTS FOR (e:Customer) REQUIRE e.KUNNR IS UNIQUE;
+:param rows => [{KUNNR: '1;2', NAME1: "it's"}]
+:begin
+UNWIND $rows AS row /* a comment; with a semicolon */
+MERGE (n:Customer {KUNNR: row.KUNNR});
+:commit
+:use other
+MATCH (n:` + "`odd;label`" + `) RETURN n`
+
+	want := []ScriptStatement{
+		{Line: 2, Command: SCRIPT_CYPHER, Text: "CREATE CONSTRAINT IF NOT EXISTS FOR (e:Customer) REQUIRE e.KUNNR IS UNIQUE"},
+		{Line: 3, Command: SCRIPT_PARAM, Text: `rows => [{KUNNR: '1;2', NAME1: "it's"}]`},
+		{Line: 4, Command: SCRIPT_BEGIN},
+		{Line: 5, Command: SCRIPT_CYPHER, Text: "UNWIND $rows AS row  \nMERGE (n:Customer {KUNNR: row.KUNNR})"},
+		{Line: 7, Command: SCRIPT_COMMIT},
+		{Line: 8, Command: SCRIPT_USE, Text: "other"},
+		{Line: 9, Command: SCRIPT_CYPHER, Text: "MATCH (n:`odd;label`) RETURN n"},
+	}
+
+	got, err := ParseScript(strings.NewReader(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted\n%#v\nbut got\n%#v", want, got)
+	}
+
+	for _, bad := range []string{"MATCH (n) WHERE n.name = 'open", ":schema", "MATCH (n) /* open"} {
+		if _, err := ParseScript(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: wanted an error", bad)
+		}
+	}
+}
+
+func TestParamExpression(t *testing.T) {
+	tests := map[string]string{
+		"rows => [1, 2]":    "{`rows`: [1, 2]}",
+		"limit 10":          "{`limit`: 10}",
+		"{a: 1, b: 'two'}":  "{a: 1, b: 'two'}",
+		"`odd name` => 'x'": "{`odd name`: 'x'}",
+	}
+	for args, want := range tests {
+		got, err := paramExpression(args)
+		if err != nil {
+			t.Errorf("%s: %v", args, err)
+		}
+		if got != want {
+			t.Errorf("%s: wanted %s but got %s", args, want, got)
+		}
+	}
+	if _, err := paramExpression("rows"); err == nil {
+		t.Error("a :param without a value should be an error")
+	}
+}
+
+func TestCypherLiteral(t *testing.T) {
+	tests := []struct {
+		val  any
+		want string
+	}{
+		{nil, "null"},
+		{"it's \\ here", `'it\'s \\ here'`},
+		{int64(3), "3"},
+		{3.0, "3.0"},
+		{1.5e300, "1.5e+300"},
+		{true, "true"},
+		{[]any{"a", int64(1)}, "['a', 1]"},
+		{[]string{"a"}, "['a']"},
+		{map[string]any{"b": 1, "a b": "x"}, "{`a b`: 'x', `b`: 1}"},
+		{dbtype.Date(time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)), "date('2022-03-04')"},
+		{time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC), "datetime('2022-03-04T05:06:07Z')"},
+		{dbtype.Point2D{X: 1, Y: 2.5, SpatialRefId: 7203}, "point({x: 1.0, y: 2.5, srid: 7203})"},
+	}
+	for _, tc := range tests {
+		if got := CypherLiteral(tc.val); got != tc.want {
+			t.Errorf("%v: wanted %s but got %s", tc.val, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package geno
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Aggregate string
+
+const (
+	AGGREGATE_LIST  Aggregate = "list"  // every distinct value, in order and joined into one cell
+	AGGREGATE_FIRST Aggregate = "first" // the first distinct value in order
+	AGGREGATE_COUNT Aggregate = "count" // the number of distinct values
+)
+
+// TableColumn is a column of a flat table with one row per node of an anchor label: a property of the anchor itself,
+// or of the nodes reached from it by one relationship of a type, e.g. the Phone of every HAS_PHONE value node.
+// Values reached through relationships are aggregated, as a list by default.
+type TableColumn struct {
+	Name         string    `json:"Name" yaml:"Name"`                                     // header of the column, defaults to the property
+	Relationship string    `json:"Relationship,omitempty" yaml:"Relationship,omitempty"` // type followed from the anchor, empty for its own properties
+	Direction    Direction `json:"Direction,omitempty" yaml:"Direction,omitempty"`       // OUTGOING or INCOMING, defaults to either
+	Label        string    `json:"Label,omitempty" yaml:"Label,omitempty"`               // label of the nodes reached, defaults to any
+	Property     string    `json:"Property" yaml:"Property"`
+	Aggregate    Aggregate `json:"Aggregate,omitempty" yaml:"Aggregate,omitempty"`
+}
+
+func (c TableColumn) aggregate() Aggregate {
+	if c.Aggregate == "" {
+		return AGGREGATE_LIST
+	}
+	return Aggregate(strings.ToLower(string(c.Aggregate)))
+}
+
+// Validate checks that the column names a property, and that its direction and aggregate are understood
+func (c TableColumn) Validate() error {
+	if c.Property == "" {
+		return fmt.Errorf("column %s does not name a property", c.Name)
+	}
+	switch Direction(strings.ToUpper(string(c.Direction))) {
+	case "", OUTGOING, INCOMING:
+	default:
+		return fmt.Errorf("column %s: direction %s is not OUTGOING or INCOMING", c.Name, c.Direction)
+	}
+	switch c.aggregate() {
+	case AGGREGATE_LIST, AGGREGATE_FIRST, AGGREGATE_COUNT:
+	default:
+		return fmt.Errorf("column %s: aggregate %s is not list, first or count", c.Name, c.Aggregate)
+	}
+	return nil
+}
+
+// Aggregates reports whether the column reads the values of related nodes, which are returned as a list
+func (c TableColumn) Aggregates() bool {
+	return c.Relationship != ""
+}
+
+// Cell aggregates the value read for the column by TableQuery: the distinct values of the list, in order, the first
+// of them (nil when there are none), or their number. Properties of the anchor are returned as they are.
+func (c TableColumn) Cell(value any) any {
+	values, isList := value.([]any)
+	if !c.Aggregates() || !isList {
+		return value
+	}
+	var (
+		seen     map[string]bool = make(map[string]bool, len(values))
+		distinct []any           = make([]any, 0, len(values))
+	)
+	for _, v := range values {
+		key := fmt.Sprintf("%T %v", v, v)
+		if !seen[key] {
+			seen[key] = true
+			distinct = append(distinct, v)
+		}
+	}
+	sort.SliceStable(distinct, func(i, j int) bool { return lessValue(distinct[i], distinct[j]) })
+
+	switch c.aggregate() {
+	case AGGREGATE_FIRST:
+		if len(distinct) == 0 {
+			return nil
+		}
+		return distinct[0]
+	case AGGREGATE_COUNT:
+		return int64(len(distinct))
+	default:
+		return distinct
+	}
+}
+
+// lessValue orders numbers by value and everything else by its text
+func lessValue(a, b any) bool {
+	x, aIsNumber := number(a)
+	y, bIsNumber := number(b)
+	if aIsNumber && bIsNumber {
+		return x < y
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// TableQuery builds the query reading one row per node of the anchor label, in order of its keys: the keys as k0,
+// k1 and so on, then every column as c0, c1 and so on. Columns reading related nodes return a list of their values.
+func TableQuery(anchor string, keys []string, columns []TableColumn) string {
+	var (
+		returned []string = make([]string, 0, len(keys)+len(columns))
+		order    []string = make([]string, 0, len(keys))
+	)
+	for i, key := range keys {
+		returned = append(returned, fmt.Sprintf("n.%s AS k%d", escapeName(key), i))
+		order = append(order, fmt.Sprintf("k%d", i))
+	}
+	for i, c := range columns {
+		if !c.Aggregates() {
+			returned = append(returned, fmt.Sprintf("n.%s AS c%d", escapeName(c.Property), i))
+			continue
+		}
+		var (
+			left, right string = "-", "-"
+			label       string
+			property    string = "m." + escapeName(c.Property)
+		)
+		switch Direction(strings.ToUpper(string(c.Direction))) {
+		case OUTGOING:
+			right = "->"
+		case INCOMING:
+			left = "<-"
+		}
+		if c.Label != "" {
+			label = ":" + escapeName(c.Label)
+		}
+		returned = append(returned, fmt.Sprintf("[(n)%s[:%s]%s(m%s) WHERE %s IS NOT NULL | %s] AS c%d",
+			left, escapeName(c.Relationship), right, label, property, property, i))
+	}
+
+	var q string = "MATCH (n:" + escapeName(anchor) + ")\nRETURN " + strings.Join(returned, ",\n  ")
+	if len(order) > 0 {
+		q += "\nORDER BY " + strings.Join(order, ", ")
+	}
+	return q
+}
@@ -0,0 +1,57 @@
+package geno
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableQuery(t *testing.T) {
+	columns := []TableColumn{
+		{Name: "Name", Property: "NAME1"},
+		{Name: "Phone", Relationship: "HAS_PHONE", Direction: OUTGOING, Label: "Phone", Property: "Phone"},
+		{Name: "Customers", Relationship: "PAYS", Direction: INCOMING, Property: "KUNNR", Aggregate: AGGREGATE_COUNT},
+		{Name: "Bank", Relationship: "HAS_BANK", Property: "BANKL"},
+	}
+	want := "MATCH (n:`Customer`)\nRETURN n.`KUNNR` AS k0,\n" +
+		"  n.`NAME1` AS c0,\n" +
+		"  [(n)-[:`HAS_PHONE`]->(m:`Phone`) WHERE m.`Phone` IS NOT NULL | m.`Phone`] AS c1,\n" +
+		"  [(n)<-[:`PAYS`]-(m) WHERE m.`KUNNR` IS NOT NULL | m.`KUNNR`] AS c2,\n" +
+		"  [(n)-[:`HAS_BANK`]-(m) WHERE m.`BANKL` IS NOT NULL | m.`BANKL`] AS c3\n" +
+		"ORDER BY k0"
+	if got := TableQuery("Customer", []string{"KUNNR"}, columns); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+}
+
+func TestTableColumn_Cell(t *testing.T) {
+	values := []any{int64(10), int64(9), int64(10), 9.5}
+	list := TableColumn{Relationship: "HAS_PHONE", Property: "Phone"}
+	if got, want := list.Cell(values), []any{int64(9), 9.5, int64(10)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted the distinct values in order, %v, but got %v", want, got)
+	}
+	if got, want := list.Cell([]any{"030 2", "030 1", "030 2"}), []any{"030 1", "030 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted the distinct values in order, %v, but got %v", want, got)
+	}
+	first := TableColumn{Relationship: "HAS_PHONE", Property: "Phone", Aggregate: AGGREGATE_FIRST}
+	if got := first.Cell(values); got != int64(9) {
+		t.Errorf("wanted the first value, but got %v", got)
+	}
+	if got := first.Cell([]any{}); got != nil {
+		t.Errorf("wanted no value without related nodes, but got %v", got)
+	}
+	count := TableColumn{Relationship: "HAS_PHONE", Property: "Phone", Aggregate: "COUNT"}
+	if got := count.Cell(values); got != int64(3) {
+		t.Errorf("wanted the number of distinct values, but got %v", got)
+	}
+	own := TableColumn{Property: "Tags"}
+	if got := own.Cell(values); !reflect.DeepEqual(got, values) {
+		t.Errorf("wanted a property of the anchor returned as it is, but got %v", got)
+	}
+
+	if err := (TableColumn{Name: "Phone", Relationship: "HAS_PHONE", Property: "Phone", Direction: "sideways"}).Validate(); err == nil {
+		t.Error("wanted an unknown direction to be rejected")
+	}
+	if err := (TableColumn{Name: "Phone", Relationship: "HAS_PHONE", Property: "Phone", Aggregate: "sum"}).Validate(); err == nil {
+		t.Error("wanted an unknown aggregate to be rejected")
+	}
+}
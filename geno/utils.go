@@ -3,6 +3,7 @@ package geno
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 func interfaceToFloat(v any) float64 {
@@ -37,6 +38,11 @@ func interfaceToFloat(v any) float64 {
 }
 
 func templatizeProps(props map[string]any, assignor, paramPrefix string) []string {
+	return templatizeRefs(props, assignor, func(key string) string { return "$" + paramPrefix + key })
+}
+
+// templatizeRefs assigns each property, in key order, to the expression returned by ref
+func templatizeRefs(props map[string]any, assignor string, ref func(key string) string) []string {
 	var (
 		keys   []string = make([]string, 0, len(props))
 		params []string = make([]string, len(props))
@@ -48,7 +54,18 @@ func templatizeProps(props map[string]any, assignor, paramPrefix string) []strin
 	sort.Strings(keys)
 
 	for i, key := range keys {
-		params[i] = fmt.Sprintf("%s%s$%s", key, assignor, paramPrefix+key)
+		params[i] = fmt.Sprintf("%s%s%s", key, assignor, ref(key))
 	}
 	return params
 }
+
+// rowRef returns a function referencing properties of a map held by the given variable, e.g. row.`key`
+func rowRef(row string) func(key string) string {
+	return func(key string) string { return row + "." + escapeName(key) }
+}
+
+// escapeName wraps a label, relationship type or property key in backticks so
+// that it may be used verbatim in a generated cypher query
+func escapeName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
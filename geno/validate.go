@@ -0,0 +1,187 @@
+package geno
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+// Violation describes a node or relationship which does not satisfy a constraint
+type Violation struct {
+	Constraint ConstraintType
+	Label      string
+	Property   string
+	Message    string
+}
+
+func (v Violation) Error() string {
+	if v.Property == "" {
+		return fmt.Sprintf("%s %s: %s", v.Constraint, v.Label, v.Message)
+	}
+	return fmt.Sprintf("%s %s.%s: %s", v.Constraint, v.Label, v.Property, v.Message)
+}
+
+// Validate returns an error for the first constraint geno checks itself which cannot be checked as configured
+func (c *Constraints) Validate() error {
+	for _, r := range c.PropertyRules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, cc := range c.Cardinality {
+		if err := cc.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateNode checks the node against every constraint which can be verified without a database
+func (c *Constraints) ValidateNode(n *Node) []Violation {
+	var violations []Violation
+	for _, label := range n.Labels {
+		violations = append(violations, checkPropertyTypes(NODE_PROPERTY_TYPE_CONSTRAINT, label, n.Properties, c.NodePropertyTypes)...)
+		violations = append(violations, checkPropertyRules(IS_NODE, label, n.Properties, c.PropertyRules)...)
+	}
+	return violations
+}
+
+// ValidateRelationship checks the relationship against every constraint which can be verified without a database
+func (c *Constraints) ValidateRelationship(r *Relationship) []Violation {
+	var violations []Violation
+	violations = append(violations, checkPropertyTypes(REL_PROPERTY_TYPE_CONSTRAINT, r.Label, r.Properties, c.RelationshipPropertyTypes)...)
+	violations = append(violations, c.checkEndpoints(r)...)
+	violations = append(violations, checkPropertyRules(IS_RELATIONSHIP, r.Label, r.Properties, c.PropertyRules)...)
+	return violations
+}
+
+func checkPropertyTypes(constraintType ConstraintType, label string, props map[string]any, constraints []Constraint) []Violation {
+	var violations []Violation
+	for _, c := range constraints {
+		if c.Label != label {
+			continue
+		}
+		for _, p := range c.Properties {
+			val, found := props[p]
+			if !found || val == nil {
+				continue
+			}
+			if !MatchesPropertyType(val, c.PropertyType) {
+				violations = append(violations, Violation{
+					Constraint: constraintType,
+					Label:      label,
+					Property:   p,
+					Message:    fmt.Sprintf("value %v of type %T is not %s", val, val, c.PropertyType),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// MatchesPropertyType reports whether a value, as it would be sent by the driver, satisfies a cypher property type
+// such as STRING, INTEGER | FLOAT or LIST<STRING NOT NULL>. Null values satisfy every type.
+func MatchesPropertyType(val any, propertyType string) bool {
+	if val == nil {
+		return true
+	}
+	for _, alternative := range splitTypeUnion(propertyType) {
+		if matchesSingleType(val, alternative) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTypeUnion splits a type on top level pipes, leaving those nested within LIST<...> intact
+func splitTypeUnion(propertyType string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range propertyType {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '|':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(propertyType[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(propertyType[start:]))
+}
+
+func matchesSingleType(val any, propertyType string) bool {
+	t := strings.ToUpper(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(propertyType), "NOT NULL")))
+	if strings.HasPrefix(t, "LIST<") && strings.HasSuffix(t, ">") {
+		inner := t[len("LIST<") : len(t)-1]
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return false
+		}
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i).Interface()
+			if elem == nil || !MatchesPropertyType(elem, inner) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch t {
+	case "ANY", "PROPERTY VALUE":
+		return true
+	case "BOOLEAN", "BOOL":
+		_, ok := val.(bool)
+		return ok
+	case "STRING", "VARCHAR":
+		_, ok := val.(string)
+		return ok
+	case "INTEGER", "INT", "SIGNED INTEGER":
+		switch val.(type) {
+		case int, int8, int16, int32, int64, uint8, uint16, uint32:
+			return true
+		}
+		return false
+	case "FLOAT":
+		switch val.(type) {
+		case float32, float64:
+			return true
+		}
+		return false
+	case "DATE":
+		_, ok := val.(dbtype.Date)
+		return ok
+	case "LOCAL TIME", "TIME WITHOUT TIME ZONE":
+		_, ok := val.(dbtype.LocalTime)
+		return ok
+	case "ZONED TIME", "TIME WITH TIME ZONE":
+		_, ok := val.(dbtype.Time)
+		return ok
+	case "LOCAL DATETIME", "TIMESTAMP WITHOUT TIME ZONE":
+		_, ok := val.(dbtype.LocalDateTime)
+		return ok
+	case "ZONED DATETIME", "TIMESTAMP WITH TIME ZONE":
+		_, ok := val.(time.Time)
+		return ok
+	case "DURATION":
+		_, ok := val.(dbtype.Duration)
+		return ok
+	case "POINT":
+		switch val.(type) {
+		case dbtype.Point2D, dbtype.Point3D:
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+}
@@ -0,0 +1,49 @@
+package geno
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+func TestMatchesPropertyType(t *testing.T) {
+	type test struct {
+		value        any
+		propertyType string
+		want         bool
+	}
+
+	tests := []test{
+		{value: "0249697900", propertyType: "STRING", want: true},
+		{value: float64(1), propertyType: "INTEGER", want: false},
+		{value: int64(1), propertyType: "INTEGER", want: true},
+		{value: float64(1), propertyType: "INTEGER | FLOAT", want: true},
+		{value: nil, propertyType: "BOOLEAN", want: true},
+		{value: dbtype.Date(time.Now()), propertyType: "DATE", want: true},
+		{value: time.Now(), propertyType: "ZONED DATETIME", want: true},
+		{value: []any{"a", "b"}, propertyType: "LIST<STRING NOT NULL>", want: true},
+		{value: []any{"a", int64(1)}, propertyType: "LIST<STRING NOT NULL>", want: false},
+		{value: []int64{1, 2}, propertyType: "LIST<INTEGER | FLOAT NOT NULL> | STRING", want: true},
+	}
+
+	for _, tc := range tests {
+		got := MatchesPropertyType(tc.value, tc.propertyType)
+		if tc.want != got {
+			t.Errorf("wanted %v for %v (%T) as %s, but got %v", tc.want, tc.value, tc.value, tc.propertyType, got)
+		}
+	}
+}
+
+func TestValidateNode(t *testing.T) {
+	c := Constraints{NodePropertyTypes: []Constraint{{Label: "Customer", Properties: []string{"KUNNR"}, PropertyType: "STRING"}}}
+
+	valid := NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "0249697900"})
+	if got := c.ValidateNode(&valid); len(got) != 0 {
+		t.Errorf("wanted no violations but got %v", got)
+	}
+	invalid := NewNode(2, []string{"Customer"}, map[string]any{"KUNNR": float64(249697900)})
+	if got := c.ValidateNode(&invalid); len(got) != 1 {
+		t.Errorf("wanted one violation but got %v", got)
+	}
+}
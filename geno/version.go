@@ -0,0 +1,114 @@
+package geno
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion is the major and minor version of the neo4j kernel a driver is connected to
+type ServerVersion struct {
+	Major int
+	Minor int
+}
+
+func (v ServerVersion) String() string { return fmt.Sprintf("%d.%d", v.Major, v.Minor) }
+
+// ParseServerVersion reads versions as reported by dbms.components, e.g. 4.4.11 or 5.13.0-aura
+func ParseServerVersion(raw string) (ServerVersion, error) {
+	var v ServerVersion
+	parts := strings.SplitN(strings.TrimSpace(raw), ".", 3)
+	if len(parts) < 2 {
+		return v, fmt.Errorf("server version %q is not formatted as <MAJOR>.<MINOR>", raw)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return v, fmt.Errorf("server version %q does not have a numeric major version", raw)
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return v, fmt.Errorf("server version %q does not have a numeric minor version", raw)
+	}
+	return ServerVersion{Major: major, Minor: minor}, nil
+}
+
+// AtLeast reports whether the version is the same as or newer than major.minor
+func (v ServerVersion) AtLeast(major, minor int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+// SupportsConstraintType reports whether a server of this version can hold constraints of the given type
+func (v ServerVersion) SupportsConstraintType(t ConstraintType) bool {
+	switch t {
+	case NODE_UNIQUE_CONSTRAINT, NODE_KEY_CONSTRAINT, NODE_PROPERTY_EXISTS_CONSTRAINT, REL_PROPERTY_EXISTS_CONSTRAINT:
+		return true
+	case REL_UNIQUE_CONSTRAINT, REL_KEY_CONSTRAINT:
+		return v.AtLeast(5, 7)
+	case NODE_PROPERTY_TYPE_CONSTRAINT, REL_PROPERTY_TYPE_CONSTRAINT:
+		return v.AtLeast(5, 9)
+	default:
+		return false
+	}
+}
+
+// SupportedStatements separates the statements built by ToCypherCreate which a server of this version can run from
+// those creating constraints of a type it cannot hold. Statements of any other form are taken to be supported.
+func (v ServerVersion) SupportedStatements(statements []string) (supported, unsupported []string) {
+	for _, statement := range statements {
+		if t, found := createdConstraintType(statement); found && !v.SupportsConstraintType(t) {
+			unsupported = append(unsupported, statement)
+			continue
+		}
+		supported = append(supported, statement)
+	}
+	return supported, unsupported
+}
+
+// normalizeConstraintType maps the type column of SHOW CONSTRAINTS onto the constraint types geno understands
+func (v ServerVersion) normalizeConstraintType(raw string) ConstraintType {
+	if v.Major >= 5 {
+		if alias, found := constraintTypeAliases[raw]; found {
+			return alias
+		}
+	}
+	return ConstraintType(raw)
+}
+
+// versionFromConstraintColumns guesses the server version from the columns of SHOW CONSTRAINTS
+// when it could not be asked for directly. Neo4j 4.4 reports ownedIndexId while 5 reports ownedIndex,
+// and the propertyType column first appears in 5.9.
+func versionFromConstraintColumns(columns []string) ServerVersion {
+	var version ServerVersion = ServerVersion{Major: 4, Minor: 4}
+	for _, column := range columns {
+		switch column {
+		case "ownedIndex":
+			if !version.AtLeast(5, 0) {
+				version = ServerVersion{Major: 5, Minor: 0}
+			}
+		case "propertyType":
+			version = ServerVersion{Major: 5, Minor: 9}
+		}
+	}
+	return version
+}
+
+// GetServerVersion asks the server for the version of its kernel
+func (d *Driver) GetServerVersion() (ServerVersion, error) {
+	records, err := d.ReadRecords("system", "CALL dbms.components() YIELD name, versions WHERE name = 'Neo4j Kernel' RETURN versions[0] AS version", nil)
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	if len(records) == 0 {
+		return ServerVersion{}, errors.New("the server did not report a kernel version")
+	}
+	raw, _ := records[0].Get("version")
+	s, ok := raw.(string)
+	if !ok {
+		return ServerVersion{}, errors.New("the server reported an improperly formatted kernel version")
+	}
+	return ParseServerVersion(s)
+}
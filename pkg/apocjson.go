@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+// apocId is an element id as written by apoc.export.json, which is a string in recent versions and a number in older ones
+type apocId int64
+
+func (id *apocId) UnmarshalJSON(raw []byte) error {
+	raw = bytes.Trim(raw, `"`)
+	n, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return fmt.Errorf("id %s is not numeric", raw)
+	}
+	*id = apocId(n)
+	return nil
+}
+
+func (id apocId) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(id), 10))
+}
+
+type apocEndpoint struct {
+	Id     apocId   `json:"id"`
+	Labels []string `json:"labels"`
+}
+
+// apocRecord is a single line of apoc.export.json output, holding either a node or a relationship
+type apocRecord struct {
+	Type       string         `json:"type"`
+	Id         apocId         `json:"id"`
+	Labels     []string       `json:"labels,omitempty"`
+	Label      string         `json:"label,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Start      *apocEndpoint  `json:"start,omitempty"`
+	End        *apocEndpoint  `json:"end,omitempty"`
+}
+
+// GetGraphFromApocJson reads the line delimited output of apoc.export.json.all and apoc.export.json.query,
+// one node or relationship per line. Lines are decoded one at a time, so only the resulting graph is held in memory.
+// Relationships may appear before the nodes they connect, but both nodes must appear somewhere in the input.
+func GetGraphFromApocJson(r io.Reader) (g Graph, err error) {
+	var (
+		decoder   *json.Decoder = json.NewDecoder(r)
+		nodeIndex map[int64]int = make(map[int64]int)
+		rels      []apocRecord
+	)
+
+	for line := 1; ; line++ {
+		var rec apocRecord
+		if err := decoder.Decode(&rec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return g, fmt.Errorf("record %d: %w", line, err)
+		}
+
+		switch rec.Type {
+		case "node":
+			if _, found := nodeIndex[int64(rec.Id)]; found {
+				return g, fmt.Errorf("record %d: node %d appears more than once", line, rec.Id)
+			}
+			nodeIndex[int64(rec.Id)] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, geno.NewNode(int64(rec.Id), rec.Labels, rec.Properties))
+		case "relationship":
+			if rec.Start == nil || rec.End == nil {
+				return g, fmt.Errorf("record %d: relationship %d is missing its start or end", line, rec.Id)
+			}
+			rels = append(rels, rec)
+		default:
+			return g, fmt.Errorf("record %d: type %q is neither node nor relationship", line, rec.Type)
+		}
+	}
+
+	g.Relationships = make([]geno.Relationship, len(rels))
+	for i, rec := range rels {
+		start, found := nodeIndex[int64(rec.Start.Id)]
+		if !found {
+			return g, fmt.Errorf("relationship %d starts at node %d which could not be found", rec.Id, rec.Start.Id)
+		}
+		end, found := nodeIndex[int64(rec.End.Id)]
+		if !found {
+			return g, fmt.Errorf("relationship %d ends at node %d which could not be found", rec.Id, rec.End.Id)
+		}
+		g.Relationships[i] = geno.NewRelationship(int64(rec.Id), g.Nodes[start], g.Nodes[end], rec.Label, rec.Properties)
+	}
+	return g, nil
+}
+
+// WriteApocJson writes a graph in the line delimited format of apoc.export.json.all,
+// which apoc.import.json can load: every node, followed by every relationship
+func WriteApocJson(w io.Writer, g Graph) error {
+	encoder := json.NewEncoder(w)
+	for _, n := range g.Nodes {
+		if err := encoder.Encode(apocRecord{Type: "node", Id: apocId(n.Id), Labels: n.Labels, Properties: exportProperties(n.Properties)}); err != nil {
+			return err
+		}
+	}
+	for _, r := range g.Relationships {
+		if err := encoder.Encode(apocRecord{
+			Type:       "relationship",
+			Id:         apocId(r.Id),
+			Label:      r.Label,
+			Properties: exportProperties(r.Properties),
+			Start:      &apocEndpoint{Id: apocId(r.Start.Id), Labels: r.Start.Labels},
+			End:        &apocEndpoint{Id: apocId(r.End.Id), Labels: r.End.Labels},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
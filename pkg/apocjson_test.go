@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestGetGraphFromApocJson(t *testing.T) {
+	const lines = `{"type":"relationship","id":"0","label":"KNOWS","properties":{"since":1993},"start":{"id":"0","labels":["User"]},"end":{"id":"1","labels":["User"]}}
+{"type":"node","id":"0","labels":["User"],"properties":{"name":"Adam","male":true,"kids":["Sam","Anna"]}}
+{"type":"node","id":1,"labels":["User"],"properties":{"name":"Jim"}}
+`
+	var (
+		nodeA geno.Node         = geno.NewNode(0, []string{"User"}, map[string]any{"name": "Adam", "male": true, "kids": []any{"Sam", "Anna"}})
+		nodeB geno.Node         = geno.NewNode(1, []string{"User"}, map[string]any{"name": "Jim"})
+		relA  geno.Relationship = geno.NewRelationship(0, nodeA, nodeB, "KNOWS", map[string]any{"since": float64(1993)})
+	)
+
+	got, err := GetGraphFromApocJson(strings.NewReader(lines))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]geno.Node{nodeA, nodeB}, got.Nodes) {
+		t.Errorf("wanted nodes of\n%v\nbut got\n%v\n", []geno.Node{nodeA, nodeB}, got.Nodes)
+	}
+	if !reflect.DeepEqual([]geno.Relationship{relA}, got.Relationships) {
+		t.Errorf("wanted rels of\n%v\nbut got\n%v\n", []geno.Relationship{relA}, got.Relationships)
+	}
+
+	if _, err := GetGraphFromApocJson(strings.NewReader(strings.Replace(lines, `"end":{"id":"1"`, `"end":{"id":"7"`, 1))); err == nil {
+		t.Error("a relationship to a node which is not in the file should be an error")
+	}
+}
+
+func TestWriteApocJson(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(3, []string{"Customer"}, map[string]any{"name": "Acme"})
+		nodeB geno.Node         = geno.NewNode(4, []string{"Vendor"}, map[string]any{"name": "Widgets"})
+		relA  geno.Relationship = geno.NewRelationship(9, nodeA, nodeB, "BUYS_FROM", map[string]any{"count": float64(3)})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB}, Relationships: []geno.Relationship{relA}}
+		buf   bytes.Buffer
+	)
+	if err := WriteApocJson(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type":"relationship","id":"9","label":"BUYS_FROM","properties":{"count":3},"start":{"id":"3","labels":["Customer"]},"end":{"id":"4","labels":["Vendor"]}}`
+	if !strings.Contains(buf.String(), want+"\n") {
+		t.Errorf("wanted a line of\n%s\nbut got\n%s", want, buf.String())
+	}
+
+	got, err := GetGraphFromApocJson(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, got) {
+		t.Errorf("wanted a round trip to give\n%v\nbut got\n%v\n", g, got)
+	}
+}
@@ -0,0 +1,283 @@
+package pkg
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+// BackupManifest is the name of the file describing a backup within its directory
+const BackupManifest string = "backup.json"
+
+// Backup describes a database written by WriteBackup: the statements recreating its constraints,
+// and one file of apoc json lines for the nodes of each label and the relationships of each type
+type Backup struct {
+	Database      string       `json:"database"`
+	Created       time.Time    `json:"created"`
+	Nodes         int          `json:"nodes"`
+	Relationships int          `json:"relationships"`
+	Constraints   []string     `json:"constraints"`
+	Files         []BackupFile `json:"files"`
+
+	// nodes without labels could not be found again on restore, so neither they nor their relationships are backed up
+	UnlabelledNodes         int64 `json:"unlabelledNodes,omitempty"`
+	UnlabelledRelationships int64 `json:"unlabelledRelationships,omitempty"`
+}
+
+// BackupFile is a single file of a backup
+type BackupFile struct {
+	Kind   string `json:"kind"` // node or relationship
+	Name   string `json:"name"` // label or relationship type
+	Path   string `json:"path"` // relative to the directory of the backup
+	Count  int    `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+// backupPage reads the page of elements following the id given, returning them as a graph along with the id of
+// the last element read
+type backupPage func(after int64) (g Graph, last int64, err error)
+
+// writeBackupFile writes every page of elements to the file of the backup, one page at a time, until a page
+// holds fewer than pageSize elements
+func writeBackupFile(dir string, file BackupFile, pageSize int, page backupPage) (BackupFile, error) {
+	f, err := os.Create(filepath.Join(dir, file.Path))
+	if err != nil {
+		return file, err
+	}
+	defer f.Close()
+
+	var (
+		hash        = sha256.New()
+		out         = bufio.NewWriter(io.MultiWriter(f, hash))
+		after int64 = -1
+	)
+	for {
+		g, last, err := page(after)
+		if err != nil {
+			return file, fmt.Errorf("%s: %w", file.Path, err)
+		}
+		if err := WriteApocJson(out, g); err != nil {
+			return file, fmt.Errorf("%s: %w", file.Path, err)
+		}
+		read := len(g.Nodes) + len(g.Relationships)
+		file.Count += read
+		if read < pageSize {
+			break
+		}
+		after = last
+	}
+	if err := out.Flush(); err != nil {
+		return file, err
+	}
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return file, f.Close()
+}
+
+// WriteBackup writes every node and relationship of a database to dir, reading pageSize elements at a time in order
+// of their internal ids, so that memory use does not grow with the database. Each node is written to the file of
+// its first label in order. Nodes without labels, and their relationships, could not be found again by a restore
+// and are only counted. The statements creating the database's constraints, the number of elements of every file and
+// their checksums are written to the backup's manifest, BackupManifest, once every file has been written.
+// done is called with each file once it is complete.
+func WriteBackup(driver *geno.Driver, database, dir string, pageSize int, done func(BackupFile)) (b Backup, err error) {
+	if pageSize < 1 {
+		return b, errors.New("the page size must be at least 1")
+	}
+	for _, sub := range []string{"nodes", "relationships"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return b, err
+		}
+	}
+	b = Backup{Database: database, Created: time.Now().UTC()}
+
+	c, err := driver.GetConstraints(database)
+	if err != nil {
+		return b, fmt.Errorf("constraints could not be read: %w", err)
+	}
+	if b.Constraints, err = c.ToCypherCreate(); err != nil {
+		return b, err
+	}
+
+	records, err := driver.ReadRecords(database, "MATCH (n) WHERE size(labels(n)) = 0\n"+
+		"OPTIONAL MATCH (n)-[r]-()\nRETURN count(DISTINCT n) AS nodes, count(DISTINCT r) AS relationships", nil)
+	if err != nil {
+		return b, err
+	}
+	if len(records) == 1 {
+		nodes, _ := records[0].Get("nodes")
+		rels, _ := records[0].Get("relationships")
+		b.UnlabelledNodes, _ = nodes.(int64)
+		b.UnlabelledRelationships, _ = rels.(int64)
+	}
+
+	labels, err := driver.GetLabels(database)
+	if err != nil {
+		return b, err
+	}
+	for _, label := range labels {
+		var file BackupFile = BackupFile{Kind: "node", Name: label, Path: filepath.Join("nodes", url.PathEscape(label)+".jsonl")}
+		file, err = writeBackupFile(dir, file, pageSize, func(after int64) (Graph, int64, error) {
+			records, err := driver.PageNodesByLabel(database, label, after, pageSize)
+			if err != nil {
+				return Graph{}, after, err
+			}
+			g := GraphFromRecords(records)
+			if len(g.Nodes) == 0 {
+				return g, after, nil
+			}
+			return g, g.Nodes[len(g.Nodes)-1].Id, nil
+		})
+		if err != nil {
+			return b, err
+		}
+		b.Nodes += file.Count
+		b.Files = append(b.Files, file)
+		if done != nil {
+			done(file)
+		}
+	}
+
+	types, err := driver.GetRelationshipTypes(database)
+	if err != nil {
+		return b, err
+	}
+	for _, relType := range types {
+		var file BackupFile = BackupFile{Kind: "relationship", Name: relType, Path: filepath.Join("relationships", url.PathEscape(relType)+".jsonl")}
+		file, err = writeBackupFile(dir, file, pageSize, func(after int64) (Graph, int64, error) {
+			records, err := driver.PageRelationshipsByType(database, relType, after, pageSize)
+			if err != nil {
+				return Graph{}, after, err
+			}
+			g := GraphFromRecords(records)
+			g.Nodes = nil // written to the files of their labels
+			if len(g.Relationships) == 0 {
+				return g, after, nil
+			}
+			return g, g.Relationships[len(g.Relationships)-1].Id, nil
+		})
+		if err != nil {
+			return b, err
+		}
+		b.Relationships += file.Count
+		b.Files = append(b.Files, file)
+		if done != nil {
+			done(file)
+		}
+	}
+
+	raw, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return b, err
+	}
+	return b, os.WriteFile(filepath.Join(dir, BackupManifest), raw, 0o644)
+}
+
+// ReadBackup reads the manifest of a backup and checks that every file it lists is present and unchanged
+func ReadBackup(dir string) (b Backup, err error) {
+	raw, err := os.ReadFile(filepath.Join(dir, BackupManifest))
+	if err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return b, fmt.Errorf("%s: %w", BackupManifest, err)
+	}
+	for _, file := range b.Files {
+		f, err := os.Open(filepath.Join(dir, file.Path))
+		if err != nil {
+			return b, err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return b, err
+		}
+		if sum := hex.EncodeToString(hash.Sum(nil)); sum != file.SHA256 {
+			return b, fmt.Errorf("%s does not match its checksum, it may be damaged or incomplete", file.Path)
+		}
+	}
+	return b, nil
+}
+
+// ReadBackupFile reads a file of a backup batchSize elements at a time, passing each batch of nodes or relationships
+// on as it is read, so that memory use does not grow with the backup. Every element holds the id it was backed up
+// with under geno.RestoreIdProperty, and relationships end at nodes holding only their labels and that id, which is
+// how geno.Query.RestoreRelationships finds the nodes restored from the files of their labels.
+func ReadBackupFile(dir string, file BackupFile, batchSize int, nodes func([]geno.Node) error, rels func([]geno.Relationship) error) error {
+	if batchSize < 1 {
+		return errors.New("the batch size must be at least 1")
+	}
+	f, err := os.Open(filepath.Join(dir, file.Path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var (
+		decoder      *json.Decoder = json.NewDecoder(bufio.NewReader(f))
+		nodeBatch    []geno.Node
+		relBatch     []geno.Relationship
+		flushNodes   = func() error { defer func() { nodeBatch = nil }(); return nodes(nodeBatch) }
+		flushRels    = func() error { defer func() { relBatch = nil }(); return rels(relBatch) }
+		withBackupId = func(props map[string]any, id apocId) map[string]any {
+			restored := make(map[string]any, len(props)+1)
+			for key, val := range props {
+				restored[key] = val
+			}
+			restored[geno.RestoreIdProperty] = int64(id)
+			return restored
+		}
+	)
+	for line := 1; ; line++ {
+		var rec apocRecord
+		if err := decoder.Decode(&rec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("%s: record %d: %w", file.Path, line, err)
+		}
+
+		switch rec.Type {
+		case "node":
+			nodeBatch = append(nodeBatch, geno.NewNode(int64(rec.Id), rec.Labels, withBackupId(rec.Properties, rec.Id)))
+		case "relationship":
+			if rec.Start == nil || rec.End == nil {
+				return fmt.Errorf("%s: record %d: relationship %d is missing its start or end", file.Path, line, rec.Id)
+			}
+			relBatch = append(relBatch, geno.NewRelationship(int64(rec.Id),
+				geno.NewNode(int64(rec.Start.Id), rec.Start.Labels, withBackupId(nil, rec.Start.Id)),
+				geno.NewNode(int64(rec.End.Id), rec.End.Labels, withBackupId(nil, rec.End.Id)),
+				rec.Label, withBackupId(rec.Properties, rec.Id)))
+		default:
+			return fmt.Errorf("%s: record %d: type %q is neither node nor relationship", file.Path, line, rec.Type)
+		}
+		if len(nodeBatch) == batchSize {
+			if err := flushNodes(); err != nil {
+				return err
+			}
+		}
+		if len(relBatch) == batchSize {
+			if err := flushRels(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(nodeBatch) > 0 {
+		if err := flushNodes(); err != nil {
+			return err
+		}
+	}
+	if len(relBatch) > 0 {
+		return flushRels()
+	}
+	return nil
+}
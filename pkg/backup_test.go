@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func writeTestBackup(t *testing.T, files map[string]string, b Backup) string {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "nodes"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "relationships"), 0o755)
+	for i, file := range b.Files {
+		raw := files[file.Path]
+		if err := os.WriteFile(filepath.Join(dir, file.Path), []byte(raw), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256([]byte(raw))
+		b.Files[i].SHA256 = hex.EncodeToString(sum[:])
+	}
+	raw, _ := json.Marshal(b)
+	if err := os.WriteFile(filepath.Join(dir, BackupManifest), raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestReadBackup(t *testing.T) {
+	files := map[string]string{
+		"nodes/Customer.jsonl":     `{"type":"node","id":"1","labels":["Customer"],"properties":{"KUNNR":"1"}}` + "\n",
+		"nodes/Bank.jsonl":         `{"type":"node","id":"2","labels":["Bank"],"properties":{"BANKL":"10020030"}}` + "\n",
+		"relationships/PAYS.jsonl": `{"type":"relationship","id":"3","label":"PAYS","start":{"id":"1","labels":["Customer"]},"end":{"id":"2","labels":["Bank"]}}` + "\n",
+	}
+	b := Backup{Database: "PRD", Nodes: 2, Relationships: 1, Files: []BackupFile{
+		{Kind: "relationship", Name: "PAYS", Path: "relationships/PAYS.jsonl", Count: 1},
+		{Kind: "node", Name: "Customer", Path: "nodes/Customer.jsonl", Count: 1},
+		{Kind: "node", Name: "Bank", Path: "nodes/Bank.jsonl", Count: 1},
+	}}
+	dir := writeTestBackup(t, files, b)
+
+	read, err := ReadBackup(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		nodes []geno.Node
+		rels  []geno.Relationship
+	)
+	for _, file := range read.Files {
+		err := ReadBackupFile(dir, file, 1,
+			func(batch []geno.Node) error { nodes = append(nodes, batch...); return nil },
+			func(batch []geno.Relationship) error { rels = append(rels, batch...); return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(nodes) != 2 || len(rels) != 1 {
+		t.Fatalf("wanted 2 nodes and a relationship, but got %v and %v", nodes, rels)
+	}
+	if nodes[0].Properties["KUNNR"] != "1" || nodes[0].Properties[geno.RestoreIdProperty] != int64(1) {
+		t.Errorf("wanted nodes read with their backup id, but got %v", nodes[0].Properties)
+	}
+	want := geno.NewNode(2, []string{"Bank"}, map[string]any{geno.RestoreIdProperty: int64(2)})
+	if !reflect.DeepEqual(rels[0].End, want) || rels[0].Properties[geno.RestoreIdProperty] != int64(3) {
+		t.Errorf("wanted relationships ending at the backup ids of their nodes, but got %+v", rels[0])
+	}
+
+	os.WriteFile(filepath.Join(dir, "nodes/Bank.jsonl"), []byte("{}\n"), 0o644)
+	if _, err := ReadBackup(dir); err == nil {
+		t.Error("wanted a changed file to fail its checksum")
+	}
+}
+
+func TestReadBackupFile_batches(t *testing.T) {
+	files := map[string]string{
+		"nodes/Note.jsonl": `{"type":"node","id":"1","labels":["Note"]}` + "\n" +
+			`{"type":"node","id":"2","labels":["Note"]}` + "\n" +
+			`{"type":"node","id":"3","labels":["Note"]}` + "\n",
+	}
+	b := Backup{Files: []BackupFile{{Kind: "node", Name: "Note", Path: "nodes/Note.jsonl", Count: 3}}}
+	dir := writeTestBackup(t, files, b)
+
+	var sizes []int
+	err := ReadBackupFile(dir, b.Files[0], 2, func(batch []geno.Node) error { sizes = append(sizes, len(batch)); return nil }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sizes, []int{2, 1}) {
+		t.Errorf("wanted identical nodes without properties read as distinct nodes in batches of 2, but got batches of %v", sizes)
+	}
+}
@@ -0,0 +1,189 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// browserId reads the identity of a node or relationship as exported by Neo4j Browser, which is a number,
+// a numeric string, or the {"low","high"} pair of the javascript driver's 64 bit integers
+func browserId(raw any) (int64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), true
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		return id, err == nil
+	case map[string]any:
+		low, lowOk := v["low"].(float64)
+		high, highOk := v["high"].(float64)
+		if !lowOk || !highOk {
+			return 0, false
+		}
+		return int64(high)<<32 | int64(uint32(int32(low))), true
+	default:
+		return 0, false
+	}
+}
+
+func browserStrings(raw any) []string {
+	items, _ := raw.([]any)
+	var strs []string = make([]string, 0, len(items))
+	for _, item := range items {
+		strs = append(strs, fmt.Sprint(item))
+	}
+	return strs
+}
+
+func browserProperties(raw any) map[string]any {
+	props, _ := raw.(map[string]any)
+	if props == nil {
+		props = map[string]any{}
+	}
+	return props
+}
+
+// browserNode reads a map as a node if it has an identity and labels
+func browserNode(m map[string]any) (neo4j.Node, bool) {
+	id, ok := browserId(m["identity"])
+	if _, hasLabels := m["labels"]; !ok || !hasLabels {
+		return neo4j.Node{}, false
+	}
+	return neo4j.Node{Id: id, Labels: browserStrings(m["labels"]), Props: browserProperties(m["properties"])}, true
+}
+
+// browserRelationship reads a map as a relationship if it has an identity, type, start and end
+func browserRelationship(m map[string]any) (neo4j.Relationship, bool) {
+	id, ok := browserId(m["identity"])
+	relType, hasType := m["type"].(string)
+	start, hasStart := browserId(m["start"])
+	end, hasEnd := browserId(m["end"])
+	if !ok || !hasType || !hasStart || !hasEnd {
+		return neo4j.Relationship{}, false
+	}
+	return neo4j.Relationship{Id: id, StartId: start, EndId: end, Type: relType, Props: browserProperties(m["properties"])}, true
+}
+
+// browserValue converts the paths, nodes and relationships within an exported value into those returned by the driver,
+// so that they can be collected in the same way as query results
+func browserValue(raw any) any {
+	switch v := raw.(type) {
+	case []any:
+		var items []any = make([]any, len(v))
+		for i, item := range v {
+			items[i] = browserValue(item)
+		}
+		return items
+	case map[string]any:
+		if segments, isPath := v["segments"].([]any); isPath {
+			var path neo4j.Path
+			for _, segment := range segments {
+				s, _ := segment.(map[string]any)
+				if start, ok := browserNode(browserProperties(s["start"])); ok {
+					path.Nodes = append(path.Nodes, start)
+				}
+				if end, ok := browserNode(browserProperties(s["end"])); ok {
+					path.Nodes = append(path.Nodes, end)
+				}
+				if rel, ok := browserRelationship(browserProperties(s["relationship"])); ok {
+					path.Relationships = append(path.Relationships, rel)
+				}
+			}
+			// zero length paths have no segments, only a start and end
+			if start, ok := browserNode(browserProperties(v["start"])); ok {
+				path.Nodes = append(path.Nodes, start)
+			}
+			return path
+		}
+		if rel, ok := browserRelationship(v); ok {
+			return rel
+		}
+		if n, ok := browserNode(v); ok {
+			return n
+		}
+		var values map[string]any = make(map[string]any, len(v))
+		for key, item := range v {
+			values[key] = browserValue(item)
+		}
+		return values
+	default:
+		return raw
+	}
+}
+
+// GetGraphFromBrowserJson reads query results exported as json from Neo4j Browser: an array of records,
+// each holding its columns by name. Every node, relationship and path found in any column, including
+// within lists and maps, contributes its unique nodes and relationships to the graph.
+// The nodes at either end of every relationship must be present somewhere in the export.
+func GetGraphFromBrowserJson(raw []byte) (g Graph, err error) {
+	records, err := browserRecords(raw)
+	if err != nil {
+		return g, err
+	}
+
+	collector := newGraphCollector()
+	for _, columns := range records {
+		for _, value := range columns {
+			collector.walk(browserValue(value))
+		}
+	}
+	if missing := collector.missingEndpoints(); len(missing) > 0 {
+		return g, fmt.Errorf("relationships refer to %d node(s) which were not exported, including node %d; return the nodes along with the relationships", len(missing), missing[0])
+	}
+	return collector.graph(), nil
+}
+
+// browserRecords decodes an array of records, keeping the values of each record in the order of its columns.
+// A single record which is not within an array is accepted as well.
+func browserRecords(raw []byte) ([][]any, error) {
+	var (
+		decoder *json.Decoder = json.NewDecoder(bytes.NewReader(raw))
+		records [][]any
+	)
+	readRecord := func() error {
+		var columns []any
+		for decoder.More() {
+			if _, err := decoder.Token(); err != nil { // column name
+				return err
+			}
+			var value any
+			if err := decoder.Decode(&value); err != nil {
+				return err
+			}
+			columns = append(columns, value)
+		}
+		records = append(records, columns)
+		_, err := decoder.Token() // closing brace
+		return err
+	}
+
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		if err := readRecord(); err != nil {
+			return nil, err
+		}
+		return records, nil
+	case json.Delim('['):
+		for decoder.More() {
+			if tok, err := decoder.Token(); err != nil {
+				return nil, err
+			} else if tok != json.Delim('{') {
+				return nil, fmt.Errorf("expected each record to be an object but found %v", tok)
+			}
+			if err := readRecord(); err != nil {
+				return nil, err
+			}
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("expected an array of records but found %v", tok)
+	}
+}
@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestGetGraphFromBrowserJson(t *testing.T) {
+	type test struct {
+		name       string
+		jsonString string
+		wantNodes  []geno.Node
+		wantRels   []geno.Relationship
+		wantErr    bool
+	}
+
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"TypeA"}, map[string]any{"Prop1": "Value1A"})
+		nodeB geno.Node         = geno.NewNode(2, []string{"TypeB"}, map[string]any{"Prop1": "Value1B"})
+		relA  geno.Relationship = geno.NewRelationship(4, nodeA, nodeB, "RelTypeA", map[string]any{"RelProp1": "RelValue1A"})
+	)
+
+	tests := []test{
+		{
+			name: "columns",
+			jsonString: `[
+				{"n":{"identity":1,"labels":["TypeA"],"properties":{"Prop1":"Value1A"}},"r":{"identity":4,"start":1,"end":2,"type":"RelTypeA","properties":{"RelProp1":"RelValue1A"}},"m":{"identity":2,"labels":["TypeB"],"properties":{"Prop1":"Value1B"}}},
+				{"n":{"identity":1,"labels":["TypeA"],"properties":{"Prop1":"Value1A"}},"r":null,"m":null}
+			]`,
+			wantNodes: []geno.Node{nodeA, nodeB},
+			wantRels:  []geno.Relationship{relA},
+		},
+		{
+			name: "path with driver integers",
+			jsonString: `[{"p":{"start":{"identity":{"low":1,"high":0},"labels":["TypeA"],"properties":{"Prop1":"Value1A"}},"end":{"identity":{"low":2,"high":0},"labels":["TypeB"],"properties":{"Prop1":"Value1B"}},
+				"segments":[{"start":{"identity":{"low":1,"high":0},"labels":["TypeA"],"properties":{"Prop1":"Value1A"}},"relationship":{"identity":{"low":4,"high":0},"start":{"low":1,"high":0},"end":{"low":2,"high":0},"type":"RelTypeA","properties":{"RelProp1":"RelValue1A"}},"end":{"identity":{"low":2,"high":0},"labels":["TypeB"],"properties":{"Prop1":"Value1B"}}}],"length":1}}]`,
+			wantNodes: []geno.Node{nodeA, nodeB},
+			wantRels:  []geno.Relationship{relA},
+		},
+		{
+			name:       "nested in a list",
+			jsonString: `[{"nodes":[{"identity":"1","labels":["TypeA"],"properties":{"Prop1":"Value1A"}}],"count":1}]`,
+			wantNodes:  []geno.Node{nodeA},
+			wantRels:   []geno.Relationship{},
+		},
+		{
+			name:       "missing endpoint",
+			jsonString: `[{"r":{"identity":4,"start":1,"end":2,"type":"RelTypeA","properties":{}}}]`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		got, err := GetGraphFromBrowserJson([]byte(tc.jsonString))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: wanted an error to be %v but got %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if !reflect.DeepEqual(tc.wantNodes, got.Nodes) {
+			t.Errorf("%s: wanted nodes of\n%v\nbut got\n%v\n", tc.name, tc.wantNodes, got.Nodes)
+		}
+		if !reflect.DeepEqual(tc.wantRels, got.Relationships) {
+			t.Errorf("%s: wanted rels of\n%v\nbut got\n%v\n", tc.name, tc.wantRels, got.Relationships)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+// Captions names the property shown as the caption of nodes with each label, e.g. Customer: NAME1
+type Captions map[string]string
+
+// captionFallbacks are tried, in order, for nodes whose labels have no caption property or lack it
+var captionFallbacks []string = []string{"name", "Name", "title", "Title", "caption"}
+
+// Caption is the text a node is drawn with: the caption property of its first label which has one and
+// holds a value, otherwise a common naming property, otherwise its first label and id
+func (c Captions) Caption(n geno.Node) string {
+	for _, label := range n.Labels {
+		if val, found := n.Properties[c[label]]; c[label] != "" && found && val != nil {
+			return fmt.Sprint(exportValue(val))
+		}
+	}
+	for _, name := range captionFallbacks {
+		if val, found := n.Properties[name]; found && val != nil {
+			return fmt.Sprint(exportValue(val))
+		}
+	}
+	if len(n.Labels) == 0 {
+		return fmt.Sprint(n.Id)
+	}
+	return fmt.Sprintf("%s %d", n.Labels[0], n.Id)
+}
+
+// labelPalette holds fill colours which keep black text legible
+var labelPalette []string = []string{
+	"#8dd3c7", "#ffffb3", "#bebada", "#fb8072", "#80b1d3",
+	"#fdb462", "#b3de69", "#fccde5", "#d9d9d9", "#bc80bd",
+}
+
+// labelColors assigns a colour to the first label of every node, in order of first appearance,
+// so the same graph is always drawn with the same colours
+func labelColors(g Graph) map[string]string {
+	var colors map[string]string = make(map[string]string)
+	for _, n := range g.Nodes {
+		if len(n.Labels) == 0 {
+			continue
+		}
+		if _, found := colors[n.Labels[0]]; !found {
+			colors[n.Labels[0]] = labelPalette[len(colors)%len(labelPalette)]
+		}
+	}
+	return colors
+}
+
+// joinLabels writes labels as neo4j does, e.g. :Customer:Vendor
+func joinLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return ":" + strings.Join(labels, ":")
+}
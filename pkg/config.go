@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"syscall"
 
 	"github.com/Viking2012/geno/geno"
@@ -23,6 +24,58 @@ type Configuration struct {
 	User        string `mapstructure:"user"`
 	password    string
 	Constraints map[string]geno.Constraints `mapstructure:"constraints"`
+	Indexes     map[string]geno.Indexes     `mapstructure:"indexes"`
+	Profiles    map[string]Profile          `mapstructure:"profiles"`
+	Timestamps  map[string][]geno.Timestamp `mapstructure:"timestamps"`
+}
+
+// DatabaseTimestamps returns the timestamp properties configured for the configured database. Database names are
+// matched regardless of case, as the configuration file's keys are read in lower case.
+func (cfg *Configuration) DatabaseTimestamps() []geno.Timestamp {
+	if ts, found := cfg.Timestamps[cfg.Database]; found {
+		return ts
+	}
+	return cfg.Timestamps[strings.ToLower(cfg.Database)]
+}
+
+// TimestampProperty returns the property configured to hold when nodes of a label, or relationships of a type,
+// were last written in the configured database, or an empty string when none is
+func (cfg *Configuration) TimestampProperty(name string) string {
+	for _, ts := range cfg.DatabaseTimestamps() {
+		if ts.Label == name {
+			return ts.Property
+		}
+	}
+	return ""
+}
+
+// Profile names another server, such as production, which commands copying between servers can connect to
+// alongside the configured one
+type Profile struct {
+	Server string `mapstructure:"server"`
+	User   string `mapstructure:"user"`
+}
+
+// ProfileConfiguration returns the configuration connecting to a profile's server, sharing the constraints and
+// indexes of the configuration. The password is not shared and must be set before connecting. Profile names are
+// matched regardless of case, as the configuration file's keys are read in lower case.
+func (cfg *Configuration) ProfileConfiguration(name, database string) (Configuration, error) {
+	p, found := cfg.Profiles[name]
+	if !found {
+		p, found = cfg.Profiles[strings.ToLower(name)]
+	}
+	if !found {
+		return Configuration{}, fmt.Errorf("profile %s is not configured", name)
+	}
+	return Configuration{
+		Server:      p.Server,
+		Database:    database,
+		User:        p.User,
+		Constraints: cfg.Constraints,
+		Indexes:     cfg.Indexes,
+		Profiles:    cfg.Profiles,
+		Timestamps:  cfg.Timestamps,
+	}, nil
 }
 
 func ConfigFromBytes(raw []byte) (cfg Configuration, err error) {
@@ -138,6 +191,16 @@ func (cfg *Configuration) Validate() error {
 	return nil
 }
 
+// ValidateConstraints returns an error when the constraints configured for any database cannot be checked
+func (cfg *Configuration) ValidateConstraints() error {
+	for database, c := range cfg.Constraints {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("constraints of %s: %w", database, err)
+		}
+	}
+	return nil
+}
+
 func (cfg *Configuration) ValidateWithAttempts() error {
 	var triedToGetUsername bool = false
 	var triedToGetPassword bool = false
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"bytes"
 	"os"
 	"path"
 	"testing"
@@ -87,3 +88,65 @@ func Test_ValidateUsename(t *testing.T) {
 		}
 	}
 }
+
+func TestProfileConfiguration(t *testing.T) {
+	cfg := Configuration{
+		Server:   "localhost:7687",
+		Database: "staging",
+		User:     "geno",
+		Profiles: map[string]Profile{"prod": {Server: "prod.example.com:7687", User: "loader"}},
+	}
+	cfg.SetPassword("secret")
+
+	prod, err := cfg.ProfileConfiguration("prod", "neo4j")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.Server != "prod.example.com:7687" || prod.User != "loader" || prod.Database != "neo4j" {
+		t.Errorf("wanted the server and user of the profile with the database given, but got %+v", prod)
+	}
+	if prod.GetPassword() != "" {
+		t.Error("wanted the password of the configuration not to be shared with the profile")
+	}
+	if _, err := cfg.ProfileConfiguration("Prod", "neo4j"); err != nil {
+		t.Errorf("wanted profiles matched regardless of case, as viper reads them, but got %v", err)
+	}
+	if _, err := cfg.ProfileConfiguration("qa", "neo4j"); err == nil {
+		t.Error("wanted an unknown profile to be rejected")
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	raw := []byte(`database: PRD
+timestamps:
+  PRD:
+    - Label: Customer
+      Property: UPDATED_AT
+    - Label: HAS_PHONE
+      Property: ChangedAt
+`)
+	if err := v.ReadConfig(bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	var cfg Configuration
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.TimestampProperty("Customer"); got != "UPDATED_AT" {
+		t.Errorf("wanted the timestamp of Customer read with its case, but got %q from %v", got, cfg.Timestamps)
+	}
+	if got := cfg.TimestampProperty("HAS_PHONE"); got != "ChangedAt" {
+		t.Errorf("wanted the timestamp of HAS_PHONE read with its case, but got %q", got)
+	}
+	if got := cfg.TimestampProperty("customer"); got != "" {
+		t.Errorf("wanted labels matched with their case, but got %q", got)
+	}
+
+	prod, _ := (&Configuration{Timestamps: cfg.Timestamps, Profiles: map[string]Profile{"prod": {}}}).ProfileConfiguration("prod", "prd")
+	if got := prod.TimestampProperty("Customer"); got != "UPDATED_AT" {
+		t.Errorf("wanted the timestamps shared with a profile, but got %q", got)
+	}
+}
@@ -2,26 +2,32 @@ package pkg
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
 )
 
 type readNode struct {
-	Id     int64          `json:"identity"`
-	Labels []string       `json:"labels"`
-	Props  map[string]any `json:"properties"`
+	Id     int64          `json:"identity" yaml:"identity"`
+	Labels []string       `json:"labels" yaml:"labels"`
+	Props  map[string]any `json:"properties" yaml:"properties"`
 }
 
 type readRelationship struct {
-	Id         int64          `json:"identity"`
-	Start      int64          `json:"start"`
-	End        int64          `json:"end"`
-	Label      string         `json:"type"`
-	Properties map[string]any `json:"properties"`
+	Id         int64          `json:"identity" yaml:"identity"`
+	Start      int64          `json:"start" yaml:"start"`
+	End        int64          `json:"end" yaml:"end"`
+	Label      string         `json:"type" yaml:"type"`
+	Properties map[string]any `json:"properties" yaml:"properties"`
 }
 type readGraph struct {
-	Nodes []readNode         `json:"nodes"`
-	Rels  []readRelationship `json:"rels"`
+	Nodes []readNode         `json:"nodes" yaml:"nodes"`
+	Rels  []readRelationship `json:"rels" yaml:"rels"`
 }
 
 type Graph struct {
@@ -30,30 +36,139 @@ type Graph struct {
 }
 
 func GetGraphFromJson(raw []byte) (g Graph, err error) {
-	var js readGraph
+	return GetGraphFromJsonDocuments([][]byte{raw})
+}
 
-	if err := json.Unmarshal(raw, &js); err != nil {
+// GetGraphFromJsonDocuments reads several json documents as one graph. Relationships are resolved once every
+// document has been read, so the relationships of one document may refer to the nodes of another.
+func GetGraphFromJsonDocuments(docs [][]byte) (g Graph, err error) {
+	graphs, err := GetGraphsFromJsonDocuments([][][]byte{docs})
+	if err != nil {
 		return g, err
 	}
+	return graphs[0], nil
+}
+
+// GetGraphsFromJsonDocuments reads sets of json documents sharing one identity space: the relationships of any set
+// may refer to the nodes of any other by id, but each set becomes a graph of its own nodes and relationships.
+// A node id may not be used by more than one set.
+func GetGraphsFromJsonDocuments(sets [][][]byte) (graphs []Graph, err error) {
+	var (
+		sources []readGraph = make([]readGraph, len(sets))
+		all     []geno.Node
+		owner   map[int64]int = make(map[int64]int)
+	)
+	graphs = make([]Graph, len(sets))
+	for s, docs := range sets {
+		for _, raw := range docs {
+			var doc readGraph
+			if err := json.Unmarshal(raw, &doc); err != nil {
+				return graphs, err
+			}
+			sources[s].Nodes = append(sources[s].Nodes, doc.Nodes...)
+			sources[s].Rels = append(sources[s].Rels, doc.Rels...)
+		}
 
-	g.Nodes = make([]geno.Node, len(js.Nodes))
-	for i := range js.Nodes {
-		rawN := js.Nodes[i]
-		g.Nodes[i] = geno.NewNode(rawN.Id, rawN.Labels, rawN.Props)
+		graphs[s].Nodes = make([]geno.Node, len(sources[s].Nodes))
+		for i, rawN := range sources[s].Nodes {
+			if other, found := owner[rawN.Id]; found && other != s {
+				return graphs, fmt.Errorf("node id %d is used by sources %d and %d of the same identity space", rawN.Id, other+1, s+1)
+			}
+			owner[rawN.Id] = s
+			graphs[s].Nodes[i] = geno.NewNode(rawN.Id, rawN.Labels, rawN.Props)
+		}
+		all = append(all, graphs[s].Nodes...)
 	}
 
-	g.Relationships = make([]geno.Relationship, len(js.Rels))
-	for i := range js.Rels {
-		rawR := js.Rels[i]
-		start, err := findNodeById(rawR.Start, g.Nodes)
-		if err != nil {
-			return g, err
+	for s := range sources {
+		graphs[s].Relationships = make([]geno.Relationship, len(sources[s].Rels))
+		for i, rawR := range sources[s].Rels {
+			start, err := findNodeById(rawR.Start, all)
+			if err != nil {
+				return graphs, err
+			}
+			end, err := findNodeById(rawR.End, all)
+			if err != nil {
+				return graphs, err
+			}
+			graphs[s].Relationships[i] = geno.NewRelationship(rawR.Id, start, end, rawR.Label, rawR.Properties)
 		}
-		end, err := findNodeById(rawR.End, g.Nodes)
-		if err != nil {
-			return g, err
+	}
+	return graphs, nil
+}
+
+// WriteJson writes a graph in the same format read by GetGraphFromJson
+func WriteJson(w io.Writer, g Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(toReadGraph(g))
+}
+
+// toReadGraph converts a graph into the nodes and rels structure shared by the json and yaml formats
+func toReadGraph(g Graph) readGraph {
+	var js readGraph = readGraph{
+		Nodes: make([]readNode, len(g.Nodes)),
+		Rels:  make([]readRelationship, len(g.Relationships)),
+	}
+	for i, n := range g.Nodes {
+		js.Nodes[i] = readNode{Id: n.Id, Labels: n.Labels, Props: exportProperties(n.Properties)}
+	}
+	for i, r := range g.Relationships {
+		js.Rels[i] = readRelationship{Id: r.Id, Start: r.Start.Id, End: r.End.Id, Label: r.Label, Properties: exportProperties(r.Properties)}
+	}
+	return js
+}
+
+// exportValue converts the temporal values returned by neo4j into the ISO 8601 strings neo4j's
+// own exports use, so they survive formats which cannot hold them natively
+func exportValue(val any) any {
+	switch v := val.(type) {
+	case dbtype.Date:
+		return v.Time().Format("2006-01-02")
+	case dbtype.LocalTime:
+		return v.Time().Format("15:04:05.999999999")
+	case dbtype.LocalDateTime:
+		return v.Time().Format("2006-01-02T15:04:05.999999999")
+	case dbtype.Time:
+		return v.Time().Format("15:04:05.999999999Z07:00")
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case dbtype.Duration:
+		return v.String()
+	case []any:
+		var items []any = make([]any, len(v))
+		for i, item := range v {
+			items[i] = exportValue(item)
+		}
+		return items
+	default:
+		return val
+	}
+}
+
+// exportProperties applies exportValue to every property, leaving the original map untouched
+func exportProperties(props map[string]any) map[string]any {
+	if props == nil {
+		return nil
+	}
+	var converted map[string]any = make(map[string]any, len(props))
+	for key, val := range props {
+		converted[key] = exportValue(val)
+	}
+	return converted
+}
+
+// plainName replaces every character cypher would need a name quoted for with an underscore,
+// so that names taken from other formats can be used as labels, types and property keys
+func plainName(name string) string {
+	plain := strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' {
+			return c
 		}
-		g.Relationships[i] = geno.NewRelationship(rawR.Id, start, end, rawR.Label, rawR.Properties)
+		return '_'
+	}, strings.TrimSpace(name))
+	if plain != "" && unicode.IsDigit([]rune(plain)[0]) {
+		plain = "_" + plain
 	}
-	return g, nil
+	return plain
 }
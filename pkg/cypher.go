@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+// cypherBatch is a set of nodes or relationships merged by the same UNWIND statement
+type cypherBatch struct {
+	statement string
+	rows      []any
+}
+
+// sortedKeys returns the keys of a map in order, for grouping elements of the same shape
+func sortedKeys(m map[string]any) []string {
+	var keys []string = make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// groupBatches collects rows under the statement built for the first element of their shape, in order of first appearance
+func groupBatches(count int, shape func(i int) string, statement func(i int) string, row func(i int) any) []cypherBatch {
+	var (
+		index   map[string]int = make(map[string]int)
+		batches []cypherBatch
+	)
+	for i := 0; i < count; i++ {
+		key := shape(i)
+		b, found := index[key]
+		if !found {
+			b = len(batches)
+			index[key] = b
+			batches = append(batches, cypherBatch{statement: statement(i)})
+		}
+		batches[b].rows = append(batches[b].rows, row(i))
+	}
+	return batches
+}
+
+// WriteCypher writes a script which cypher-shell can run to recreate a graph: statements creating the
+// constraints neo4j can hold, followed by UNWIND statements merging batches of up to batchSize nodes,
+// then relationships. Each batch is passed as a :param block. Statements are built by the same
+// merge builders used by geno import, so the script merges exactly as an import with the same constraints and
+// timestamps would, keeping the values of existing elements. When a server version is given, constraints it cannot
+// hold are written as comments rather than statements.
+func WriteCypher(w io.Writer, g Graph, c *geno.Constraints, timestamps []geno.Timestamp, version *geno.ServerVersion, batchSize int) error {
+	if batchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, not %d", batchSize)
+	}
+	ddl, err := c.ToCypherCreate()
+	if err != nil {
+		return err
+	}
+	var unsupported []string
+	if version != nil {
+		ddl, unsupported = version.SupportedStatements(ddl)
+	}
+
+	nodeBatches := groupBatches(len(g.Nodes),
+		func(i int) string {
+			n := &g.Nodes[i]
+			constraints := c.GetNodeConstraints(n)
+			sort.Strings(constraints)
+			return strings.Join([]string{n.String(), strings.Join(constraints, ","), strings.Join(sortedKeys(n.Properties), ",")}, "|")
+		},
+		func(i int) string {
+			n := &g.Nodes[i]
+			return n.ToCypherUnwindMerge(c.GetNodeConstraints(n), "row") + geno.StampCreate("n", geno.TimestampOf(timestamps, n.Labels...))
+		},
+		func(i int) any {
+			props := g.Nodes[i].Properties
+			if props == nil {
+				props = map[string]any{}
+			}
+			return props
+		})
+
+	relBatches := groupBatches(len(g.Relationships),
+		func(i int) string {
+			r := &g.Relationships[i]
+			row := r.UnwindRow(c.GetNodeConstraints(&r.Start), c.GetNodeConstraints(&r.End))
+			return strings.Join([]string{
+				r.Label,
+				r.Start.String(), strings.Join(sortedKeys(row["left"].(map[string]any)), ","),
+				r.End.String(), strings.Join(sortedKeys(row["right"].(map[string]any)), ","),
+				strings.Join(sortedKeys(r.Properties), ","),
+			}, "|")
+		},
+		func(i int) string {
+			r := &g.Relationships[i]
+			return r.ToCypherUnwindMerge(c.GetNodeConstraints(&r.Start), c.GetNodeConstraints(&r.End), c.GetRelationshipConstraints(r), "row") +
+				geno.StampCreate("r", geno.TimestampOf(timestamps, r.Label))
+		},
+		func(i int) any {
+			r := &g.Relationships[i]
+			return r.UnwindRow(c.GetNodeConstraints(&r.Start), c.GetNodeConstraints(&r.End))
+		})
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "// %d nodes and %d relationships exported by geno\n", len(g.Nodes), len(g.Relationships))
+	fmt.Fprintln(out, "// run with: cypher-shell -d <DATABASE> -f <FILE>")
+	if len(ddl) > 0 || len(unsupported) > 0 {
+		fmt.Fprintln(out, "\n// constraints")
+	}
+	for _, statement := range ddl {
+		fmt.Fprintln(out, statement+";")
+	}
+	for _, statement := range unsupported {
+		fmt.Fprintf(out, "// left out, as neo4j %s cannot hold it: %s\n", version, statement)
+	}
+	for _, section := range []struct {
+		name    string
+		batches []cypherBatch
+	}{{"nodes", nodeBatches}, {"relationships", relBatches}} {
+		if len(section.batches) > 0 {
+			fmt.Fprintln(out, "\n// "+section.name)
+		}
+		for _, b := range section.batches {
+			for start := 0; start < len(b.rows); start += batchSize {
+				end := start + batchSize
+				if end > len(b.rows) {
+					end = len(b.rows)
+				}
+				fmt.Fprintf(out, ":param rows => %s\n", geno.CypherLiteral(b.rows[start:end]))
+				fmt.Fprintf(out, "UNWIND $rows AS row\n%s;\n", strings.TrimSuffix(b.statement, "\n"))
+			}
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestWriteCypher(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "1", "NAME1": "Acme's"})
+		nodeB geno.Node         = geno.NewNode(2, []string{"Customer"}, map[string]any{"KUNNR": "2", "NAME1": "Widgets"})
+		nodeC geno.Node         = geno.NewNode(3, []string{"Customer"}, map[string]any{"KUNNR": "3"})
+		relA  geno.Relationship = geno.NewRelationship(4, nodeA, nodeB, "REFERS", map[string]any{"count": int64(2)})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB, nodeC}, Relationships: []geno.Relationship{relA}}
+		c     geno.Constraints  = geno.Constraints{
+			NodeKeys:         []geno.Constraint{{Label: "Customer", Properties: []string{"KUNNR"}}},
+			RelationshipKeys: []geno.Constraint{{Label: "REFERS", Properties: []string{"count"}}},
+		}
+		timestamps []geno.Timestamp = []geno.Timestamp{{Label: "Customer", Property: "UPDATED_AT"}}
+		buf        bytes.Buffer
+	)
+	if err := WriteCypher(&buf, g, &c, timestamps, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+// constraints
+CREATE CONSTRAINT IF NOT EXISTS FOR (e:` + "`Customer`" + `) REQUIRE (e.` + "`KUNNR`" + `) IS NODE KEY;
+CREATE CONSTRAINT IF NOT EXISTS FOR ()-[e:` + "`REFERS`" + `]-() REQUIRE (e.` + "`count`" + `) IS RELATIONSHIP KEY;
+
+// nodes
+:param rows => [{` + "`KUNNR`: '1', `NAME1`: 'Acme\\'s'" + `}]
+UNWIND $rows AS row
+MERGE (n:Customer {KUNNR:row.` + "`KUNNR`" + `})
+ON CREATE SET n.NAME1=row.` + "`NAME1`" + `
+ON CREATE SET n.` + "`UPDATED_AT`" + ` = datetime();
+:param rows => [{` + "`KUNNR`: '2', `NAME1`: 'Widgets'" + `}]
+UNWIND $rows AS row
+MERGE (n:Customer {KUNNR:row.` + "`KUNNR`" + `})
+ON CREATE SET n.NAME1=row.` + "`NAME1`" + `
+ON CREATE SET n.` + "`UPDATED_AT`" + ` = datetime();
+:param rows => [{` + "`KUNNR`: '3'" + `}]
+UNWIND $rows AS row
+MERGE (n:Customer {KUNNR:row.` + "`KUNNR`" + `})
+ON CREATE SET n.` + "`UPDATED_AT`" + ` = datetime();
+
+// relationships
+:param rows => [{` + "`left`: {`KUNNR`: '1'}, `properties`: {`count`: 2}, `right`: {`KUNNR`: '2'}" + `}]
+UNWIND $rows AS row
+MATCH (left:Customer {KUNNR:row.left.` + "`KUNNR`" + `})
+MATCH (right:Customer {KUNNR:row.right.` + "`KUNNR`" + `})
+MERGE (left)-[r:REFERS {count:row.properties.` + "`count`" + `}]-(right);
+`
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("wanted a script ending with\n%s\nbut got\n%s", want, buf.String())
+	}
+
+	statements, err := geno.ParseScript(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 10 {
+		t.Errorf("wanted the script to parse into 10 statements but got %d", len(statements))
+	}
+}
@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"sort"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+)
+
+// graphCollector gathers the unique nodes and relationships found in query results
+type graphCollector struct {
+	nodes     map[int64]geno.Node
+	nodeOrder []int64
+	rels      map[int64]neo4j.Relationship
+	relOrder  []int64
+}
+
+func newGraphCollector() *graphCollector {
+	return &graphCollector{nodes: make(map[int64]geno.Node), rels: make(map[int64]neo4j.Relationship)}
+}
+
+func (c *graphCollector) addNode(n neo4j.Node) {
+	if _, found := c.nodes[n.Id]; found {
+		return
+	}
+	c.nodes[n.Id] = geno.NewNode(n.Id, n.Labels, n.Props)
+	c.nodeOrder = append(c.nodeOrder, n.Id)
+}
+
+func (c *graphCollector) addRelationship(r neo4j.Relationship) {
+	if _, found := c.rels[r.Id]; found {
+		return
+	}
+	c.rels[r.Id] = r
+	c.relOrder = append(c.relOrder, r.Id)
+}
+
+// walk collects every node, relationship and path within a value, descending into lists and maps
+func (c *graphCollector) walk(value any) {
+	switch v := value.(type) {
+	case neo4j.Node:
+		c.addNode(v)
+	case neo4j.Relationship:
+		c.addRelationship(v)
+	case neo4j.Path:
+		for _, n := range v.Nodes {
+			c.addNode(n)
+		}
+		for _, r := range v.Relationships {
+			c.addRelationship(r)
+		}
+	case []any:
+		for _, item := range v {
+			c.walk(item)
+		}
+	case map[string]any:
+		var keys []string = make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys) // keeps the order of collected elements stable between runs
+		for _, key := range keys {
+			c.walk(v[key])
+		}
+	}
+}
+
+// collect walks every value of every record
+func (c *graphCollector) collect(records []*db.Record) {
+	for _, record := range records {
+		for _, value := range record.Values {
+			c.walk(value)
+		}
+	}
+}
+
+// missingEndpoints returns the ids of relationship endpoints which were not themselves collected
+func (c *graphCollector) missingEndpoints() []int64 {
+	var (
+		missing []int64
+		seen    map[int64]bool = make(map[int64]bool)
+	)
+	for _, id := range c.relOrder {
+		r := c.rels[id]
+		for _, endpoint := range []int64{r.StartId, r.EndId} {
+			if _, found := c.nodes[endpoint]; !found && !seen[endpoint] {
+				seen[endpoint] = true
+				missing = append(missing, endpoint)
+			}
+		}
+	}
+	return missing
+}
+
+func (c *graphCollector) graph() Graph {
+	var g Graph = Graph{
+		Nodes:         make([]geno.Node, 0, len(c.nodeOrder)),
+		Relationships: make([]geno.Relationship, 0, len(c.relOrder)),
+	}
+	for _, id := range c.nodeOrder {
+		g.Nodes = append(g.Nodes, c.nodes[id])
+	}
+	for _, id := range c.relOrder {
+		r := c.rels[id]
+		g.Relationships = append(g.Relationships, geno.NewRelationship(r.Id, c.nodes[r.StartId], c.nodes[r.EndId], r.Type, r.Props))
+	}
+	return g
+}
+
+// GraphFromRecords collects the unique nodes and relationships held anywhere within query results,
+// including those within paths, lists and maps
+func GraphFromRecords(records []*db.Record) Graph {
+	collector := newGraphCollector()
+	collector.collect(records)
+	return collector.graph()
+}
+
+// GetGraphFromDb runs a read query and returns every node and relationship it returns.
+// Endpoints of returned relationships are fetched as well when the query does not return them itself.
+func GetGraphFromDb(driver *geno.Driver, database, cypher string, params map[string]any) (Graph, error) {
+	records, err := driver.ReadRecords(database, cypher, params)
+	if err != nil {
+		return Graph{}, err
+	}
+	return graphWithEndpoints(driver, database, records)
+}
+
+// StreamGraphFromDb runs a read query as GetGraphFromDb does, but calls page with the graph of every pageSize records
+// as they arrive rather than holding them all. Each page holds the endpoints of its relationships, so an element
+// returned by records of several pages is part of each of them.
+func StreamGraphFromDb(driver *geno.Driver, database, cypher string, params map[string]any, pageSize int, page func(Graph) error) error {
+	return driver.StreamRecords(database, cypher, params, pageSize, func(records []*neo4j.Record) error {
+		g, err := graphWithEndpoints(driver, database, records)
+		if err != nil {
+			return err
+		}
+		return page(g)
+	})
+}
+
+// graphWithEndpoints collects the graph of records, fetching the endpoints of relationships they do not hold
+func graphWithEndpoints(driver *geno.Driver, database string, records []*db.Record) (Graph, error) {
+	collector := newGraphCollector()
+	collector.collect(records)
+
+	if missing := collector.missingEndpoints(); len(missing) > 0 {
+		records, err := driver.ReadRecords(database, "MATCH (n) WHERE id(n) IN $ids RETURN n", map[string]any{"ids": missing})
+		if err != nil {
+			return Graph{}, err
+		}
+		collector.collect(records)
+	}
+
+	return collector.graph(), nil
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DotOptions configures how nodes are drawn by Graphviz
+type DotOptions struct {
+	Captions Captions
+	Styles   map[string]string // extra attributes for nodes by first label, e.g. Customer: shape=ellipse; penwidth=2
+}
+
+// dotString quotes a string as a DOT identifier
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r\n", `\n`, "\n", `\n`).Replace(s) + `"`
+}
+
+// WriteDot writes a graph in Graphviz's DOT language. Nodes are drawn with their caption, filled with a colour
+// for their first label and styled by any attributes configured for that label; relationships are labelled with
+// their type. Labels are written into each node's tooltip.
+func WriteDot(w io.Writer, g Graph, opts DotOptions) error {
+	var (
+		out    *bufio.Writer     = bufio.NewWriter(w)
+		colors map[string]string = labelColors(g)
+	)
+	fmt.Fprintln(out, "digraph geno {")
+	fmt.Fprintln(out, `    node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"];`)
+	fmt.Fprintln(out, `    edge [fontname="Helvetica", fontsize=10];`)
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotString(opts.Captions.Caption(n)), "tooltip=" + dotString(joinLabels(n.Labels))}
+		if len(n.Labels) > 0 {
+			attrs = append(attrs, "fillcolor="+dotString(colors[n.Labels[0]]))
+			if style := strings.TrimSpace(opts.Styles[n.Labels[0]]); style != "" {
+				attrs = append(attrs, style)
+			}
+		}
+		fmt.Fprintf(out, "    \"n%d\" [%s];\n", n.Id, strings.Join(attrs, ", "))
+	}
+	for _, r := range g.Relationships {
+		fmt.Fprintf(out, "    \"n%d\" -> \"n%d\" [label=%s];\n", r.Start.Id, r.End.Id, dotString(r.Label))
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestWriteDot(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"Customer"}, map[string]any{"NAME1": "Acme \"Steel\"", "name": "ignored"})
+		nodeB geno.Node         = geno.NewNode(2, []string{"Phone"}, map[string]any{"Value": "555-0100"})
+		relA  geno.Relationship = geno.NewRelationship(3, nodeA, nodeB, "HAS_PHONE", map[string]any{})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB}, Relationships: []geno.Relationship{relA}}
+		buf   bytes.Buffer
+	)
+	opts := DotOptions{Captions: Captions{"Customer": "NAME1"}, Styles: map[string]string{"Phone": "shape=ellipse"}}
+	if err := WriteDot(&buf, g, opts); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph geno {
+    node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"];
+    edge [fontname="Helvetica", fontsize=10];
+    "n1" [label="Acme \"Steel\"", tooltip=":Customer", fillcolor="#8dd3c7"];
+    "n2" [label="Phone 2", tooltip=":Phone", fillcolor="#ffffb3", shape=ellipse];
+    "n1" -> "n2" [label="HAS_PHONE"];
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package pkg
+
+import "github.com/Viking2012/geno/geno"
+
+// FilterGraph keeps the nodes with any of the given labels and the relationships of any of the given types
+// between them. An empty list of labels or types keeps every node or relationship. Relationships are only
+// kept when the nodes at both ends are, so the result never refers to nodes it does not hold.
+func FilterGraph(g Graph, labels, types []string) Graph {
+	var (
+		filtered   Graph
+		keptNodes  map[int64]bool  = make(map[int64]bool, len(g.Nodes))
+		wantLabels map[string]bool = make(map[string]bool, len(labels))
+		wantTypes  map[string]bool = make(map[string]bool, len(types))
+	)
+	for _, label := range labels {
+		wantLabels[label] = true
+	}
+	for _, t := range types {
+		wantTypes[t] = true
+	}
+
+	for _, n := range g.Nodes {
+		if len(wantLabels) > 0 && !hasAnyLabel(n, wantLabels) {
+			continue
+		}
+		keptNodes[n.Id] = true
+		filtered.Nodes = append(filtered.Nodes, n)
+	}
+	for _, r := range g.Relationships {
+		if len(wantTypes) > 0 && !wantTypes[r.Label] {
+			continue
+		}
+		if !keptNodes[r.Start.Id] || !keptNodes[r.End.Id] {
+			continue
+		}
+		filtered.Relationships = append(filtered.Relationships, r)
+	}
+	return filtered
+}
+
+func hasAnyLabel(n geno.Node, labels map[string]bool) bool {
+	for _, label := range n.Labels {
+		if labels[label] {
+			return true
+		}
+	}
+	return false
+}
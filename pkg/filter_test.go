@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestFilterGraph(t *testing.T) {
+	var (
+		customer geno.Node         = geno.NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "1"})
+		vendor   geno.Node         = geno.NewNode(2, []string{"Vendor", "Customer"}, map[string]any{"LIFNR": "2"})
+		phone    geno.Node         = geno.NewNode(3, []string{"Phone"}, map[string]any{"Value": "555-0100"})
+		buys     geno.Relationship = geno.NewRelationship(4, customer, vendor, "BUYS_FROM", map[string]any{})
+		refers   geno.Relationship = geno.NewRelationship(5, vendor, customer, "REFERS", map[string]any{})
+		hasPhone geno.Relationship = geno.NewRelationship(6, customer, phone, "HAS_PHONE", map[string]any{})
+		g        Graph             = Graph{Nodes: []geno.Node{customer, vendor, phone}, Relationships: []geno.Relationship{buys, refers, hasPhone}}
+	)
+
+	tests := []struct {
+		name   string
+		labels []string
+		types  []string
+		want   Graph
+	}{
+		{"everything", nil, nil, g},
+		{"by label", []string{"Customer"}, nil, Graph{Nodes: []geno.Node{customer, vendor}, Relationships: []geno.Relationship{buys, refers}}},
+		{"by type", nil, []string{"HAS_PHONE"}, Graph{Nodes: []geno.Node{customer, vendor, phone}, Relationships: []geno.Relationship{hasPhone}}},
+		{"by both", []string{"Customer"}, []string{"HAS_PHONE", "REFERS"}, Graph{Nodes: []geno.Node{customer, vendor}, Relationships: []geno.Relationship{refers}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterGraph(g, tt.labels, tt.types); !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("wanted\n%v\nbut got\n%v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// TablePattern describes a flat table of the graph: one row per node of the anchor label, identified by its keys,
+// with a column for each property of the anchor or of the nodes related to it, e.g. the Name, Phone and Bank value
+// nodes of every Customer. Values of several related nodes are joined by the separator.
+type TablePattern struct {
+	Anchor    string             `yaml:"Anchor"`
+	Keys      []string           `yaml:"Keys"`      // properties identifying the rows, defaults to the constraint keys of the anchor
+	Separator string             `yaml:"Separator"` // joins the values of list columns, defaults to "; "
+	Columns   []geno.TableColumn `yaml:"Columns"`
+}
+
+// ReadTablePattern reads a pattern written in yaml, naming each column which has no name after its property.
+// Columns must be named apart from each other and from the keys of the pattern.
+func ReadTablePattern(raw []byte) (p TablePattern, err error) {
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return p, err
+	}
+	if p.Anchor == "" {
+		return p, errors.New("a table pattern must name its anchor label")
+	}
+	if len(p.Columns) == 0 {
+		return p, errors.New("a table pattern must have at least one column")
+	}
+	if p.Separator == "" {
+		p.Separator = "; "
+	}
+	for i := range p.Columns {
+		c := &p.Columns[i]
+		if c.Name == "" {
+			c.Name = c.Property
+		}
+		if err := c.Validate(); err != nil {
+			return p, err
+		}
+	}
+	if _, err := p.header(p.Keys); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// header names the key columns followed by the columns of the pattern, none of which may share a name
+func (p TablePattern) header(keys []string) ([]string, error) {
+	var (
+		header []string        = make([]string, 0, len(keys)+len(p.Columns))
+		names  map[string]bool = make(map[string]bool)
+	)
+	for _, key := range keys {
+		if names[key] {
+			return nil, fmt.Errorf("key %s is given more than once", key)
+		}
+		names[key] = true
+		header = append(header, key)
+	}
+	for _, column := range p.Columns {
+		if names[column.Name] && containsString(keys, column.Name) {
+			return nil, fmt.Errorf("column %s is named after a key column of :%s; give it a distinct Name", column.Name, p.Anchor)
+		}
+		if names[column.Name] {
+			return nil, fmt.Errorf("column %s is named more than once; give each a distinct Name", column.Name)
+		}
+		names[column.Name] = true
+		header = append(header, column.Name)
+	}
+	return header, nil
+}
+
+// CheckSheetName reports whether a name may name an xlsx sheet: it must have between 1 and 31 characters, none of
+// which are : \ / ? * [ or ], and neither start nor end with an apostrophe
+func CheckSheetName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("a sheet must be named")
+	case utf8.RuneCountInString(name) > 31:
+		return fmt.Errorf("sheet name %s is longer than the 31 characters excel allows", name)
+	case strings.ContainsAny(name, `:\/?*[]`):
+		return fmt.Errorf("sheet name %s holds one of the characters : \\ / ? * [ ] which excel forbids", name)
+	case strings.HasPrefix(name, "'") || strings.HasSuffix(name, "'"):
+		return fmt.Errorf("sheet name %s starts or ends with an apostrophe, which excel forbids", name)
+	}
+	return nil
+}
+
+// keys returns the properties identifying the rows: those of the pattern, or the uniqueness, key and existence
+// constraint properties of the anchor in order
+func (p TablePattern) keys(c *geno.Constraints) ([]string, error) {
+	if len(p.Keys) > 0 {
+		return p.Keys, nil
+	}
+	var keys []string = c.GetNodeConstraints(&geno.Node{Labels: []string{p.Anchor}})
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no constraint properties are configured for :%s to identify its rows by; give the pattern Keys", p.Anchor)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetTable reads the rows of a pattern, in order of their keys, along with the header naming the keys and columns.
+// Cells hold the value of a property, the distinct values of list columns joined by the separator, or their count.
+func GetTable(driver *geno.Driver, database string, p TablePattern, c *geno.Constraints) (header []string, rows [][]any, err error) {
+	keys, err := p.keys(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header, err = p.header(keys); err != nil {
+		return nil, nil, err
+	}
+	records, err := driver.ReadRecords(database, geno.TableQuery(p.Anchor, keys, p.Columns), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows = make([][]any, 0, len(records))
+	for _, record := range records {
+		var row []any = make([]any, 0, len(header))
+		for i := range keys {
+			value, _ := record.Get(fmt.Sprintf("k%d", i))
+			row = append(row, tableCell(value, p.Separator))
+		}
+		for i, column := range p.Columns {
+			value, _ := record.Get(fmt.Sprintf("c%d", i))
+			row = append(row, tableCell(column.Cell(value), p.Separator))
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
+
+// tableCell writes lists as their values joined by the separator, and temporal values as text
+func tableCell(value any, separator string) any {
+	switch v := exportValue(value).(type) {
+	case []any:
+		var items []string = make([]string, len(v))
+		for i, item := range v {
+			items[i] = cellText(item)
+		}
+		return strings.Join(items, separator)
+	default:
+		return v
+	}
+}
+
+// WriteTableCsv writes a table as a delimited table with a header row
+func WriteTableCsv(w io.Writer, header []string, rows [][]any) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		var cells []string = make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cellText(cell)
+		}
+		if err := out.Write(cells); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteTableXlsx writes a table as a workbook of a single sheet with a header row. Numbers and booleans keep their type.
+func WriteTableXlsx(w io.Writer, sheet string, header []string, rows [][]any) error {
+	if err := CheckSheetName(sheet); err != nil {
+		return err
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	var cells []any = make([]any, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	if err := stream.SetRow("A1", cells); err != nil {
+		return err
+	}
+	for r, row := range rows {
+		axis, err := excelize.CoordinatesToCellName(1, r+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(axis, row); err != nil {
+			return err
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
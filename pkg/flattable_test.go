@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadTablePattern(t *testing.T) {
+	raw := []byte(`Anchor: Customer
+Columns:
+  - Property: NAME1
+  - Name: Phone
+    Relationship: HAS_PHONE
+    Property: Phone
+`)
+	p, err := ReadTablePattern(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Separator != "; " || p.Columns[0].Name != "NAME1" {
+		t.Errorf("wanted the default separator and columns named after their property, but got %+v", p)
+	}
+
+	c := geno.Constraints{NodeKeys: []geno.Constraint{{Label: "Customer", Properties: []string{"KUNNR", "BUKRS"}}}}
+	if keys, err := p.keys(&c); err != nil || !reflect.DeepEqual(keys, []string{"BUKRS", "KUNNR"}) {
+		t.Errorf("wanted the constraint keys of the anchor in order, but got %v, %v", keys, err)
+	}
+	if _, err := p.keys(&geno.Constraints{}); err == nil {
+		t.Error("wanted an anchor without keys to be rejected")
+	}
+
+	for _, bad := range []string{
+		"Columns:\n  - Property: NAME1\n",
+		"Anchor: Customer\n",
+		"Anchor: Customer\nColumns:\n  - Property: NAME1\n  - Property: NAME1\n",
+		"Anchor: Customer\nKeys: [KUNNR]\nColumns:\n  - Property: KUNNR\n",
+	} {
+		if _, err := ReadTablePattern([]byte(bad)); err == nil {
+			t.Errorf("wanted pattern %q to be rejected", bad)
+		}
+	}
+	if _, err := (TablePattern{Anchor: "Customer", Columns: []geno.TableColumn{{Name: "BUKRS"}}}).header([]string{"BUKRS", "KUNNR"}); err == nil {
+		t.Error("wanted a column named after a constraint key to be rejected")
+	}
+
+	for name, valid := range map[string]bool{
+		"Customer":                         true,
+		"":                                 false,
+		"Customers/Vendors":                false,
+		"[Customer]":                       false,
+		"'Customer'":                       false,
+		"CustomerWithAVeryLongLabelName01": false,
+	} {
+		if err := CheckSheetName(name); (err == nil) != valid {
+			t.Errorf("sheet name %q: wanted valid %v, but got %v", name, valid, err)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	header := []string{"KUNNR", "Name", "Phone", "Phones", "Founded"}
+	rows := [][]any{
+		{"0000001", "ACME", tableCell([]any{"030 1", "030 2"}, "; "), int64(2), tableCell(dbtype.Date(time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)), "; ")},
+		{"0000002", nil, "", int64(0), nil},
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteTableCsv(&csvOut, header, rows); err != nil {
+		t.Fatal(err)
+	}
+	want := "KUNNR,Name,Phone,Phones,Founded\n0000001,ACME,030 1; 030 2,2,2001-02-03\n0000002,,,0,\n"
+	if csvOut.String() != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, csvOut.String())
+	}
+
+	var xlsxOut bytes.Buffer
+	if err := WriteTableXlsx(&xlsxOut, "Customer", header, rows); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&xlsxOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := f.GetRows("Customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 3 || read[1][2] != "030 1; 030 2" || read[1][3] != "2" {
+		t.Errorf("wanted the header and two rows written to the sheet, but got %v", read)
+	}
+}
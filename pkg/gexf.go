@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const gexfNamespace string = "http://gexf.net/1.3"
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator string `xml:"creator"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	Id    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	Id     string         `xml:"id,attr"`
+	Label  string         `xml:"label,attr"`
+	Values []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	Id     string         `xml:"id,attr"`
+	Source string         `xml:"source,attr"`
+	Target string         `xml:"target,attr"`
+	Label  string         `xml:"label,attr"`
+	Kind   string         `xml:"kind,attr"`
+	Values []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfAttributesOf declares an attribute for each graphml key, with the same types;
+// lists are declared as GEXF list types, e.g. liststring
+func gexfAttributesOf(class string, keys []graphmlKey) gexfAttributes {
+	var attrs gexfAttributes = gexfAttributes{Class: class}
+	for _, k := range keys {
+		t := k.AttrType
+		if k.AttrList != "" {
+			t = "list" + k.AttrList
+		}
+		attrs.Attributes = append(attrs.Attributes, gexfAttribute{Id: k.Id, Title: k.AttrName, Type: t})
+	}
+	return attrs
+}
+
+func gexfValuesOf(data []graphmlData) []gexfAttValue {
+	var values []gexfAttValue = make([]gexfAttValue, 0, len(data))
+	for _, d := range data {
+		values = append(values, gexfAttValue{For: d.Key, Value: d.Value})
+	}
+	return values
+}
+
+// WriteGexf writes a graph as GEXF for Gephi. Nodes are labelled with their caption and relationships with
+// their type, which is also their kind so that parallel relationships of different types stay apart.
+// Labels, written as :Customer:Vendor, and relationship types are attributes alongside every property,
+// so Gephi can partition and colour by them.
+func WriteGexf(w io.Writer, g Graph, captions Captions) error {
+	var (
+		nodeProps []map[string]any = make([]map[string]any, len(g.Nodes))
+		relProps  []map[string]any = make([]map[string]any, len(g.Relationships))
+		keyIndex  map[string]graphmlKey
+		doc       gexfDocument = gexfDocument{Xmlns: gexfNamespace, Version: "1.3", Meta: gexfMeta{Creator: "geno"}}
+	)
+	for i, n := range g.Nodes {
+		nodeProps[i] = exportProperties(n.Properties)
+	}
+	for i, r := range g.Relationships {
+		relProps[i] = exportProperties(r.Properties)
+	}
+	nodeKeys, nodeIds := graphmlKeys("node", "n", nodeProps)
+	relKeys, relIds := graphmlKeys("edge", "e", relProps)
+	nodeKeys = append([]graphmlKey{{Id: "labels", For: "node", AttrName: "labels", AttrType: "string"}}, nodeKeys...)
+	relKeys = append([]graphmlKey{{Id: "type", For: "edge", AttrName: "type", AttrType: "string"}}, relKeys...)
+	keyIndex = make(map[string]graphmlKey, len(nodeKeys)+len(relKeys))
+	for _, k := range append(nodeKeys, relKeys...) {
+		keyIndex[k.Id] = k
+	}
+
+	doc.Graph = gexfGraph{
+		DefaultEdgeType: "directed",
+		Mode:            "static",
+		Attributes:      []gexfAttributes{gexfAttributesOf("node", nodeKeys), gexfAttributesOf("edge", relKeys)},
+	}
+	for i, n := range g.Nodes {
+		data := append([]graphmlData{{Key: "labels", Value: joinLabels(n.Labels)}}, graphmlDataOf(nodeProps[i], nodeIds, keyIndex)...)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{Id: fmt.Sprint(n.Id), Label: captions.Caption(n), Values: gexfValuesOf(data)})
+	}
+	for i, r := range g.Relationships {
+		data := append([]graphmlData{{Key: "type", Value: r.Label}}, graphmlDataOf(relProps[i], relIds, keyIndex)...)
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			Id:     fmt.Sprint(r.Id),
+			Source: fmt.Sprint(r.Start.Id),
+			Target: fmt.Sprint(r.End.Id),
+			Label:  r.Label,
+			Kind:   r.Label,
+			Values: gexfValuesOf(data),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestWriteGexf(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"Customer", "Vendor"}, map[string]any{"NAME1": "Acme", "tags": []any{"a", "b"}})
+		nodeB geno.Node         = geno.NewNode(2, []string{"Phone"}, map[string]any{"Value": "555-0100"})
+		relA  geno.Relationship = geno.NewRelationship(3, nodeA, nodeB, "HAS_PHONE", map[string]any{"since": int64(2001)})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB}, Relationships: []geno.Relationship{relA}}
+		buf   bytes.Buffer
+	)
+	if err := WriteGexf(&buf, g, Captions{"Customer": "NAME1"}); err != nil {
+		t.Fatal(err)
+	}
+	var doc gexfDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	wantAttrs := []gexfAttributes{
+		{Class: "node", Attributes: []gexfAttribute{{"labels", "labels", "string"}, {"n0", "NAME1", "string"}, {"n1", "Value", "string"}, {"n2", "tags", "liststring"}}},
+		{Class: "edge", Attributes: []gexfAttribute{{"type", "type", "string"}, {"e0", "since", "long"}}},
+	}
+	if !reflect.DeepEqual(wantAttrs, doc.Graph.Attributes) {
+		t.Errorf("wanted attributes\n%v\nbut got\n%v", wantAttrs, doc.Graph.Attributes)
+	}
+	wantNodes := []gexfNode{
+		{Id: "1", Label: "Acme", Values: []gexfAttValue{{"labels", ":Customer:Vendor"}, {"n0", "Acme"}, {"n2", `["a","b"]`}}},
+		{Id: "2", Label: "Phone 2", Values: []gexfAttValue{{"labels", ":Phone"}, {"n1", "555-0100"}}},
+	}
+	if !reflect.DeepEqual(wantNodes, doc.Graph.Nodes) {
+		t.Errorf("wanted nodes\n%v\nbut got\n%v", wantNodes, doc.Graph.Nodes)
+	}
+	wantEdges := []gexfEdge{{Id: "3", Source: "1", Target: "2", Label: "HAS_PHONE", Kind: "HAS_PHONE", Values: []gexfAttValue{{"type", "HAS_PHONE"}, {"e0", "2001"}}}}
+	if !reflect.DeepEqual(wantEdges, doc.Graph.Edges) {
+		t.Errorf("wanted edges\n%v\nbut got\n%v", wantEdges, doc.Graph.Edges)
+	}
+}
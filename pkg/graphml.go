@@ -0,0 +1,406 @@
+package pkg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+const graphmlNamespace string = "http://graphml.graphdrawing.org/xmlns"
+
+type graphmlDocument struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphmlKey   `xml:"key"`
+	Graphs  []graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	Id       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr,omitempty"`
+	AttrType string `xml:"attr.type,attr,omitempty"`
+	AttrList string `xml:"attr.list,attr,omitempty"` // list element type, as written by APOC
+	Default  string `xml:"default,omitempty"`
+}
+
+type graphmlGraph struct {
+	Id          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	Id     string        `xml:"id,attr"`
+	Labels string        `xml:"labels,attr,omitempty"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Id     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Label  string        `xml:"label,attr,omitempty"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// GraphMLOptions names the attributes holding neo4j labels and relationship types
+type GraphMLOptions struct {
+	LabelAttribute string // node attribute holding labels, separated by colons, e.g. :Customer:Vendor
+	TypeAttribute  string // edge attribute holding the relationship type
+	DefaultLabel   string // label of nodes without any, which are otherwise an error when read
+}
+
+// DefaultGraphMLOptions match the attributes written by APOC's GraphML export
+var DefaultGraphMLOptions GraphMLOptions = GraphMLOptions{LabelAttribute: "labels", TypeAttribute: "label"}
+
+// GetGraphFromGraphML reads nodes and edges of the first graph in a GraphML document.
+// Typed keys become typed properties, node ids of the form n123 or 123 keep their number, unless two ids
+// share a number, such as n5 and 5, in which case every node is numbered in order of appearance, as are edges.
+// The configured attributes provide labels and relationship types, which are made plain names as other readers
+// make them. Every edge must have a type, and every node a label unless a default label is given.
+func GetGraphFromGraphML(r io.Reader, opts GraphMLOptions) (g Graph, err error) {
+	var doc graphmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return g, err
+	}
+	if len(doc.Graphs) == 0 {
+		return g, nil
+	}
+
+	var (
+		keys     map[string]graphmlKey = make(map[string]graphmlKey, len(doc.Keys))
+		nodeIds  map[string]int64      = make(map[string]int64)
+		nextId   int64
+		graphDoc graphmlGraph = doc.Graphs[0]
+	)
+	for _, k := range doc.Keys {
+		keys[k.Id] = k
+	}
+	numbered := graphmlIdsAreDistinct(len(graphDoc.Nodes), func(i int) string { return graphDoc.Nodes[i].Id }, "n")
+	for _, n := range graphDoc.Nodes {
+		if id, ok := graphmlNumericId(n.Id, "n"); numbered && ok && id >= nextId {
+			nextId = id + 1
+		}
+	}
+
+	g.Nodes = make([]geno.Node, 0, len(graphDoc.Nodes))
+	for _, n := range graphDoc.Nodes {
+		id, ok := graphmlNumericId(n.Id, "n")
+		if _, taken := nodeIds[n.Id]; taken {
+			return g, fmt.Errorf("node id %s is used more than once", n.Id)
+		}
+		if !ok || !numbered {
+			id = nextId
+			nextId++
+		}
+		nodeIds[n.Id] = id
+
+		props, err := graphmlProperties(n.Data, keys, "node")
+		if err != nil {
+			return g, fmt.Errorf("node %s: %w", n.Id, err)
+		}
+		rawLabels := n.Labels
+		if val, found := props[opts.LabelAttribute]; found {
+			rawLabels = fmt.Sprint(val)
+			delete(props, opts.LabelAttribute)
+		}
+		labels := splitLabels(rawLabels)
+		if len(labels) == 0 && opts.DefaultLabel != "" {
+			labels = []string{plainName(opts.DefaultLabel)}
+		}
+		if len(labels) == 0 {
+			return g, fmt.Errorf("node %s has no labels in its %s attribute and no default label was given", n.Id, opts.LabelAttribute)
+		}
+		g.Nodes = append(g.Nodes, geno.NewNode(id, labels, props))
+	}
+
+	g.Relationships = make([]geno.Relationship, 0, len(graphDoc.Edges))
+	numbered = graphmlIdsAreDistinct(len(graphDoc.Edges), func(i int) string { return graphDoc.Edges[i].Id }, "e")
+	for i, e := range graphDoc.Edges {
+		start, err := findNodeById(nodeIds[e.Source], g.Nodes)
+		if _, found := nodeIds[e.Source]; !found || err != nil {
+			return g, fmt.Errorf("edge %s starts at node %s which could not be found", e.Id, e.Source)
+		}
+		end, err := findNodeById(nodeIds[e.Target], g.Nodes)
+		if _, found := nodeIds[e.Target]; !found || err != nil {
+			return g, fmt.Errorf("edge %s ends at node %s which could not be found", e.Id, e.Target)
+		}
+		props, err := graphmlProperties(e.Data, keys, "edge")
+		if err != nil {
+			return g, fmt.Errorf("edge %s: %w", e.Id, err)
+		}
+		relType := e.Label
+		if val, found := props[opts.TypeAttribute]; found {
+			relType = fmt.Sprint(val)
+			delete(props, opts.TypeAttribute)
+		}
+		relType = plainName(relType)
+		if relType == "" {
+			return g, fmt.Errorf("edge %s has no relationship type in its %s attribute", e.Id, opts.TypeAttribute)
+		}
+		id, ok := graphmlNumericId(e.Id, "e")
+		if !ok || !numbered {
+			id = int64(i)
+		}
+		g.Relationships = append(g.Relationships, geno.NewRelationship(id, start, end, relType, props))
+	}
+	return g, nil
+}
+
+// graphmlNumericId reads ids such as n12 or 12, with the prefix given
+func graphmlNumericId(id, prefix string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, prefix), 10, 64)
+	return n, err == nil
+}
+
+// graphmlIdsAreDistinct reports whether no two numeric ids among count elements share a number, so that they can
+// keep their numbers
+func graphmlIdsAreDistinct(count int, idOf func(i int) string, prefix string) bool {
+	var seen map[int64]bool = make(map[int64]bool, count)
+	for i := 0; i < count; i++ {
+		if id, ok := graphmlNumericId(idOf(i), prefix); ok {
+			if seen[id] {
+				return false
+			}
+			seen[id] = true
+		}
+	}
+	return true
+}
+
+func splitLabels(raw string) []string {
+	var labels []string = []string{}
+	for _, l := range strings.Split(raw, ":") {
+		if l = plainName(l); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// graphmlProperties types the data of an element, applying the defaults of keys the element has no data for
+func graphmlProperties(data []graphmlData, keys map[string]graphmlKey, element string) (map[string]any, error) {
+	var props map[string]any = make(map[string]any, len(data))
+	for _, k := range keys {
+		if k.Default != "" && (k.For == element || k.For == "all") && k.AttrName != "" {
+			val, err := graphmlValue(k, k.Default)
+			if err != nil {
+				return nil, err
+			}
+			props[k.AttrName] = val
+		}
+	}
+	for _, d := range data {
+		k, found := keys[d.Key]
+		if !found {
+			return nil, fmt.Errorf("data refers to key %s which was not declared", d.Key)
+		}
+		if k.AttrName == "" {
+			continue // tool specific keys, such as yEd's graphics, carry no attribute name
+		}
+		val, err := graphmlValue(k, d.Value)
+		if err != nil {
+			return nil, err
+		}
+		props[k.AttrName] = val
+	}
+	return props, nil
+}
+
+func graphmlValue(k graphmlKey, raw string) (any, error) {
+	if k.AttrList != "" {
+		var items []any
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.UseNumber() // keeps large integers exact until they are typed below
+		if err := decoder.Decode(&items); err != nil {
+			return nil, fmt.Errorf("value of %s is not a list: %w", k.AttrName, err)
+		}
+		for i, item := range items {
+			val, err := graphmlValue(graphmlKey{AttrName: k.AttrName, AttrType: k.AttrList}, fmt.Sprint(item))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = val
+		}
+		return items, nil
+	}
+	switch strings.ToLower(k.AttrType) {
+	case "boolean":
+		return strconv.ParseBool(strings.TrimSpace(raw))
+	case "int", "long":
+		return strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	case "float", "double":
+		return strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	default:
+		return raw, nil
+	}
+}
+
+// graphmlType names the GraphML type of a property value, along with the element type of lists
+func graphmlType(val any) (attrType string, listType string) {
+	switch v := val.(type) {
+	case bool:
+		return "boolean", ""
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "long", ""
+	case float32, float64:
+		return "double", ""
+	case []any:
+		listType = "string"
+		if len(v) > 0 {
+			listType, _ = graphmlType(v[0])
+		}
+		return "string", listType
+	case []string:
+		return "string", "string"
+	case []int64:
+		return "string", "long"
+	case []float64:
+		return "string", "double"
+	case []bool:
+		return "string", "boolean"
+	default:
+		return "string", ""
+	}
+}
+
+// graphmlKeys declares a key for every property of the elements, in alphabetical order.
+// Properties whose values have differing types across elements are declared as strings.
+func graphmlKeys(element, prefix string, propertySets []map[string]any) ([]graphmlKey, map[string]string) {
+	var (
+		types map[string]graphmlKey = make(map[string]graphmlKey)
+		names []string
+		keys  []graphmlKey
+		ids   map[string]string = make(map[string]string)
+	)
+	for _, props := range propertySets {
+		for name, val := range props {
+			if val == nil {
+				continue
+			}
+			attrType, listType := graphmlType(val)
+			existing, found := types[name]
+			if !found {
+				types[name] = graphmlKey{AttrName: name, AttrType: attrType, AttrList: listType}
+				names = append(names, name)
+			} else if existing.AttrType != attrType || existing.AttrList != listType {
+				types[name] = graphmlKey{AttrName: name, AttrType: "string"}
+			}
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		k := types[name]
+		k.Id = fmt.Sprintf("%s%d", prefix, i)
+		k.For = element
+		keys = append(keys, k)
+		ids[name] = k.Id
+	}
+	return keys, ids
+}
+
+func graphmlDataOf(props map[string]any, ids map[string]string, keys map[string]graphmlKey) []graphmlData {
+	var (
+		names []string
+		data  []graphmlData
+	)
+	for name, val := range props {
+		if val != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var (
+			val  any    = props[name]
+			text string = fmt.Sprint(val)
+		)
+		if keys[ids[name]].AttrList != "" {
+			raw, _ := json.Marshal(val)
+			text = string(raw)
+		} else if _, isString := val.(string); !isString && keys[ids[name]].AttrType == "string" {
+			if raw, err := json.Marshal(val); err == nil {
+				text = string(raw)
+			}
+		}
+		data = append(data, graphmlData{Key: ids[name], Value: text})
+	}
+	return data
+}
+
+// WriteGraphML writes a graph as GraphML, preserving labels and relationship types in the configured attributes
+// and declaring a typed key for every property. Properties named like either attribute are an error, as they
+// could not be told apart from labels and types when the file is read back.
+func WriteGraphML(w io.Writer, g Graph, opts GraphMLOptions) error {
+	var (
+		nodeProps []map[string]any = make([]map[string]any, len(g.Nodes))
+		relProps  []map[string]any = make([]map[string]any, len(g.Relationships))
+		keyIndex  map[string]graphmlKey
+		doc       graphmlDocument = graphmlDocument{Xmlns: graphmlNamespace}
+		graphDoc  graphmlGraph    = graphmlGraph{Id: "G", EdgeDefault: "directed"}
+	)
+	for i, n := range g.Nodes {
+		if _, found := n.Properties[opts.LabelAttribute]; found {
+			return fmt.Errorf("node %d has a property named %s, which would be read back as its labels; write labels to another attribute", n.Id, opts.LabelAttribute)
+		}
+		nodeProps[i] = exportProperties(n.Properties)
+	}
+	for i, r := range g.Relationships {
+		if _, found := r.Properties[opts.TypeAttribute]; found {
+			return fmt.Errorf("relationship %d has a property named %s, which would be read back as its type; write types to another attribute", r.Id, opts.TypeAttribute)
+		}
+		relProps[i] = exportProperties(r.Properties)
+	}
+	nodeKeys, nodeIds := graphmlKeys("node", "n", nodeProps)
+	relKeys, relIds := graphmlKeys("edge", "e", relProps)
+
+	doc.Keys = append(doc.Keys, graphmlKey{Id: "labels", For: "node", AttrName: opts.LabelAttribute, AttrType: "string"})
+	doc.Keys = append(doc.Keys, nodeKeys...)
+	doc.Keys = append(doc.Keys, graphmlKey{Id: "label", For: "edge", AttrName: opts.TypeAttribute, AttrType: "string"})
+	doc.Keys = append(doc.Keys, relKeys...)
+	keyIndex = make(map[string]graphmlKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		keyIndex[k.Id] = k
+	}
+
+	for i, n := range g.Nodes {
+		labels := ":" + strings.Join(n.Labels, ":")
+		data := append([]graphmlData{{Key: "labels", Value: labels}}, graphmlDataOf(nodeProps[i], nodeIds, keyIndex)...)
+		graphDoc.Nodes = append(graphDoc.Nodes, graphmlNode{Id: fmt.Sprintf("n%d", n.Id), Labels: labels, Data: data})
+	}
+	for i, r := range g.Relationships {
+		data := append([]graphmlData{{Key: "label", Value: r.Label}}, graphmlDataOf(relProps[i], relIds, keyIndex)...)
+		graphDoc.Edges = append(graphDoc.Edges, graphmlEdge{
+			Id:     fmt.Sprintf("e%d", r.Id),
+			Source: fmt.Sprintf("n%d", r.Start.Id),
+			Target: fmt.Sprintf("n%d", r.End.Id),
+			Label:  r.Label,
+			Data:   data,
+		})
+	}
+	doc.Graphs = []graphmlGraph{graphDoc}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
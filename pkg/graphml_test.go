@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestGetGraphFromGraphML(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="labels" for="node" attr.name="labels" attr.type="string"/>
+  <key id="name" for="node" attr.name="name" attr.type="string"/>
+  <key id="age" for="node" attr.name="age" attr.type="long"/>
+  <key id="active" for="node" attr.name="active" attr.type="boolean"><default>true</default></key>
+  <key id="tags" for="node" attr.name="tags" attr.type="string" attr.list="long"/>
+  <key id="label" for="edge" attr.name="label" attr.type="string"/>
+  <key id="weight" for="edge" attr.name="weight" attr.type="double"/>
+  <key id="d9" for="node" yfiles.type="nodegraphics"/>
+  <graph id="G" edgedefault="directed">
+    <node id="n3" labels=":Customer:Vendor"><data key="labels">:Customer:Vendor</data><data key="name">Acme</data><data key="age">12</data><data key="tags">[1, 20000000]</data></node>
+    <node id="alpha"><data key="labels">:Customer</data><data key="active">false</data><data key="d9">ignored</data></node>
+    <edge id="e7" source="n3" target="alpha"><data key="label">SUPPLIES</data><data key="weight">0.5</data></edge>
+  </graph>
+</graphml>`
+
+	var (
+		nodeA geno.Node         = geno.NewNode(3, []string{"Customer", "Vendor"}, map[string]any{"name": "Acme", "age": int64(12), "active": true, "tags": []any{int64(1), int64(20000000)}})
+		nodeB geno.Node         = geno.NewNode(4, []string{"Customer"}, map[string]any{"active": false})
+		relA  geno.Relationship = geno.NewRelationship(7, nodeA, nodeB, "SUPPLIES", map[string]any{"weight": 0.5})
+	)
+
+	got, err := GetGraphFromGraphML(strings.NewReader(doc), DefaultGraphMLOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]geno.Node{nodeA, nodeB}, got.Nodes) {
+		t.Errorf("wanted nodes of\n%v\nbut got\n%v\n", []geno.Node{nodeA, nodeB}, got.Nodes)
+	}
+	if !reflect.DeepEqual([]geno.Relationship{relA}, got.Relationships) {
+		t.Errorf("wanted rels of\n%v\nbut got\n%v\n", []geno.Relationship{relA}, got.Relationships)
+	}
+
+	if _, err := GetGraphFromGraphML(strings.NewReader(strings.Replace(doc, `target="alpha"`, `target="beta"`, 1)), DefaultGraphMLOptions); err == nil {
+		t.Error("an edge to an undeclared node should be an error")
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"Customer"}, map[string]any{"name": "Acme", "since": int64(2001), "score": 1.5, "aliases": []any{"A", "B"}})
+		nodeB geno.Node         = geno.NewNode(2, []string{"Vendor", "Customer"}, map[string]any{"name": "Widgets & Co", "active": true})
+		relA  geno.Relationship = geno.NewRelationship(5, nodeA, nodeB, "BUYS_FROM", map[string]any{"count": int64(3)})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB}, Relationships: []geno.Relationship{relA}}
+		buf   bytes.Buffer
+	)
+
+	if err := WriteGraphML(&buf, g, DefaultGraphMLOptions); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetGraphFromGraphML(&buf, DefaultGraphMLOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, got) {
+		t.Errorf("wanted a round trip to give\n%v\nbut got\n%v\n", g, got)
+	}
+}
+
+func TestGetGraphFromGraphMLIds(t *testing.T) {
+	const doc = `<graphml>
+  <key id="label" for="edge" attr.name="label" attr.type="string"/>
+  <graph edgedefault="directed">
+    <node id="n5"/>
+    <node id="5"/>
+    <edge id="e1" source="n5" target="5"><data key="label">KNOWS</data></edge>
+  </graph>
+</graphml>`
+	if _, err := GetGraphFromGraphML(strings.NewReader(doc), DefaultGraphMLOptions); err == nil {
+		t.Error("a node without labels should be an error without a default label")
+	}
+	opts := DefaultGraphMLOptions
+	opts.DefaultLabel = "My Node"
+	got, err := GetGraphFromGraphML(strings.NewReader(doc), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Nodes[0].Labels, []string{"My_Node"}) {
+		t.Errorf("wanted nodes without labels given the default label as a plain name, but got %v", got.Nodes[0].Labels)
+	}
+	if labels := splitLabels(":My Label:Vendor"); !reflect.DeepEqual(labels, []string{"My_Label", "Vendor"}) {
+		t.Errorf("wanted labels made plain names, but got %v", labels)
+	}
+	if got.Nodes[0].Id != 0 || got.Nodes[1].Id != 1 || got.Relationships[0].End.Id != 1 {
+		t.Errorf("wanted nodes whose ids share a number numbered in order, but got %v", got)
+	}
+
+	if _, err := GetGraphFromGraphML(strings.NewReader(strings.Replace(doc, `<data key="label">KNOWS</data>`, "", 1)), opts); err == nil {
+		t.Error("an edge without a type should be an error")
+	}
+
+	n := geno.NewNode(1, []string{"Customer"}, map[string]any{"labels": "VIP"})
+	if err := WriteGraphML(&bytes.Buffer{}, Graph{Nodes: []geno.Node{n}}, DefaultGraphMLOptions); err == nil {
+		t.Error("a property named like the label attribute should be an error")
+	}
+}
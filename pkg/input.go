@@ -0,0 +1,193 @@
+package pkg
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Input is a single file to read, which may have come from stdin or from within a tar archive
+type Input struct {
+	Name string // path of the file, or of the entry within its archive, without any compression extension
+	Raw  []byte // the decompressed contents
+}
+
+// Reader reads the contents of an input
+func (i Input) Reader() io.Reader {
+	return bytes.NewReader(i.Raw)
+}
+
+// compressionExtensions are removed from the names of compressed inputs, so that their format can be recognised
+var compressionExtensions []string = []string{".gz", ".bz2", ".zst"}
+
+// decompress recognises gzip, bzip2 and zstandard streams by their magic numbers and decompresses them;
+// anything else is returned untouched
+func decompress(r io.Reader) (io.Reader, func(), error) {
+	buffered := bufio.NewReader(r)
+	magic, _ := buffered.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gz, func() { gz.Close() }, nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bzip2.NewReader(buffered), func() {}, nil
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zr, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	default:
+		return buffered, func() {}, nil
+	}
+}
+
+// isTar reports whether contents start with a tar header, which is marked ustar at offset 257
+func isTar(raw []byte) bool {
+	return len(raw) > 262 && bytes.Equal(raw[257:262], []byte("ustar"))
+}
+
+// readInput decompresses a single file or stream and expands it into the regular files it holds if it is a tar archive,
+// which must hold at least one
+func readInput(name string, r io.Reader) ([]Input, error) {
+	decompressed, closer, err := decompress(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer closer()
+	raw, err := io.ReadAll(decompressed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	for _, ext := range compressionExtensions {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if !isTar(raw) {
+		return []Input{{Name: name, Raw: raw}}, nil
+	}
+
+	var (
+		archive *tar.Reader = tar.NewReader(bytes.NewReader(raw))
+		inputs  []Input
+	)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if header.Typeflag != tar.TypeReg || strings.HasPrefix(filepath.Base(header.Name), ".") {
+			continue
+		}
+		entries, err := readInput(header.Name, archive)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		inputs = append(inputs, entries...)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%s holds no files to read", name)
+	}
+	sort.SliceStable(inputs, func(i, j int) bool { return inputs[i].Name < inputs[j].Name })
+	return inputs, nil
+}
+
+// ReadInputs reads every file named by path: - for stdin, a file, every file directly within a directory,
+// or every file matching a glob such as exports/*.json.gz. Files are read in order of their names and
+// hidden files are skipped. gzip, bzip2 and zstandard files are decompressed, and tar archives are expanded into
+// the files they hold, whether or not they are compressed.
+func ReadInputs(path string, stdin io.Reader) ([]Input, error) {
+	if path == "-" {
+		return readInput("-", stdin)
+	}
+
+	var paths []string
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				paths = append(paths, filepath.Join(path, entry.Name()))
+			}
+		}
+	} else if err == nil {
+		paths = []string{path}
+	} else {
+		matches, globErr := filepath.Glob(path)
+		if globErr != nil {
+			return nil, globErr
+		}
+		if len(matches) == 0 {
+			return nil, err // the original error, as the path is neither a file nor a pattern matching any
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() && !strings.HasPrefix(filepath.Base(match), ".") {
+				paths = append(paths, match)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s holds no files to read", path)
+	}
+
+	var inputs []Input
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		read, err := readInput(p, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, read...)
+	}
+	return inputs, nil
+}
+
+// CombineGraphs joins graphs read from separate inputs into one, holding every node of every graph
+// before any relationship. Ids are renumbered so that the elements of different graphs stay distinct.
+func CombineGraphs(graphs []Graph) Graph {
+	if len(graphs) == 1 {
+		return graphs[0]
+	}
+	var combined Graph
+	for _, g := range graphs {
+		for _, n := range g.Nodes {
+			n.Id = int64(len(combined.Nodes))
+			combined.Nodes = append(combined.Nodes, n)
+		}
+	}
+	var offset int
+	for _, g := range graphs {
+		var ids map[int64]int64 = make(map[int64]int64, len(g.Nodes))
+		for i, n := range g.Nodes {
+			ids[n.Id] = int64(offset + i)
+		}
+		for _, r := range g.Relationships {
+			r.Id = int64(len(combined.Relationships))
+			r.Start.Id, r.End.Id = ids[r.Start.Id], ids[r.End.Id]
+			combined.Relationships = append(combined.Relationships, r)
+		}
+		offset += len(g.Nodes)
+	}
+	return combined
+}
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/klauspost/compress/zstd"
+)
+
+func gzipped(t *testing.T, raw string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(raw))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadInputs(t *testing.T) {
+	const (
+		nodes = `{"nodes":[{"identity":1,"labels":["Customer"],"properties":{"KUNNR":"1"}}],"rels":[]}`
+		rels  = `{"nodes":[],"rels":[{"identity":2,"start":1,"end":1,"type":"REFERS","properties":{}}]}`
+	)
+	dir := t.TempDir()
+
+	var zstdBuf bytes.Buffer
+	zw, err := zstd.NewWriter(&zstdBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Write([]byte(rels))
+	zw.Close()
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for _, entry := range []struct{ name, body string }{{"b_rels.json", rels}, {"a_nodes.json", nodes}} {
+		tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0600, Size: int64(len(entry.body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(entry.body))
+	}
+	tw.Close()
+
+	for name, raw := range map[string][]byte{
+		"1_nodes.json.gz": gzipped(t, nodes),
+		"2_rels.json.zst": zstdBuf.Bytes(),
+		".hidden.json":    []byte("ignored"),
+		"3_export.tar.gz": gzipped(t, tarBuf.String()),
+		"notes.txt":       []byte("not matched by the glob"),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), raw, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inputs, err := ReadInputs(filepath.Join(dir, "*.*.*"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, input := range inputs {
+		names = append(names, filepath.Base(input.Name))
+	}
+	if want := []string{"1_nodes.json", "2_rels.json", "a_nodes.json", "b_rels.json"}; !reflect.DeepEqual(want, names) {
+		t.Errorf("wanted inputs %v but got %v", want, names)
+	}
+	if inputs[1].Name != filepath.Join(dir, "2_rels.json") || string(inputs[1].Raw) != rels {
+		t.Errorf("the zstandard file was not decompressed: %s %q", inputs[1].Name, inputs[1].Raw)
+	}
+
+	// relationship files may refer to the nodes of node files
+	docs := [][]byte{inputs[1].Raw, inputs[0].Raw}
+	g, err := GetGraphFromJsonDocuments(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 1 || len(g.Relationships) != 1 || g.Relationships[0].Start.Properties["KUNNR"] != "1" {
+		t.Errorf("wanted one node and one relationship to it but got %v", g)
+	}
+
+	if inputs, err := ReadInputs(dir, nil); err != nil || len(inputs) != 5 {
+		t.Errorf("wanted the 5 files of the directory and its archive but got %d: %v", len(inputs), err)
+	}
+	if inputs, err := ReadInputs("-", strings.NewReader(nodes)); err != nil || len(inputs) != 1 || string(inputs[0].Raw) != nodes {
+		t.Errorf("wanted stdin to be read as it is but got %v: %v", inputs, err)
+	}
+	if _, err := ReadInputs(filepath.Join(dir, "missing.json"), nil); err == nil {
+		t.Error("a missing file should be an error")
+	}
+
+	var emptyTar bytes.Buffer
+	tw = tar.NewWriter(&emptyTar)
+	tw.WriteHeader(&tar.Header{Name: "exports/", Mode: 0700, Typeflag: tar.TypeDir})
+	tw.Close()
+	if _, err := ReadInputs("-", bytes.NewReader(emptyTar.Bytes())); err == nil {
+		t.Error("a tar archive without any files should be an error")
+	}
+}
+
+func TestCombineGraphs(t *testing.T) {
+	var (
+		a   geno.Node = geno.NewNode(0, []string{"Customer"}, map[string]any{"KUNNR": "1"})
+		b   geno.Node = geno.NewNode(1, []string{"Customer"}, map[string]any{"KUNNR": "2"})
+		c   geno.Node = geno.NewNode(0, []string{"Vendor"}, map[string]any{"LIFNR": "1"})
+		d   geno.Node = geno.NewNode(1, []string{"Vendor"}, map[string]any{"LIFNR": "2"})
+		one Graph     = Graph{Nodes: []geno.Node{a, b}, Relationships: []geno.Relationship{geno.NewRelationship(0, a, b, "REFERS", nil)}}
+		two Graph     = Graph{Nodes: []geno.Node{c, d}, Relationships: []geno.Relationship{geno.NewRelationship(0, d, c, "REFERS", nil)}}
+	)
+	got := CombineGraphs([]Graph{one, two})
+
+	c.Id, d.Id = 2, 3
+	want := Graph{
+		Nodes:         []geno.Node{a, b, c, d},
+		Relationships: []geno.Relationship{geno.NewRelationship(0, a, b, "REFERS", nil), geno.NewRelationship(1, d, c, "REFERS", nil)},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted\n%v\nbut got\n%v", want, got)
+	}
+}
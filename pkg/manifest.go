@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest lists the sources of a load. Every source is read and validated before any is written.
+//
+//	Database: PRD
+//	OnConflict: keep
+//	BatchSize: 500
+//	Sources:
+//	    - Name: customers
+//	      Path: extracts/customers_*.json.gz
+//	      Identity: sap
+//	    - Name: orders
+//	      Path: extracts/orders.csv
+//	      Mapping: mappings/orders.yaml
+//	      OnConflict: overwrite
+//	      After: [customers]
+//	    - Name: ownership
+//	      Path: extracts/ownership.json
+//	      Identity: sap
+type Manifest struct {
+	Database   string           `yaml:"Database"`   // database written by sources which do not name one
+	OnConflict string           `yaml:"OnConflict"` // conflict policy of sources which do not give one, defaults to keep
+	BatchSize  int              `yaml:"BatchSize"`  // batch size of sources which do not give one, defaults to 1000
+	Sources    []ManifestSource `yaml:"Sources"`
+}
+
+// ManifestSource is one set of files loaded by a manifest
+type ManifestSource struct {
+	Name       string   `yaml:"Name"`       // referred to by the After lists of other sources, defaults to the path
+	Path       string   `yaml:"Path"`       // file, directory or glob, relative to the manifest
+	Format     string   `yaml:"Format"`     // inferred from the extension of the path when not given
+	Mapping    string   `yaml:"Mapping"`    // mapping file of a csv, xlsx or rdf source, relative to the manifest
+	Database   string   `yaml:"Database"`   // database written to
+	After      []string `yaml:"After"`      // sources which must be loaded before this one
+	OnConflict string   `yaml:"OnConflict"` // keep, overwrite or fail the existing values of nodes and relationships
+	BatchSize  int      `yaml:"BatchSize"`  // number of nodes or relationships merged by each statement
+	Identity   string   `yaml:"Identity"`   // sources sharing an identity space may refer to each other's node ids
+}
+
+// ReadManifest reads a manifest written in yaml. Paths are resolved against dir, the directory holding the manifest,
+// and every source is given the defaults of the manifest. The sources are returned in the order they should be loaded:
+// the order they are listed in, except that a source is moved after those it must follow.
+func ReadManifest(raw []byte, dir string) (m Manifest, err error) {
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return m, err
+	}
+	if len(m.Sources) == 0 {
+		return m, errors.New("a manifest must list at least one source")
+	}
+	if m.OnConflict == "" {
+		m.OnConflict = "keep"
+	}
+	if m.BatchSize == 0 {
+		m.BatchSize = 1000
+	}
+
+	var names map[string]bool = make(map[string]bool, len(m.Sources))
+	for i := range m.Sources {
+		s := &m.Sources[i]
+		if s.Path == "" {
+			return m, fmt.Errorf("source %d does not give a path", i+1)
+		}
+		if s.Name == "" {
+			s.Name = s.Path
+		}
+		if names[s.Name] {
+			return m, fmt.Errorf("source %s is listed more than once", s.Name)
+		}
+		names[s.Name] = true
+
+		s.Path = resolvePath(dir, s.Path)
+		if s.Mapping != "" {
+			s.Mapping = resolvePath(dir, s.Mapping)
+		}
+		if s.Database == "" {
+			s.Database = m.Database
+		}
+		if s.OnConflict == "" {
+			s.OnConflict = m.OnConflict
+		}
+		switch s.OnConflict {
+		case "keep", "overwrite", "fail":
+		default:
+			return m, fmt.Errorf("source %s: conflict policy %s is not one of keep, overwrite or fail", s.Name, s.OnConflict)
+		}
+		if s.BatchSize == 0 {
+			s.BatchSize = m.BatchSize
+		}
+		if s.BatchSize < 0 {
+			return m, fmt.Errorf("source %s: the batch size must be at least 1", s.Name)
+		}
+	}
+	for _, s := range m.Sources {
+		for _, after := range s.After {
+			if !names[after] {
+				return m, fmt.Errorf("source %s follows %s, which is not a source of the manifest", s.Name, after)
+			}
+		}
+	}
+
+	m.Sources, err = orderSources(m.Sources)
+	return m, err
+}
+
+// resolvePath resolves a path of a manifest against its directory; stdin and absolute paths are left untouched
+func resolvePath(dir, path string) string {
+	if path == "-" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// orderSources sorts sources so that each follows those named by its After list, otherwise keeping the order given
+func orderSources(sources []ManifestSource) ([]ManifestSource, error) {
+	var (
+		ordered []ManifestSource
+		placed  map[string]bool = make(map[string]bool, len(sources))
+	)
+	for len(ordered) < len(sources) {
+		var progressed bool
+		for _, s := range sources {
+			if placed[s.Name] {
+				continue
+			}
+			var ready bool = true
+			for _, after := range s.After {
+				ready = ready && placed[after]
+			}
+			if ready {
+				ordered = append(ordered, s)
+				placed[s.Name] = true
+				progressed = true
+				break // restart from the top, so that sources keep their listed order wherever they can
+			}
+		}
+		if !progressed {
+			var waiting []string
+			for _, s := range sources {
+				if !placed[s.Name] {
+					waiting = append(waiting, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("sources %s follow each other in a cycle", strings.Join(waiting, ", "))
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadManifest(t *testing.T) {
+	const raw = `
+Database: PRD
+BatchSize: 500
+Sources:
+    - Name: orders
+      Path: extracts/orders.csv
+      Mapping: mappings/orders.yaml
+      OnConflict: overwrite
+      After: [customers]
+    - Name: customers
+      Path: extracts/customers.json
+      Identity: sap
+      BatchSize: 50
+    - Path: /data/ownership.json
+      Database: QAS
+      Identity: sap
+`
+	m, err := ReadManifest([]byte(raw), "loads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range m.Sources {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "customers,orders,/data/ownership.json" {
+		t.Errorf("wanted sources ordered customers, orders, /data/ownership.json, but got %s", got)
+	}
+
+	customers, orders, ownership := m.Sources[0], m.Sources[1], m.Sources[2]
+	if customers.Path != filepath.Join("loads", "extracts", "customers.json") || orders.Mapping != filepath.Join("loads", "mappings", "orders.yaml") {
+		t.Errorf("wanted paths resolved against the manifest's directory, but got %s and %s", customers.Path, orders.Mapping)
+	}
+	if ownership.Path != "/data/ownership.json" {
+		t.Errorf("wanted absolute paths untouched, but got %s", ownership.Path)
+	}
+	if customers.Database != "PRD" || ownership.Database != "QAS" {
+		t.Errorf("wanted the manifest's database unless a source names one, but got %s and %s", customers.Database, ownership.Database)
+	}
+	if customers.OnConflict != "keep" || orders.OnConflict != "overwrite" {
+		t.Errorf("wanted the keep policy unless a source gives one, but got %s and %s", customers.OnConflict, orders.OnConflict)
+	}
+	if customers.BatchSize != 50 || orders.BatchSize != 500 {
+		t.Errorf("wanted the manifest's batch size unless a source gives one, but got %d and %d", customers.BatchSize, orders.BatchSize)
+	}
+}
+
+func TestReadManifestInvalid(t *testing.T) {
+	var tests map[string]string = map[string]string{
+		"no sources":   `Database: PRD`,
+		"no path":      "Sources:\n    - Name: a",
+		"duplicate":    "Sources:\n    - Path: a.json\n    - Path: a.json",
+		"policy":       "Sources:\n    - Path: a.json\n      OnConflict: replace",
+		"batch size":   "Sources:\n    - Path: a.json\n      BatchSize: -1",
+		"unknown":      "Sources:\n    - Path: a.json\n      After: [b.json]",
+		"cycle":        "Sources:\n    - Path: a.json\n      After: [b.json]\n    - Path: b.json\n      After: [a.json]",
+		"invalid yaml": "Sources: [",
+	}
+	for name, raw := range tests {
+		if _, err := ReadManifest([]byte(raw), "."); err == nil {
+			t.Errorf("%s: wanted the manifest to be rejected", name)
+		}
+	}
+}
+
+func TestGetGraphsFromJsonDocuments(t *testing.T) {
+	const (
+		customers = `{"nodes":[{"identity":1,"labels":["Customer"],"properties":{"KUNNR":"1"}}],"rels":[]}`
+		vendors   = `{"nodes":[{"identity":2,"labels":["Vendor"],"properties":{"LIFNR":"2"}}],"rels":[]}`
+		ownership = `{"nodes":[],"rels":[{"identity":1,"start":1,"end":2,"type":"IS_VENDOR","properties":{}}]}`
+	)
+	graphs, err := GetGraphsFromJsonDocuments([][][]byte{{[]byte(customers), []byte(vendors)}, {[]byte(ownership)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graphs[0].Nodes) != 2 || len(graphs[0].Relationships) != 0 || len(graphs[1].Nodes) != 0 || len(graphs[1].Relationships) != 1 {
+		t.Fatalf("wanted each set to keep its own nodes and relationships, but got %+v", graphs)
+	}
+	if r := graphs[1].Relationships[0]; r.Start.Properties["KUNNR"] != "1" || r.End.Properties["LIFNR"] != "2" {
+		t.Errorf("wanted the relationship resolved against the nodes of the other set, but got %+v", r)
+	}
+
+	if _, err := GetGraphsFromJsonDocuments([][][]byte{{[]byte(customers)}, {[]byte(customers)}}); err == nil {
+		t.Error("wanted a node id used by two sets to be rejected")
+	}
+}
@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// mermaidText escapes text for a quoted Mermaid label, which cannot hold quotes or raw line breaks
+func mermaidText(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\r\n", "<br/>", "\n", "<br/>").Replace(s) + `"`
+}
+
+// mermaidId is the identifier of a node, which may not contain a minus sign
+func mermaidId(id int64) string {
+	if id < 0 {
+		return fmt.Sprintf("m%d", -id)
+	}
+	return fmt.Sprintf("n%d", id)
+}
+
+// WriteMermaid writes a graph as a Mermaid flowchart, which renders within markdown on GitHub, GitLab and
+// most wikis. Nodes show their caption and are coloured by their first label, and relationships are
+// labelled with their type. direction is one of TB, TD, BT, LR or RL.
+func WriteMermaid(w io.Writer, g Graph, captions Captions, direction string) error {
+	switch direction {
+	case "TB", "TD", "BT", "LR", "RL":
+	default:
+		return fmt.Errorf("direction must be one of TB, TD, BT, LR or RL, not %s", direction)
+	}
+	var (
+		out     *bufio.Writer       = bufio.NewWriter(w)
+		colors  map[string]string   = labelColors(g)
+		members map[string][]string = make(map[string][]string)
+		classes []string
+	)
+	fmt.Fprintln(out, "flowchart "+direction)
+	for _, n := range g.Nodes {
+		fmt.Fprintf(out, "    %s[%s]\n", mermaidId(n.Id), mermaidText(captions.Caption(n)))
+		if len(n.Labels) > 0 {
+			class := plainName(n.Labels[0])
+			if _, found := members[class]; !found {
+				classes = append(classes, class)
+			}
+			members[class] = append(members[class], mermaidId(n.Id))
+		}
+	}
+	for _, r := range g.Relationships {
+		fmt.Fprintf(out, "    %s -->|%s| %s\n", mermaidId(r.Start.Id), mermaidText(r.Label), mermaidId(r.End.Id))
+	}
+
+	var classColors map[string]string = make(map[string]string, len(colors))
+	for label, color := range colors {
+		classColors[plainName(label)] = color
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(out, "    classDef %s fill:%s,stroke:#333\n", class, classColors[class])
+		fmt.Fprintf(out, "    class %s %s\n", strings.Join(members[class], ","), class)
+	}
+	return out.Flush()
+}
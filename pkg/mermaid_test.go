@@ -0,0 +1,38 @@
+package pkg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"Customer"}, map[string]any{"name": "Acme \"Steel\""})
+		nodeB geno.Node         = geno.NewNode(2, []string{"Sales-Org"}, map[string]any{})
+		nodeC geno.Node         = geno.NewNode(3, []string{"Customer"}, map[string]any{"name": "Widgets"})
+		relA  geno.Relationship = geno.NewRelationship(4, nodeA, nodeB, "BELONGS_TO", map[string]any{})
+		g     Graph             = Graph{Nodes: []geno.Node{nodeA, nodeB, nodeC}, Relationships: []geno.Relationship{relA}}
+		buf   bytes.Buffer
+	)
+	if err := WriteMermaid(&buf, g, nil, "LR"); err != nil {
+		t.Fatal(err)
+	}
+	want := `flowchart LR
+    n1["Acme #quot;Steel#quot;"]
+    n2["Sales-Org 2"]
+    n3["Widgets"]
+    n1 -->|"BELONGS_TO"| n2
+    classDef Customer fill:#8dd3c7,stroke:#333
+    class n1,n3 Customer
+    classDef Sales_Org fill:#ffffb3,stroke:#333
+    class n2 Sales_Org
+`
+	if got := buf.String(); got != want {
+		t.Errorf("wanted\n%s\nbut got\n%s", want, got)
+	}
+	if err := WriteMermaid(&buf, g, nil, "sideways"); err == nil {
+		t.Error("an unknown direction should be an error")
+	}
+}
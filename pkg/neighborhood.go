@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// NeighborhoodOptions limit the neighborhood read around seed nodes
+type NeighborhoodOptions struct {
+	Hops     int      // number of relationships between a seed and the furthest node read
+	Types    []string // relationship types followed, or every type when empty
+	Labels   []string // labels of the nodes reached, or every label when empty; seeds are always included
+	MaxNodes int      // nodes read at most, including the seeds, or no limit when 0
+}
+
+// ParseSeeds reads the keys of seed nodes of a label, each written as Key=value pairs separated by commas, e.g.
+// KUNNR=0000001,BUKRS=1000, or as a single value when the label has a single key property. Keys must be properties
+// of the configured uniqueness, key or existence constraints of the label, and values are typed by its property
+// type constraints; other values are strings. Every seed must give the same properties.
+func ParseSeeds(label string, c *geno.Constraints, seeds []string) ([]map[string]any, error) {
+	if len(seeds) == 0 {
+		return nil, errors.New("at least one seed must be given")
+	}
+	var (
+		keys   []string = c.GetNodeConstraints(&geno.Node{Labels: []string{label}})
+		types  map[string]string
+		parsed []map[string]any = make([]map[string]any, 0, len(seeds))
+	)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no constraint properties are configured for :%s to look seeds up by", label)
+	}
+	sort.Strings(keys)
+	types = make(map[string]string)
+	for _, pt := range c.NodePropertyTypes {
+		if pt.Label == label {
+			for _, p := range pt.Properties {
+				types[p] = pt.PropertyType
+			}
+		}
+	}
+
+	for _, seed := range seeds {
+		var raw map[string]string = make(map[string]string)
+		if !strings.Contains(seed, "=") {
+			if len(keys) != 1 {
+				return nil, fmt.Errorf("seed %s must name its properties, as :%s is looked up by %s", seed, label, strings.Join(keys, ", "))
+			}
+			raw[keys[0]] = seed
+		} else {
+			for _, pair := range strings.Split(seed, ",") {
+				key, value, found := strings.Cut(pair, "=")
+				if !found {
+					return nil, fmt.Errorf("seed %s: %s is not a Key=value pair", seed, pair)
+				}
+				raw[strings.TrimSpace(key)] = value
+			}
+		}
+
+		var key map[string]any = make(map[string]any, len(raw))
+		for prop, value := range raw {
+			if !containsString(keys, prop) {
+				return nil, fmt.Errorf("seed %s: %s is not a constraint property of :%s, which are %s", seed, prop, label, strings.Join(keys, ", "))
+			}
+			typed, err := typeTableValue(value, strings.ToLower(types[prop]))
+			if err != nil {
+				typed = value // property types such as LIST<STRING> are not typed, and neo4j will simply find no match
+			}
+			key[prop] = typed
+		}
+		if len(parsed) > 0 && propertyNames(key) != propertyNames(parsed[0]) {
+			return nil, fmt.Errorf("seed %s gives %s, but every seed must give %s", seed, propertyNames(key), propertyNames(parsed[0]))
+		}
+		parsed = append(parsed, key)
+	}
+	return parsed, nil
+}
+
+func propertyNames(props map[string]any) string {
+	var names []string = make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// GetNeighborhood reads the nodes of a label matching the seed keys, and every node and relationship within the given
+// number of hops of them, one hop at a time. No more than MaxNodes nodes are read, seeds included: each hop reads only
+// as many new nodes as remain of the budget, those with the lowest ids, and truncated reports whether any were left out.
+func GetNeighborhood(driver *geno.Driver, database, label string, seeds []map[string]any, opts NeighborhoodOptions) (g Graph, truncated bool, err error) {
+	var seedLimit int
+	if opts.MaxNodes > 0 {
+		seedLimit = opts.MaxNodes + 1 // one more than the budget, to know whether any seed was left out
+	}
+	records, err := driver.FindNodesByKey(database, label, seeds, seedLimit)
+	if err != nil {
+		return g, false, err
+	}
+	if len(records) == 0 {
+		return g, false, fmt.Errorf("no :%s node matches any of the seeds", label)
+	}
+	if opts.MaxNodes > 0 && len(records) > opts.MaxNodes {
+		records, truncated = records[:opts.MaxNodes], true
+	}
+
+	collector := newGraphCollector()
+	collector.collect(records)
+	var frontier []int64 = append([]int64(nil), collector.nodeOrder...)
+
+	for hop := 0; hop < opts.Hops && len(frontier) > 0; hop++ {
+		var records []*neo4j.Record
+		if opts.MaxNodes > 0 {
+			records, err = driver.ExpandNodesWithin(database, frontier, collector.nodeOrder, opts.Types, opts.Labels, opts.MaxNodes-len(collector.nodeOrder))
+		} else {
+			records, err = driver.ExpandNodes(database, frontier, opts.Types, opts.Labels)
+		}
+		if err != nil {
+			return g, truncated, err
+		}
+		frontier = nil
+		for _, record := range records {
+			if left, _ := record.Get("truncated"); left == true {
+				truncated = true
+			}
+			r, _ := record.Get("r")
+			m, _ := record.Get("m")
+			rel, isRel := r.(neo4j.Relationship)
+			node, isNode := m.(neo4j.Node)
+			if !isRel || !isNode {
+				continue
+			}
+			if _, found := collector.nodes[node.Id]; !found {
+				collector.addNode(node)
+				frontier = append(frontier, node.Id)
+			}
+			collector.addRelationship(rel)
+		}
+	}
+	return collector.graph(), truncated, nil
+}
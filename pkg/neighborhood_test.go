@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestParseSeeds(t *testing.T) {
+	c := geno.Constraints{
+		NodeKeys:          []geno.Constraint{{Label: "Customer", Properties: []string{"Key"}}, {Label: "Account", Properties: []string{"BUKRS", "SAKNR"}}},
+		NodePropertyTypes: []geno.Constraint{{Label: "Account", Properties: []string{"BUKRS"}, PropertyType: "INTEGER"}},
+	}
+
+	got, err := ParseSeeds("Customer", &c, []string{"Hybrid_Germany0249697900", "Key=Hybrid_France01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{{"Key": "Hybrid_Germany0249697900"}, {"Key": "Hybrid_France01"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted %v, but got %v", want, got)
+	}
+
+	got, err = ParseSeeds("Account", &c, []string{"BUKRS=1000,SAKNR=0000140000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []map[string]any{{"BUKRS": int64(1000), "SAKNR": "0000140000"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted values typed by property type constraints, %v, but got %v", want, got)
+	}
+
+	var invalid map[string][]string = map[string][]string{
+		"unnamed value with several keys": {"1000"},
+		"unconstrained property":          {"NAME1=ACME"},
+		"differing properties":            {"BUKRS=1000,SAKNR=1", "SAKNR=2"},
+		"no seeds":                        nil,
+	}
+	for name, seeds := range invalid {
+		if _, err := ParseSeeds("Account", &c, seeds); err == nil {
+			t.Errorf("%s: wanted the seeds to be rejected", name)
+		}
+	}
+	if _, err := ParseSeeds("Phone", &c, []string{"1"}); err == nil {
+		t.Error("wanted seeds of a label without constraints to be rejected")
+	}
+}
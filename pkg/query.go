@@ -0,0 +1,269 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+// ParseQueryParams reads query parameters written as name=value, or name:type=value. Typed values are read as
+// string, integer, float, boolean, date, datetime (RFC 3339) or json. Untyped values are read as json when they
+// are valid json, such as 42, true, null, ["a","b"] or {"k":1}, and as strings otherwise, so that keys such as
+// 0000001 stay strings.
+func ParseQueryParams(raw []string) (map[string]any, error) {
+	var params map[string]any = make(map[string]any, len(raw))
+	for _, param := range raw {
+		name, value, found := strings.Cut(param, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("parameter %s is not written as name=value", param)
+		}
+		name, t, typed := strings.Cut(name, ":")
+		if _, duplicate := params[name]; duplicate {
+			return nil, fmt.Errorf("parameter %s is given more than once", name)
+		}
+
+		var (
+			parsed any
+			err    error
+		)
+		switch {
+		case !typed:
+			if parsed, err = jsonValue(value); err != nil {
+				parsed, err = value, nil
+			}
+		case strings.ToLower(t) == "json":
+			parsed, err = jsonValue(value)
+		case strings.ToLower(t) == "datetime":
+			parsed, err = time.Parse(time.RFC3339Nano, value)
+		default:
+			parsed, err = typeTableValue(value, t)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		params[name] = parsed
+	}
+	return params, nil
+}
+
+// jsonValue reads a json value, with whole numbers as integers as neo4j expects
+func jsonValue(raw string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%s holds more than one json value", raw)
+	}
+	return jsonNumbers(value), nil
+}
+
+func jsonNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = jsonNumbers(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = jsonNumbers(v[key])
+		}
+	}
+	return value
+}
+
+// resultValue converts a value returned by a query into one written as json: nodes, relationships and paths become
+// maps and temporal values text
+func resultValue(value any) any {
+	switch v := value.(type) {
+	case dbtype.Node:
+		return map[string]any{"id": v.Id, "labels": v.Labels, "properties": resultValue(v.Props)}
+	case dbtype.Relationship:
+		return map[string]any{"id": v.Id, "type": v.Type, "start": v.StartId, "end": v.EndId, "properties": resultValue(v.Props)}
+	case dbtype.Path:
+		var (
+			nodes []any = make([]any, len(v.Nodes))
+			rels  []any = make([]any, len(v.Relationships))
+		)
+		for i, n := range v.Nodes {
+			nodes[i] = resultValue(n)
+		}
+		for i, r := range v.Relationships {
+			rels[i] = resultValue(r)
+		}
+		return map[string]any{"nodes": nodes, "relationships": rels}
+	case []any:
+		var items []any = make([]any, len(v))
+		for i, item := range v {
+			items[i] = resultValue(item)
+		}
+		return items
+	case map[string]any:
+		var converted map[string]any = make(map[string]any, len(v))
+		for key, item := range v {
+			converted[key] = resultValue(item)
+		}
+		return converted
+	default:
+		return exportValue(value)
+	}
+}
+
+// resultText writes a value returned by a query as a single line of text: nodes as (:Label {...}), relationships
+// as [:TYPE {...}], paths as their nodes and relationships in turn, and lists and maps as json
+func resultText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.ReplaceAll(strings.ReplaceAll(v, "\r", `\r`), "\n", `\n`)
+	case dbtype.Node:
+		var labels string
+		for _, label := range v.Labels {
+			labels += ":" + label
+		}
+		return "(" + labels + propsText(v.Props) + ")"
+	case dbtype.Relationship:
+		return "[:" + v.Type + propsText(v.Props) + "]"
+	case dbtype.Path:
+		var text string
+		for i, n := range v.Nodes {
+			if i > 0 && i-1 < len(v.Relationships) {
+				r := v.Relationships[i-1]
+				if r.StartId == n.Id {
+					text += "<-" + resultText(r) + "-"
+				} else {
+					text += "-" + resultText(r) + "->"
+				}
+			}
+			text += resultText(n)
+		}
+		return text
+	case []any, map[string]any:
+		raw, err := json.Marshal(resultValue(v))
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(raw)
+	default:
+		return cellText(v)
+	}
+}
+
+func propsText(props map[string]any) string {
+	if len(props) == 0 {
+		return ""
+	}
+	return " " + resultText(props)
+}
+
+// WriteResultTable writes the records of a query as a table aligned for reading, under a header of its keys
+func WriteResultTable(w io.Writer, keys []string, records []*db.Record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var rules []string = make([]string, len(keys))
+	for i, key := range keys {
+		rules[i] = strings.Repeat("-", len(key))
+	}
+	fmt.Fprintln(tw, strings.Join(keys, "\t"))
+	fmt.Fprintln(tw, strings.Join(rules, "\t"))
+	for _, record := range records {
+		var cells []string = make([]string, len(record.Values))
+		for i, value := range record.Values {
+			cells[i] = strings.ReplaceAll(resultText(value), "\t", " ")
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteResultCsv writes the records of a query as a delimited table with a header row of its keys
+func WriteResultCsv(w io.Writer, keys []string, records []*db.Record) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(keys); err != nil {
+		return err
+	}
+	for _, record := range records {
+		var cells []string = make([]string, len(record.Values))
+		for i, value := range record.Values {
+			cells[i] = resultText(value)
+		}
+		if err := out.Write(cells); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// resultObject writes a record as a json object holding its values under its keys, in order
+func resultObject(keys []string, values []any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		var value any
+		if i < len(values) {
+			value = values[i]
+		}
+		raw, err := json.Marshal(resultValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		buf.Write(raw)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// WriteResultJson writes the records of a query as a json array of objects keyed by its keys
+func WriteResultJson(w io.Writer, keys []string, records []*db.Record) error {
+	var objects []json.RawMessage = make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		object, err := resultObject(keys, record.Values)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+	raw, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(raw))
+	return err
+}
+
+// WriteResultNdjson writes the records of a query as json lines, one object keyed by its keys per record
+func WriteResultNdjson(w io.Writer, keys []string, records []*db.Record) error {
+	for _, record := range records {
+		object, err := resultObject(keys, record.Values)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, string(object)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+func TestParseQueryParams(t *testing.T) {
+	params, err := ParseQueryParams([]string{
+		"kunnr=0000001",
+		"limit=10",
+		"rate=0.5",
+		"active=true",
+		"banks=[\"10020030\", 1]",
+		"code:string=42",
+		"since:date=2024-01-31",
+		"at:datetime=2024-01-31T08:00:00Z",
+		"empty=",
+		"expr=a=b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"kunnr":  "0000001",
+		"limit":  int64(10),
+		"rate":   0.5,
+		"active": true,
+		"banks":  []any{"10020030", int64(1)},
+		"code":   "42",
+		"since":  dbtype.Date(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)),
+		"at":     time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC),
+		"empty":  "",
+		"expr":   "a=b",
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("wanted\n%v\nbut got\n%v", want, params)
+	}
+
+	for _, bad := range [][]string{{"kunnr"}, {"=1"}, {"n:integer=x"}, {"n:json=[1"}, {"n=1", "n=2"}} {
+		if _, err := ParseQueryParams(bad); err == nil {
+			t.Errorf("wanted %v to be rejected", bad)
+		}
+	}
+}
+
+func testResults() ([]string, []*db.Record) {
+	customer := dbtype.Node{Id: 1, Labels: []string{"Customer"}, Props: map[string]any{"KUNNR": "0000001"}}
+	phone := dbtype.Node{Id: 2, Labels: []string{"Phone"}, Props: map[string]any{"Phone": "030 1"}}
+	rel := dbtype.Relationship{Id: 3, StartId: 1, EndId: 2, Type: "HAS_PHONE", Props: map[string]any{}}
+	keys := []string{"name", "path", "tags"}
+	return keys, []*db.Record{
+		{Keys: keys, Values: []any{"ACME\nBerlin", dbtype.Path{Nodes: []dbtype.Node{phone, customer}, Relationships: []dbtype.Relationship{rel}}, []any{"a", int64(1)}}},
+		{Keys: keys, Values: []any{nil, customer, nil}},
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	keys, records := testResults()
+
+	type test struct {
+		name  string
+		write func(*bytes.Buffer) error
+		want  string
+	}
+	var tests []test = []test{
+		{
+			name:  "table",
+			write: func(b *bytes.Buffer) error { return WriteResultTable(b, keys, records) },
+			want: "name          path                                                                      tags\n" +
+				"----          ----                                                                      ----\n" +
+				"ACME\\nBerlin  (:Phone {\"Phone\":\"030 1\"})<-[:HAS_PHONE]-(:Customer {\"KUNNR\":\"0000001\"})  [\"a\",1]\n" +
+				"              (:Customer {\"KUNNR\":\"0000001\"})                                           \n",
+		},
+		{
+			name:  "csv",
+			write: func(b *bytes.Buffer) error { return WriteResultCsv(b, keys, records[1:]) },
+			want:  "name,path,tags\n,\"(:Customer {\"\"KUNNR\"\":\"\"0000001\"\"})\",\n",
+		},
+		{
+			name:  "ndjson",
+			write: func(b *bytes.Buffer) error { return WriteResultNdjson(b, keys, records[1:]) },
+			want:  `{"name":null,"path":{"id":1,"labels":["Customer"],"properties":{"KUNNR":"0000001"}},"tags":null}` + "\n",
+		},
+		{
+			name: "json",
+			write: func(b *bytes.Buffer) error {
+				return WriteResultJson(b, []string{"name"}, []*db.Record{{Keys: []string{"name"}, Values: []any{"ACME"}}})
+			},
+			want: "[\n  {\n    \"name\": \"ACME\"\n  }\n]\n",
+		},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		if err := tc.write(&buf); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if buf.String() != tc.want {
+			t.Errorf("%s: wanted\n%q\nbut got\n%q", tc.name, tc.want, buf.String())
+		}
+	}
+}
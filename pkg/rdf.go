@@ -0,0 +1,782 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	rdfNamespace string = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xsdNamespace string = "http://www.w3.org/2001/XMLSchema#"
+	rdfType      string = rdfNamespace + "type"
+)
+
+type rdfTermKind int
+
+const (
+	rdfIRI rdfTermKind = iota
+	rdfBlank
+	rdfLiteral
+	rdfCollection
+)
+
+// rdfTerm is a subject, predicate or object of a triple
+type rdfTerm struct {
+	kind     rdfTermKind
+	value    string // the IRI, blank node label or lexical form of a literal
+	datatype string // full IRI of a literal's datatype
+	lang     string
+	items    []rdfTerm // members of a collection
+}
+
+type rdfTriple struct {
+	subject, predicate, object rdfTerm
+}
+
+// RDFMapping configures how resources become nodes. Every resource becomes a node with the default label,
+// along with a label for each of its rdf:type classes. Literal objects become properties and resource
+// objects become relationships, both named after their predicate.
+type RDFMapping struct {
+	KeyProperty  string            `yaml:"KeyProperty"`  // property holding each resource's IRI, defaults to uri
+	DefaultLabel string            `yaml:"DefaultLabel"` // label of every resource, defaults to Resource
+	Names        string            `yaml:"Names"`        // prefixed (default), e.g. foaf__name, or local, e.g. name
+	Prefixes     map[string]string `yaml:"Prefixes"`     // namespaces to compact names with, along with those declared in the input
+}
+
+// DefaultRDFMapping stores IRIs in uri and labels every node Resource, as neosemantics does
+var DefaultRDFMapping RDFMapping = RDFMapping{KeyProperty: "uri", DefaultLabel: "Resource", Names: "prefixed"}
+
+// Key is the uniqueness constraint resources are merged on when imported, so that importing the same triples again
+// finds the nodes imported before by their IRI
+func (m RDFMapping) Key() geno.Constraint {
+	return geno.Constraint{Label: m.DefaultLabel, Properties: []string{m.KeyProperty}}
+}
+
+// ReadRDFMapping reads a mapping file written in yaml, using the default for anything it leaves out
+func ReadRDFMapping(raw []byte) (RDFMapping, error) {
+	var m RDFMapping
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return m, err
+	}
+	if m.KeyProperty == "" {
+		m.KeyProperty = DefaultRDFMapping.KeyProperty
+	}
+	if m.DefaultLabel == "" {
+		m.DefaultLabel = DefaultRDFMapping.DefaultLabel
+	}
+	switch m.Names {
+	case "":
+		m.Names = DefaultRDFMapping.Names
+	case "prefixed", "local":
+	default:
+		return m, fmt.Errorf("names must be prefixed or local, not %s", m.Names)
+	}
+	return m, nil
+}
+
+// rdfParser reads turtle, of which N-Triples is a subset
+type rdfParser struct {
+	src      []rune
+	pos      int
+	line     int
+	base     *url.URL
+	prefixes map[string]string
+	scope    string // identifies the document, as blank node labels are only unique within it
+	blanks   int
+	triples  []rdfTriple
+}
+
+func (p *rdfParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *rdfParser) peek() rune {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *rdfParser) next() rune {
+	c := p.peek()
+	if c == '\n' {
+		p.line++
+	}
+	p.pos++
+	return c
+}
+
+// skip passes over whitespace and comments
+func (p *rdfParser) skip() {
+	for p.pos < len(p.src) {
+		c := p.peek()
+		if c == '#' {
+			for p.pos < len(p.src) && p.peek() != '\n' {
+				p.next()
+			}
+		} else if unicode.IsSpace(c) {
+			p.next()
+		} else {
+			return
+		}
+	}
+}
+
+func (p *rdfParser) expect(c rune) error {
+	p.skip()
+	if p.peek() != c {
+		return p.errorf("expected %q but found %q", c, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// keyword reports, case insensitively, whether a bare word comes next, consuming it if so
+func (p *rdfParser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.src) || !strings.EqualFold(string(p.src[p.pos:end]), word) {
+		return false
+	}
+	if end < len(p.src) && isNameChar(p.src[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *rdfParser) parse() error {
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil
+		}
+		switch {
+		case p.keyword("@prefix"):
+			if err := p.prefixDirective(); err != nil {
+				return err
+			}
+			if err := p.expect('.'); err != nil {
+				return err
+			}
+		case p.keyword("@base"):
+			if err := p.baseDirective(); err != nil {
+				return err
+			}
+			if err := p.expect('.'); err != nil {
+				return err
+			}
+		case p.keyword("PREFIX"):
+			if err := p.prefixDirective(); err != nil {
+				return err
+			}
+		case p.keyword("BASE"):
+			if err := p.baseDirective(); err != nil {
+				return err
+			}
+		default:
+			if err := p.triplesStatement(); err != nil {
+				return err
+			}
+			if err := p.expect('.'); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *rdfParser) prefixDirective() error {
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.src) && p.peek() != ':' && !unicode.IsSpace(p.peek()) {
+		p.next()
+	}
+	if p.peek() != ':' {
+		return p.errorf("prefix declaration is missing its colon")
+	}
+	prefix := string(p.src[start:p.pos])
+	p.next()
+	p.skip()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.prefixes[prefix] = iri
+	return nil
+}
+
+func (p *rdfParser) baseDirective() error {
+	p.skip()
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+	p.base, err = url.Parse(iri)
+	return err
+}
+
+// iriRef reads an IRI within angle brackets, resolving it against the base
+func (p *rdfParser) iriRef() (string, error) {
+	if p.peek() != '<' {
+		return "", p.errorf("expected an IRI but found %q", p.peek())
+	}
+	p.next()
+	var iri strings.Builder
+	for {
+		c := p.next()
+		switch c {
+		case 0:
+			return "", p.errorf("IRI is never closed")
+		case '>':
+			return p.resolve(iri.String())
+		case '\\':
+			r, err := p.unicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			iri.WriteRune(r)
+		default:
+			iri.WriteRune(c)
+		}
+	}
+}
+
+func (p *rdfParser) resolve(iri string) (string, error) {
+	if p.base == nil {
+		return iri, nil
+	}
+	ref, err := url.Parse(iri)
+	if err != nil {
+		return "", p.errorf("IRI %s is invalid: %v", iri, err)
+	}
+	return p.base.ResolveReference(ref).String(), nil
+}
+
+func (p *rdfParser) unicodeEscape() (rune, error) {
+	var size int
+	switch p.next() {
+	case 'u':
+		size = 4
+	case 'U':
+		size = 8
+	default:
+		return 0, p.errorf("invalid escape sequence")
+	}
+	if p.pos+size > len(p.src) {
+		return 0, p.errorf("escape sequence is cut short")
+	}
+	code, err := strconv.ParseUint(string(p.src[p.pos:p.pos+size]), 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid escape sequence")
+	}
+	p.pos += size
+	return rune(code), nil
+}
+
+func isNameChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' || c == ':' || c == '%' || c == '\\'
+}
+
+// prefixedName reads a name such as foaf:name and expands it
+func (p *rdfParser) prefixedName() (string, error) {
+	var (
+		name     strings.Builder
+		trailing int // unescaped dots at the end of the name
+	)
+	for p.pos < len(p.src) && isNameChar(p.peek()) {
+		c := p.next()
+		if c == '\\' { // reserved characters may be escaped within local names
+			name.WriteRune(p.next())
+			trailing = 0
+			continue
+		}
+		if c == '.' {
+			trailing++
+		} else {
+			trailing = 0
+		}
+		name.WriteRune(c)
+	}
+	// a name may not end with a dot, which ends the statement instead
+	raw := name.String()
+	raw = raw[:len(raw)-trailing]
+	p.pos -= trailing
+	prefix, local, found := strings.Cut(raw, ":")
+	if !found {
+		return "", p.errorf("%q is neither an IRI nor a prefixed name", raw)
+	}
+	namespace, known := p.prefixes[prefix]
+	if !known {
+		return "", p.errorf("prefix %s: is not declared", prefix)
+	}
+	return namespace + local, nil
+}
+
+func (p *rdfParser) blankLabel() rdfTerm {
+	p.pos += 2 // _:
+	start := p.pos
+	for p.pos < len(p.src) && isNameChar(p.peek()) && p.peek() != ':' {
+		p.next()
+	}
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+	return rdfTerm{kind: rdfBlank, value: "_:" + p.scope + "-" + string(p.src[start:p.pos])}
+}
+
+func (p *rdfParser) newBlank() rdfTerm {
+	p.blanks++
+	return rdfTerm{kind: rdfBlank, value: fmt.Sprintf("_:%s-genid%d", p.scope, p.blanks)}
+}
+
+func (p *rdfParser) triplesStatement() error {
+	p.skip()
+	if p.peek() == '[' {
+		subject, err := p.blankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		p.skip()
+		if p.peek() == '.' {
+			return nil
+		}
+		return p.predicateObjectList(subject)
+	}
+	subject, err := p.term(false)
+	if err != nil {
+		return err
+	}
+	if subject.kind == rdfLiteral {
+		return p.errorf("a literal cannot be a subject")
+	}
+	return p.predicateObjectList(subject)
+}
+
+func (p *rdfParser) predicateObjectList(subject rdfTerm) error {
+	for {
+		p.skip()
+		var predicate rdfTerm
+		if p.peek() == 'a' && p.pos+1 < len(p.src) && (unicode.IsSpace(p.src[p.pos+1]) || p.src[p.pos+1] == '<' || p.src[p.pos+1] == '[') {
+			p.next()
+			predicate = rdfTerm{kind: rdfIRI, value: rdfType}
+		} else {
+			var err error
+			if predicate, err = p.term(false); err != nil {
+				return err
+			}
+			if predicate.kind != rdfIRI {
+				return p.errorf("a predicate must be an IRI")
+			}
+		}
+		for {
+			object, err := p.term(true)
+			if err != nil {
+				return err
+			}
+			p.triples = append(p.triples, rdfTriple{subject: subject, predicate: predicate, object: object})
+			p.skip()
+			if p.peek() != ',' {
+				break
+			}
+			p.next()
+		}
+		p.skip()
+		if p.peek() != ';' {
+			return nil
+		}
+		for p.peek() == ';' { // repeated semicolons are allowed
+			p.next()
+			p.skip()
+		}
+		if c := p.peek(); c == '.' || c == ']' {
+			return nil
+		}
+	}
+}
+
+func (p *rdfParser) blankNodePropertyList() (rdfTerm, error) {
+	p.next() // [
+	subject := p.newBlank()
+	p.skip()
+	if p.peek() == ']' {
+		p.next()
+		return subject, nil
+	}
+	if err := p.predicateObjectList(subject); err != nil {
+		return subject, err
+	}
+	return subject, p.expect(']')
+}
+
+// term reads an IRI, prefixed name, blank node or collection, and also literals when they are allowed
+func (p *rdfParser) term(literals bool) (rdfTerm, error) {
+	p.skip()
+	c := p.peek()
+	switch {
+	case c == '<':
+		iri, err := p.iriRef()
+		return rdfTerm{kind: rdfIRI, value: iri}, err
+	case c == '_' && p.pos+1 < len(p.src) && p.src[p.pos+1] == ':':
+		return p.blankLabel(), nil
+	case c == '[':
+		if !literals {
+			return rdfTerm{}, p.errorf("a blank node property list may only be a subject or object")
+		}
+		return p.blankNodePropertyList()
+	case c == '(':
+		p.next()
+		var collection rdfTerm = rdfTerm{kind: rdfCollection}
+		for {
+			p.skip()
+			if p.peek() == ')' {
+				p.next()
+				return collection, nil
+			}
+			item, err := p.term(true)
+			if err != nil {
+				return collection, err
+			}
+			collection.items = append(collection.items, item)
+		}
+	case literals && (c == '"' || c == '\''):
+		return p.stringLiteral()
+	case literals && (c == '+' || c == '-' || c == '.' || unicode.IsDigit(c)):
+		return p.numericLiteral()
+	case literals && p.keyword("true"):
+		return rdfTerm{kind: rdfLiteral, value: "true", datatype: xsdNamespace + "boolean"}, nil
+	case literals && p.keyword("false"):
+		return rdfTerm{kind: rdfLiteral, value: "false", datatype: xsdNamespace + "boolean"}, nil
+	case c == 0:
+		return rdfTerm{}, p.errorf("unexpected end of input")
+	default:
+		iri, err := p.prefixedName()
+		return rdfTerm{kind: rdfIRI, value: iri}, err
+	}
+}
+
+func (p *rdfParser) stringLiteral() (rdfTerm, error) {
+	var (
+		quote rune = p.next()
+		long  bool
+		value strings.Builder
+	)
+	if p.pos+1 < len(p.src) && p.src[p.pos] == quote && p.src[p.pos+1] == quote {
+		p.pos += 2
+		long = true
+	}
+	for {
+		c := p.next()
+		switch {
+		case c == 0:
+			return rdfTerm{}, p.errorf("string is never closed")
+		case c == quote && !long:
+			return p.literalSuffix(value.String())
+		case c == quote && p.pos+1 < len(p.src) && p.src[p.pos] == quote && p.src[p.pos+1] == quote:
+			p.pos += 2
+			return p.literalSuffix(value.String())
+		case c == '\\':
+			switch e := p.peek(); e {
+			case 't', 'b', 'n', 'r', 'f', '"', '\'', '\\':
+				p.next()
+				value.WriteRune(map[rune]rune{'t': '\t', 'b': '\b', 'n': '\n', 'r': '\r', 'f': '\f', '"': '"', '\'': '\'', '\\': '\\'}[e])
+			default:
+				r, err := p.unicodeEscape()
+				if err != nil {
+					return rdfTerm{}, err
+				}
+				value.WriteRune(r)
+			}
+		case c == '\n' && !long:
+			return rdfTerm{}, p.errorf("string is never closed")
+		default:
+			value.WriteRune(c)
+		}
+	}
+}
+
+// literalSuffix reads the language tag or datatype following a string
+func (p *rdfParser) literalSuffix(value string) (rdfTerm, error) {
+	var literal rdfTerm = rdfTerm{kind: rdfLiteral, value: value, datatype: xsdNamespace + "string"}
+	switch {
+	case p.peek() == '@':
+		p.next()
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsLetter(p.peek()) || unicode.IsDigit(p.peek()) || p.peek() == '-') {
+			p.next()
+		}
+		literal.lang = string(p.src[start:p.pos])
+		literal.datatype = rdfNamespace + "langString"
+	case p.pos+1 < len(p.src) && p.src[p.pos] == '^' && p.src[p.pos+1] == '^':
+		p.pos += 2
+		datatype, err := p.term(false)
+		if err != nil {
+			return literal, err
+		}
+		literal.datatype = datatype.value
+	}
+	return literal, nil
+}
+
+func (p *rdfParser) numericLiteral() (rdfTerm, error) {
+	var (
+		start    int    = p.pos
+		datatype string = "integer"
+	)
+	if c := p.peek(); c == '+' || c == '-' {
+		p.next()
+	}
+	for unicode.IsDigit(p.peek()) {
+		p.next()
+	}
+	if p.peek() == '.' && p.pos+1 < len(p.src) && unicode.IsDigit(p.src[p.pos+1]) {
+		datatype = "decimal"
+		p.next()
+		for unicode.IsDigit(p.peek()) {
+			p.next()
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		datatype = "double"
+		p.next()
+		if c := p.peek(); c == '+' || c == '-' {
+			p.next()
+		}
+		for unicode.IsDigit(p.peek()) {
+			p.next()
+		}
+	}
+	raw := string(p.src[start:p.pos])
+	if raw == "" || raw == "+" || raw == "-" || raw == "." {
+		return rdfTerm{}, p.errorf("%q is not a number", raw)
+	}
+	return rdfTerm{kind: rdfLiteral, value: raw, datatype: xsdNamespace + datatype}, nil
+}
+
+// rdfValue types a literal according to its XSD datatype; unknown datatypes and language strings remain strings
+func rdfValue(t rdfTerm) (any, error) {
+	local := strings.TrimPrefix(t.datatype, xsdNamespace)
+	if local == t.datatype {
+		return t.value, nil
+	}
+	raw := strings.TrimSpace(t.value)
+	switch local {
+	case "integer", "int", "long", "short", "byte", "nonNegativeInteger", "positiveInteger", "negativeInteger",
+		"nonPositiveInteger", "unsignedInt", "unsignedLong", "unsignedShort", "unsignedByte":
+		return strconv.ParseInt(raw, 10, 64)
+	case "decimal", "double", "float":
+		switch raw {
+		case "INF":
+			raw = "+Inf"
+		case "-INF":
+			raw = "-Inf"
+		}
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		return strconv.ParseBool(raw)
+	case "date":
+		d, err := time.Parse("2006-01-02", raw)
+		return dbtype.Date(d), err
+	case "dateTime", "dateTimeStamp":
+		if d, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return d, nil
+		}
+		d, err := time.Parse("2006-01-02T15:04:05.999999999", raw)
+		return dbtype.LocalDateTime(d), err
+	default:
+		return t.value, nil
+	}
+}
+
+// rdfNamer compacts IRIs into names usable as labels, relationship types and property keys
+type rdfNamer struct {
+	mapping    RDFMapping
+	namespaces map[string]string // namespace to prefix
+}
+
+func newRDFNamer(mapping RDFMapping, declared map[string]string) *rdfNamer {
+	var n *rdfNamer = &rdfNamer{mapping: mapping, namespaces: make(map[string]string)}
+	for _, prefixes := range []map[string]string{declared, mapping.Prefixes} {
+		for prefix, namespace := range prefixes {
+			n.namespaces[namespace] = prefix
+		}
+	}
+	return n
+}
+
+// name splits an IRI at its longest known namespace, or at its last # or /, and joins the prefix and local name
+// as neosemantics does, e.g. foaf__name. Namespaces without a prefix are given one derived from their IRI, such as
+// ns1a2b3c4d, so that their names are the same whichever file or order they are read in. Characters which cypher would need quoted are replaced with underscores.
+func (n *rdfNamer) name(iri string) string {
+	var namespace, local string
+	for ns := range n.namespaces {
+		if strings.HasPrefix(iri, ns) && len(ns) > len(namespace) {
+			namespace = ns
+		}
+	}
+	if namespace == "" {
+		cut := strings.LastIndexAny(iri, "#/")
+		namespace = iri[:cut+1]
+		n.namespaces[namespace] = fmt.Sprintf("ns%x", sha256.Sum256([]byte(namespace)))[:10]
+	}
+	local = iri[len(namespace):]
+	if n.mapping.Names != "local" && n.namespaces[namespace] != "" {
+		local = n.namespaces[namespace] + "__" + local
+	}
+	return plainName(local)
+}
+
+// GetGraphFromRDF reads N-Triples or Turtle. Every IRI or blank node which is a subject or the object of a
+// predicate other than rdf:type becomes a node keyed by its IRI, labelled with the default label and
+// its rdf:type classes. Literal objects become properties typed by their XSD datatype, with repeated
+// predicates becoming lists, and other objects become relationships.
+func GetGraphFromRDF(r io.Reader, mapping RDFMapping) (g Graph, err error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return g, err
+	}
+	return GetGraphFromRDFDocuments([][]byte{raw}, mapping)
+}
+
+// GetGraphFromRDFDocuments reads several N-Triples or Turtle documents as one graph, as GetGraphFromRDF does, so that
+// resources of every document are merged on their IRI. Blank nodes are only the same node within a document, so they
+// are keyed by their label prefixed with a hash of the document's content, such as _:3f2a9c01d4e5b6a7-b0, which
+// keeps blank nodes of different documents apart while importing the same document again finds them.
+func GetGraphFromRDFDocuments(docs [][]byte, mapping RDFMapping) (g Graph, err error) {
+	var (
+		parsers  []*rdfParser
+		declared map[string]string = make(map[string]string)
+	)
+	for i, raw := range docs {
+		sum := sha256.Sum256(raw)
+		p := &rdfParser{src: []rune(string(raw)), line: 1, scope: hex.EncodeToString(sum[:8]),
+			prefixes: map[string]string{"rdf": rdfNamespace, "xsd": xsdNamespace}}
+		if err := p.parse(); err != nil {
+			if len(docs) > 1 {
+				return g, fmt.Errorf("document %d: %w", i+1, err)
+			}
+			return g, err
+		}
+		for prefix, namespace := range p.prefixes {
+			declared[prefix] = namespace
+		}
+		parsers = append(parsers, p)
+	}
+	if mapping.KeyProperty == "" {
+		return g, errors.New("a key property must be configured to hold the IRI of each resource")
+	}
+
+	var (
+		namer   *rdfNamer        = newRDFNamer(mapping, declared)
+		index   map[string]int   = make(map[string]int)
+		labels  map[int][]string = make(map[int][]string)
+		rels    [][3]int         // start node, end node and triple
+		resolve func(t rdfTerm) int
+	)
+	resolve = func(t rdfTerm) int {
+		if i, found := index[t.value]; found {
+			return i
+		}
+		i := len(g.Nodes)
+		index[t.value] = i
+		g.Nodes = append(g.Nodes, geno.NewNode(int64(i), nil, map[string]any{mapping.KeyProperty: t.value}))
+		if mapping.DefaultLabel != "" {
+			labels[i] = append(labels[i], mapping.DefaultLabel)
+		}
+		return i
+	}
+
+	var triples []rdfTriple
+	for _, p := range parsers {
+		triples = append(triples, p.triples...)
+	}
+	for ti, t := range triples {
+		subject := resolve(t.subject)
+		switch {
+		case t.predicate.value == rdfType && t.object.kind == rdfIRI:
+			label := namer.name(t.object.value)
+			if !containsString(labels[subject], label) {
+				labels[subject] = append(labels[subject], label)
+			}
+		case t.object.kind == rdfLiteral || (t.object.kind == rdfCollection && allLiterals(t.object.items)):
+			var value any
+			if t.object.kind == rdfLiteral {
+				if value, err = rdfValue(t.object); err != nil {
+					return g, fmt.Errorf("%s %s: %w", t.subject.value, t.predicate.value, err)
+				}
+			} else {
+				var items []any = make([]any, len(t.object.items))
+				for i, item := range t.object.items {
+					if items[i], err = rdfValue(item); err != nil {
+						return g, fmt.Errorf("%s %s: %w", t.subject.value, t.predicate.value, err)
+					}
+				}
+				value = items
+			}
+			addRDFProperty(g.Nodes[subject].Properties, namer.name(t.predicate.value), value)
+		case t.object.kind == rdfCollection:
+			for _, item := range t.object.items {
+				if item.kind == rdfLiteral {
+					return g, fmt.Errorf("%s %s: a collection may not mix literals and resources", t.subject.value, t.predicate.value)
+				}
+				rels = append(rels, [3]int{subject, resolve(item), ti})
+			}
+		default:
+			rels = append(rels, [3]int{subject, resolve(t.object), ti})
+		}
+	}
+
+	for i := range g.Nodes {
+		g.Nodes[i].Labels = labels[i]
+		if g.Nodes[i].Labels == nil {
+			g.Nodes[i].Labels = []string{}
+		}
+	}
+	for i, rel := range rels {
+		t := triples[rel[2]]
+		g.Relationships = append(g.Relationships, geno.NewRelationship(int64(i), g.Nodes[rel[0]], g.Nodes[rel[1]], namer.name(t.predicate.value), map[string]any{}))
+	}
+	return g, nil
+}
+
+// addRDFProperty sets a property, collecting the values of a predicate repeated on the same subject into a list
+func addRDFProperty(props map[string]any, name string, value any) {
+	existing, found := props[name]
+	if !found {
+		props[name] = value
+		return
+	}
+	if list, isList := existing.([]any); isList {
+		props[name] = append(list, value)
+		return
+	}
+	props[name] = []any{existing, value}
+}
+
+func allLiterals(terms []rdfTerm) bool {
+	for _, t := range terms {
+		if t.kind != rdfLiteral {
+			return false
+		}
+	}
+	return len(terms) > 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
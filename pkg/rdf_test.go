@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+func TestGetGraphFromRDF(t *testing.T) {
+	const turtle = `@prefix sup: <http://example.com/supplier#> .
+@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+# suppliers and their contacts
+sup:S1 a sup:Supplier, foaf:Organization ;
+    foaf:name "Acme \"Steel\""@en ;
+    sup:rating 4.5 ;
+    sup:active true ;
+    sup:since "2021-03-04"^^xsd:date ;
+    sup:tag "steel", "bulk" ;
+    sup:contact [ foaf:name 'Jo' ] ;
+    sup:supplies <http://example.com/parts/P-1.> .
+<http://example.com/parts/P-1.> <http://example.com/other/weight> "12"^^<http://www.w3.org/2001/XMLSchema#integer> .
+`
+	got, err := GetGraphFromRDF(strings.NewReader(turtle), DefaultRDFMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		supplier geno.Node = geno.NewNode(0, []string{"Resource", "sup__Supplier", "foaf__Organization"}, map[string]any{
+			"uri":         "http://example.com/supplier#S1",
+			"foaf__name":  "Acme \"Steel\"",
+			"sup__rating": 4.5,
+			"sup__active": true,
+			"sup__since":  dbtype.Date(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)),
+			"sup__tag":    []any{"steel", "bulk"},
+		})
+		scope   [32]byte  = sha256.Sum256([]byte(turtle))
+		prefix  string    = fmt.Sprintf("ns%x", sha256.Sum256([]byte("http://example.com/other/")))[:10]
+		contact geno.Node = geno.NewNode(1, []string{"Resource"}, map[string]any{"uri": "_:" + hex.EncodeToString(scope[:8]) + "-genid1", "foaf__name": "Jo"})
+		part    geno.Node = geno.NewNode(2, []string{"Resource"}, map[string]any{"uri": "http://example.com/parts/P-1.", prefix + "__weight": int64(12)})
+	)
+	wantNodes := []geno.Node{supplier, contact, part}
+	wantRels := []geno.Relationship{
+		geno.NewRelationship(0, supplier, contact, "sup__contact", map[string]any{}),
+		geno.NewRelationship(1, supplier, part, "sup__supplies", map[string]any{}),
+	}
+	if !reflect.DeepEqual(wantNodes, got.Nodes) {
+		t.Errorf("nodes:\nwant %v\ngot  %v", wantNodes, got.Nodes)
+	}
+	if !reflect.DeepEqual(wantRels, got.Relationships) {
+		t.Errorf("relationships:\nwant %v\ngot  %v", wantRels, got.Relationships)
+	}
+}
+
+func TestGetGraphFromRDFNTriples(t *testing.T) {
+	const ntriples = `<http://example.com/s/1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://example.com/v#Supplier> .
+<http://example.com/s/1> <http://example.com/v#name> "Acmeé" .
+<http://example.com/s/1> <http://example.com/v#parent> <http://example.com/s/2> .
+`
+	m, err := ReadRDFMapping([]byte("KeyProperty: iri\nNames: local\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetGraphFromRDF(strings.NewReader(ntriples), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		first  geno.Node = geno.NewNode(0, []string{"Resource", "Supplier"}, map[string]any{"iri": "http://example.com/s/1", "name": "Acmeé"})
+		second geno.Node = geno.NewNode(1, []string{"Resource"}, map[string]any{"iri": "http://example.com/s/2"})
+	)
+	if want := []geno.Node{first, second}; !reflect.DeepEqual(want, got.Nodes) {
+		t.Errorf("nodes:\nwant %v\ngot  %v", want, got.Nodes)
+	}
+	if want := []geno.Relationship{geno.NewRelationship(0, first, second, "parent", map[string]any{})}; !reflect.DeepEqual(want, got.Relationships) {
+		t.Errorf("relationships:\nwant %v\ngot  %v", want, got.Relationships)
+	}
+	c := geno.Constraints{NodeUniqueness: []geno.Constraint{m.Key()}}
+	if keys := c.GetNodeConstraints(&got.Nodes[0]); !reflect.DeepEqual(keys, []string{"iri"}) {
+		t.Errorf("wanted resources merged on their iri, but got %v", keys)
+	}
+
+	if _, err := GetGraphFromRDF(strings.NewReader(`ex:a ex:b ex:c .`), m); err == nil {
+		t.Error("an undeclared prefix should be an error")
+	}
+}
+
+func TestGetGraphFromRDFDocuments(t *testing.T) {
+	var (
+		first  = []byte("_:b0 <http://example.com/v#name> \"Jo\" .\n")
+		second = []byte("_:b0 <http://example.com/v#name> \"Ann\" .\n")
+	)
+	got, err := GetGraphFromRDFDocuments([][]byte{first, second}, DefaultRDFMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].Properties["uri"] == got.Nodes[1].Properties["uri"] {
+		t.Errorf("wanted blank nodes of different documents kept apart, but got %v", got.Nodes)
+	}
+
+	again, err := GetGraphFromRDF(strings.NewReader(string(first)), DefaultRDFMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Nodes[0].Properties["uri"] != got.Nodes[0].Properties["uri"] {
+		t.Errorf("wanted a blank node keyed the same when its document is read again, but got %v and %v", again.Nodes[0].Properties, got.Nodes[0].Properties)
+	}
+	if !reflect.DeepEqual(again.Nodes[0].Properties, got.Nodes[0].Properties) {
+		t.Errorf("wanted an undeclared namespace given the same prefix in every read, but got %v and %v", again.Nodes[0].Properties, got.Nodes[0].Properties)
+	}
+}
@@ -0,0 +1,187 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+// StreamOptions describe a paged export of the nodes of a label, or of the relationships of a type
+type StreamOptions struct {
+	Label     string     // label of the nodes exported, or
+	Type      string     // type of the relationships exported
+	OrderBy   string     // string or number property nodes are read in order of, or their internal id when empty
+	PageSize  int        // elements read by each query
+	Limit     int        // elements exported at most, or all of them when 0
+	Timestamp string     // property holding when an element was last written, required by Since
+	Since     *time.Time // when given, only elements whose timestamp is at or after it are exported
+}
+
+// StreamCheckpoint records how far a stream has been written, so that an interrupted stream can be resumed
+type StreamCheckpoint struct {
+	Label    string     `json:"label,omitempty"`
+	Type     string     `json:"type,omitempty"`
+	OrderBy  string     `json:"orderBy,omitempty"`
+	Since    *time.Time `json:"since,omitempty"`
+	After    any        `json:"after"`    // cursor of the last element written, nil before the first
+	Written  int        `json:"written"`  // elements written
+	Offset   int64      `json:"offset"`   // bytes of output written
+	Complete bool       `json:"complete"` // whether every element has been written
+}
+
+// NewStreamCheckpoint returns the checkpoint of a stream which has not started
+func NewStreamCheckpoint(opts StreamOptions) StreamCheckpoint {
+	return StreamCheckpoint{Label: opts.Label, Type: opts.Type, OrderBy: opts.OrderBy, Since: opts.Since}
+}
+
+// Resumes reports whether the checkpoint was written by a stream with the same options
+func (c StreamCheckpoint) Resumes(opts StreamOptions) bool {
+	sameSince := (c.Since == nil && opts.Since == nil) || (c.Since != nil && opts.Since != nil && c.Since.Equal(*opts.Since))
+	return c.Label == opts.Label && c.Type == opts.Type && c.OrderBy == opts.OrderBy && sameSince
+}
+
+// ReadStreamCheckpoint reads a checkpoint written by WriteStreamCheckpoint
+func ReadStreamCheckpoint(path string) (c StreamCheckpoint, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return c, err
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if n, isNumber := c.After.(json.Number); isNumber {
+		// cursors are compared to the properties of the database, so integers must remain integers
+		if i, err := n.Int64(); err == nil {
+			c.After = i
+		} else if f, err := n.Float64(); err == nil {
+			c.After = f
+		}
+	}
+	return c, nil
+}
+
+// WriteStreamCheckpoint writes a checkpoint to path, replacing the previous one only once it is complete
+func WriteStreamCheckpoint(path string, c StreamCheckpoint) error {
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// StreamGraph writes the nodes of a label, or the relationships of a type, to w as apoc json lines, reading
+// PageSize elements at a time from the position recorded by the checkpoint given, so that memory use does not grow
+// with the number of elements. Once each page is written and flushed, saved is called with the checkpoint following
+// it. Relationships are written without their end nodes.
+func StreamGraph(driver *geno.Driver, database string, opts StreamOptions, from StreamCheckpoint, w io.Writer, saved func(StreamCheckpoint) error) (StreamCheckpoint, error) {
+	var c StreamCheckpoint = from
+	if (opts.Label == "") == (opts.Type == "") {
+		return c, errors.New("either a label or a relationship type must be streamed")
+	}
+	if opts.Type != "" && opts.OrderBy != "" {
+		return c, errors.New("relationships can only be streamed in order of their internal ids")
+	}
+	if opts.PageSize < 1 {
+		return c, errors.New("the page size must be at least 1")
+	}
+	if opts.Since != nil && opts.Timestamp == "" {
+		return c, errors.New("no timestamp property is configured to select elements written since a time")
+	}
+	if !c.Resumes(opts) {
+		return c, errors.New("the checkpoint was written by a stream of other elements")
+	}
+	var since any
+	if opts.Since != nil {
+		since = *opts.Since
+	}
+
+	var (
+		counter *countingWriter = &countingWriter{w: w, n: c.Offset}
+		out     *bufio.Writer   = bufio.NewWriter(counter)
+	)
+	for !c.Complete {
+		limit := opts.PageSize
+		if opts.Limit > 0 && opts.Limit-c.Written < limit {
+			limit = opts.Limit - c.Written
+		}
+		if limit <= 0 {
+			break
+		}
+
+		var (
+			g     Graph
+			read  int
+			after any
+		)
+		if opts.Label != "" {
+			records, err := driver.PageNodes(database, opts.Label, opts.OrderBy, c.After, limit, opts.Timestamp, since)
+			if err != nil {
+				return c, err
+			}
+			g = GraphFromRecords(records)
+			g.Relationships = nil
+			read = len(g.Nodes)
+			if read > 0 {
+				last := g.Nodes[read-1]
+				after = last.Id
+				if opts.OrderBy != "" {
+					after = last.Properties[opts.OrderBy]
+				}
+			}
+		} else {
+			records, err := driver.PageRelationships(database, opts.Type, c.After, limit, opts.Timestamp, since)
+			if err != nil {
+				return c, err
+			}
+			g = GraphFromRecords(records)
+			g.Nodes = nil
+			read = len(g.Relationships)
+			if read > 0 {
+				after = g.Relationships[read-1].Id
+			}
+		}
+
+		if err := WriteApocJson(out, g); err != nil {
+			return c, err
+		}
+		if err := out.Flush(); err != nil {
+			return c, err
+		}
+		if read > 0 {
+			c.After = after
+		}
+		c.Written += read
+		c.Offset = counter.n
+		c.Complete = read < limit || (opts.Limit > 0 && c.Written >= opts.Limit)
+		if saved != nil {
+			if err := saved(c); err != nil {
+				return c, err
+			}
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStreamCheckpoint(t *testing.T) {
+	since := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	opts := StreamOptions{Label: "Customer", OrderBy: "KUNNR", Since: &since}
+	path := filepath.Join(t.TempDir(), "customers.checkpoint")
+
+	type test struct {
+		name  string
+		after any
+	}
+	for _, tc := range []test{{"string key", "0000001"}, {"internal id", int64(9007199254740993)}, {"float key", 1.5}} {
+		c := NewStreamCheckpoint(opts)
+		c.After, c.Written, c.Offset = tc.after, 10, 1024
+		if err := WriteStreamCheckpoint(path, c); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadStreamCheckpoint(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.After != tc.after || read.Written != 10 || read.Offset != 1024 {
+			t.Errorf("%s: wanted the cursor %v (%T) read back, but got %v (%T)", tc.name, tc.after, tc.after, read.After, read.After)
+		}
+		if !read.Resumes(opts) {
+			t.Errorf("%s: wanted the checkpoint to resume the stream which wrote it", tc.name)
+		}
+	}
+
+	later := since.Add(time.Hour)
+	for _, other := range []StreamOptions{
+		{Label: "Customer", OrderBy: "KUNNR"},
+		{Label: "Customer", Since: &since},
+		{Label: "Customer", OrderBy: "KUNNR", Since: &later},
+		{Type: "HAS_PHONE", Since: &since},
+	} {
+		if NewStreamCheckpoint(opts).Resumes(other) {
+			t.Errorf("wanted a checkpoint not to resume a stream of %+v", other)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"gopkg.in/yaml.v3"
+)
+
+// TableMapping describes how each row of a table becomes nodes and the relationships between them
+type TableMapping struct {
+	Delimiter     string                `yaml:"Delimiter"` // a single character, defaults to a comma
+	Nodes         []NodeMapping         `yaml:"Nodes"`
+	Relationships []RelationshipMapping `yaml:"Relationships"`
+}
+
+// NodeMapping builds one node per row. Properties map property names to value templates: a column name,
+// or columns and quoted literals joined by +, e.g. Database + '-' + KUNNR. Properties are strings unless
+// given another type (string, integer, float, boolean or date). Empty values are left out, and a row with
+// no values in any column of the node does not produce the node, e.g. customers without a phone number.
+type NodeMapping struct {
+	Name       string            `yaml:"Name"` // referred to by relationships, defaults to the first label
+	Labels     []string          `yaml:"Labels"`
+	Properties map[string]string `yaml:"Properties"`
+	Types      map[string]string `yaml:"Types"`
+}
+
+// RelationshipMapping connects two nodes built from the same row
+type RelationshipMapping struct {
+	Type       string            `yaml:"Type"`
+	Start      string            `yaml:"Start"` // name of the node mapping at the start of the relationship
+	End        string            `yaml:"End"`   // name of the node mapping at the end of the relationship
+	Properties map[string]string `yaml:"Properties"`
+	Types      map[string]string `yaml:"Types"`
+}
+
+// valueTerm is a single column or literal of a value template
+type valueTerm struct {
+	literal bool
+	text    string
+}
+
+// parseValueTemplate splits a template such as Database + '-' + KUNNR into its columns and literals
+func parseValueTemplate(template string) ([]valueTerm, error) {
+	var (
+		terms   []valueTerm
+		current strings.Builder
+		quote   rune
+		literal bool
+	)
+	finish := func() error {
+		text := current.String()
+		if !literal {
+			text = strings.TrimSpace(text)
+		}
+		if text == "" && !literal {
+			return fmt.Errorf("value template %q has an empty term", template)
+		}
+		terms = append(terms, valueTerm{literal: literal, text: text})
+		current.Reset()
+		literal = false
+		return nil
+	}
+	for _, c := range template {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(c)
+		case c == '\'' || c == '"':
+			if strings.TrimSpace(current.String()) != "" {
+				return nil, fmt.Errorf("value template %q mixes a column and a literal without a +", template)
+			}
+			current.Reset()
+			quote, literal = c, true
+		case c == '+':
+			if err := finish(); err != nil {
+				return nil, err
+			}
+		case literal:
+			if c != ' ' && c != '\t' {
+				return nil, fmt.Errorf("value template %q mixes a column and a literal without a +", template)
+			}
+		default:
+			current.WriteRune(c)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("value template %q has an unclosed quote", template)
+	}
+	return terms, finish()
+}
+
+// tableProperties is a parsed set of property templates
+type tableProperties struct {
+	names     []string
+	templates map[string][]valueTerm
+	types     map[string]string
+}
+
+func parseTableProperties(props, types map[string]string, columns map[string]int) (tableProperties, error) {
+	var p tableProperties = tableProperties{templates: make(map[string][]valueTerm, len(props)), types: types}
+	for name, template := range props {
+		terms, err := parseValueTemplate(template)
+		if err != nil {
+			return p, err
+		}
+		for _, term := range terms {
+			if _, found := columns[term.text]; !term.literal && !found {
+				return p, fmt.Errorf("property %s refers to column %s which is not in the table", name, term.text)
+			}
+		}
+		p.names = append(p.names, name)
+		p.templates[name] = terms
+	}
+	for name, t := range types {
+		if _, found := props[name]; !found {
+			return p, fmt.Errorf("a type is given for property %s which is not mapped", name)
+		}
+		switch strings.ToLower(t) {
+		case "", "string", "integer", "int", "float", "boolean", "bool", "date":
+		default:
+			return p, fmt.Errorf("type %s of property %s is not one of string, integer, float, boolean or date", t, name)
+		}
+	}
+	sort.Strings(p.names)
+	return p, nil
+}
+
+// values fills in every template from a row. A property is left out when every column of its template is empty.
+// filled reports whether any property read from a column has a value, or whether there are no such properties.
+// Cells which are already typed, such as the numbers and dates of a workbook, keep their type when a property is
+// a single column without a configured type.
+func (p tableProperties) values(row []any, columns map[string]int) (values map[string]any, filled bool, err error) {
+	var fromColumns bool
+	values = make(map[string]any, len(p.names))
+	for _, name := range p.names {
+		var (
+			value      strings.Builder
+			hasValue   bool
+			hasColumns bool
+			typed      any
+		)
+		for _, term := range p.templates[name] {
+			if term.literal {
+				value.WriteString(term.text)
+				continue
+			}
+			hasColumns = true
+			cell := row[columns[term.text]]
+			if _, isString := cell.(string); !isString && cell != nil {
+				typed = cell
+			}
+			text := strings.TrimSpace(cellText(cell))
+			if text != "" {
+				hasValue = true
+			}
+			value.WriteString(text)
+		}
+		if !hasColumns {
+			values[name] = value.String() // constants, such as the system a table was extracted from
+			continue
+		}
+		fromColumns = true
+		if !hasValue {
+			continue
+		}
+		if typed != nil && len(p.templates[name]) == 1 && p.types[name] == "" {
+			values[name] = typed
+		} else if values[name], err = typeTableValue(value.String(), p.types[name]); err != nil {
+			return nil, false, fmt.Errorf("property %s: %w", name, err)
+		}
+		filled = true
+	}
+	return values, filled || !fromColumns, nil
+}
+
+// cellText writes a cell as text, so typed cells can be joined with others or given another type
+func cellText(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(exportValue(v))
+	}
+}
+
+// tableDateLayouts are the date formats accepted for date properties, including SAP's YYYYMMDD
+var tableDateLayouts []string = []string{"2006-01-02", "20060102", "02.01.2006"}
+
+func typeTableValue(raw, t string) (any, error) {
+	switch strings.ToLower(t) {
+	case "", "string":
+		return raw, nil
+	case "integer", "int":
+		return strconv.ParseInt(raw, 10, 64)
+	case "float":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean", "bool":
+		switch strings.ToLower(raw) {
+		case "true", "x", "1", "yes": // SAP flags are X when set
+			return true, nil
+		case "false", "0", "no":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q is not a boolean", raw)
+	case "date":
+		for _, layout := range tableDateLayouts {
+			if d, err := time.Parse(layout, raw); err == nil {
+				return dbtype.Date(d), nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a date in any of the formats %s", raw, strings.Join(tableDateLayouts, ", "))
+	default:
+		return nil, fmt.Errorf("type %s is not one of string, integer, float, boolean or date", t)
+	}
+}
+
+// ReadTableMapping reads a mapping file written in yaml
+func ReadTableMapping(raw []byte) (m TableMapping, err error) {
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return m, err
+	}
+	return m, m.validate()
+}
+
+// validate checks that every node has labels and properties, and names each node which has no name
+func (m *TableMapping) validate() error {
+	if len(m.Nodes) == 0 {
+		return errors.New("a table mapping must map at least one node")
+	}
+	var names map[string]bool = make(map[string]bool)
+	for i := range m.Nodes {
+		n := &m.Nodes[i]
+		if len(n.Labels) == 0 {
+			return fmt.Errorf("node mapping %d has no labels", i+1)
+		}
+		if len(n.Properties) == 0 {
+			return fmt.Errorf("node mapping %s has no properties", n.Labels[0])
+		}
+		if n.Name == "" {
+			n.Name = n.Labels[0]
+		}
+		if names[n.Name] {
+			return fmt.Errorf("node mapping %s is named more than once; give each a distinct Name", n.Name)
+		}
+		names[n.Name] = true
+	}
+	for _, r := range m.Relationships {
+		if r.Type == "" {
+			return errors.New("every relationship mapping must have a type")
+		}
+		if !names[r.Start] || !names[r.End] {
+			return fmt.Errorf("relationship %s must start and end at mapped nodes, not %q and %q", r.Type, r.Start, r.End)
+		}
+	}
+	if len([]rune(m.Delimiter)) > 1 {
+		return fmt.Errorf("delimiter %q must be a single character", m.Delimiter)
+	}
+	return nil
+}
+
+// tableGraph collects the nodes and relationships built from the rows of one or more tables.
+// Nodes built from different rows with identical labels and properties are only included once.
+type tableGraph struct {
+	Graph
+	seen map[string]int // index within Nodes of each node, by its labels and properties
+}
+
+func newTableGraph() *tableGraph {
+	return &tableGraph{seen: make(map[string]int)}
+}
+
+// addTable builds nodes and relationships from every row returned by next until it returns io.EOF.
+// Rows are numbered from firstLine in errors.
+func (t *tableGraph) addTable(m TableMapping, header []string, firstLine int, next func() ([]any, error)) (err error) {
+	var columns map[string]int = make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+
+	var (
+		nodeProps []tableProperties = make([]tableProperties, len(m.Nodes))
+		relProps  []tableProperties = make([]tableProperties, len(m.Relationships))
+	)
+	for i, n := range m.Nodes {
+		if nodeProps[i], err = parseTableProperties(n.Properties, n.Types, columns); err != nil {
+			return fmt.Errorf("node %s: %w", n.Name, err)
+		}
+	}
+	for i, rel := range m.Relationships {
+		if relProps[i], err = parseTableProperties(rel.Properties, rel.Types, columns); err != nil {
+			return fmt.Errorf("relationship %s: %w", rel.Type, err)
+		}
+	}
+
+	for line := firstLine; ; line++ {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(row) < len(header) {
+			row = append(row, make([]any, len(header)-len(row))...)
+		}
+
+		var built map[string]int = make(map[string]int, len(m.Nodes)) // index within Nodes of each node of the row
+		for i, n := range m.Nodes {
+			props, filled, err := nodeProps[i].values(row, columns)
+			if err != nil {
+				return fmt.Errorf("row %d, node %s: %w", line, n.Name, err)
+			}
+			if !filled {
+				continue
+			}
+			identity := strings.Join(n.Labels, ":") + geno.CypherLiteral(props)
+			index, found := t.seen[identity]
+			if !found {
+				index = len(t.Nodes)
+				t.seen[identity] = index
+				t.Nodes = append(t.Nodes, geno.NewNode(int64(index), n.Labels, props))
+			}
+			built[n.Name] = index
+		}
+		for i, rel := range m.Relationships {
+			start, hasStart := built[rel.Start]
+			end, hasEnd := built[rel.End]
+			if !hasStart || !hasEnd {
+				continue
+			}
+			props, _, err := relProps[i].values(row, columns)
+			if err != nil {
+				return fmt.Errorf("row %d, relationship %s: %w", line, rel.Type, err)
+			}
+			t.Relationships = append(t.Relationships, geno.NewRelationship(int64(len(t.Relationships)), t.Nodes[start], t.Nodes[end], rel.Type, props))
+		}
+	}
+}
+
+// GetGraphFromTable builds nodes and relationships from every row of a delimited table with a header row.
+// Nodes built from different rows with identical labels and properties are only included once,
+// so a value shared by many rows, such as a phone number, becomes a single node.
+func GetGraphFromTable(r io.Reader, m TableMapping) (g Graph, err error) {
+	reader := csv.NewReader(r)
+	if m.Delimiter != "" {
+		reader.Comma = []rune(m.Delimiter)[0]
+	}
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return g, fmt.Errorf("the table's header row could not be read: %w", err)
+	}
+	t := newTableGraph()
+	err = t.addTable(m, header, 2, func() ([]any, error) {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		var row []any = make([]any, len(record))
+		for i, cell := range record {
+			row[i] = cell
+		}
+		return row, nil
+	})
+	return t.Graph, err
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+)
+
+func TestGetGraphFromTable(t *testing.T) {
+	const mapping = `
+Delimiter: ";"
+Nodes:
+    - Labels: [Customer]
+      Properties:
+          Key: Database + '-' + KUNNR
+          KUNNR: KUNNR
+          NAME1: NAME1
+          ERDAT: ERDAT
+          Source: "'SAP'"
+      Types:
+          ERDAT: date
+    - Labels: [Phone]
+      Properties:
+          Value: TELF1
+Relationships:
+    - Type: HAS_PHONE
+      Start: Customer
+      End: Phone
+`
+	const table = `Database;KUNNR;NAME1;ERDAT;TELF1
+PRD;0000001;Acme;20220304;555-0100
+PRD;0000002;Widgets;20220305;555-0100
+PRD;0000003;Gadgets;20220306;
+`
+	m, err := ReadTableMapping([]byte(mapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetGraphFromTable(strings.NewReader(table), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := func(day int) dbtype.Date { return dbtype.Date(time.Date(2022, 3, day, 0, 0, 0, 0, time.UTC)) }
+	var (
+		acme    geno.Node = geno.NewNode(0, []string{"Customer"}, map[string]any{"Key": "PRD-0000001", "KUNNR": "0000001", "NAME1": "Acme", "ERDAT": date(4), "Source": "SAP"})
+		phone   geno.Node = geno.NewNode(1, []string{"Phone"}, map[string]any{"Value": "555-0100"})
+		widgets geno.Node = geno.NewNode(2, []string{"Customer"}, map[string]any{"Key": "PRD-0000002", "KUNNR": "0000002", "NAME1": "Widgets", "ERDAT": date(5), "Source": "SAP"})
+		gadgets geno.Node = geno.NewNode(3, []string{"Customer"}, map[string]any{"Key": "PRD-0000003", "KUNNR": "0000003", "NAME1": "Gadgets", "ERDAT": date(6), "Source": "SAP"})
+	)
+	wantNodes := []geno.Node{acme, phone, widgets, gadgets}
+	wantRels := []geno.Relationship{
+		geno.NewRelationship(0, acme, phone, "HAS_PHONE", map[string]any{}),
+		geno.NewRelationship(1, widgets, phone, "HAS_PHONE", map[string]any{}),
+	}
+	if !reflect.DeepEqual(wantNodes, got.Nodes) {
+		t.Errorf("wanted nodes of\n%v\nbut got\n%v\n", wantNodes, got.Nodes)
+	}
+	if !reflect.DeepEqual(wantRels, got.Relationships) {
+		t.Errorf("wanted rels of\n%v\nbut got\n%v\n", wantRels, got.Relationships)
+	}
+}
+
+func TestReadTableMappingErrors(t *testing.T) {
+	tests := map[string]string{
+		"no nodes":          `Relationships: []`,
+		"no labels":         `Nodes: [{Properties: {A: A}}]`,
+		"unknown endpoint":  `{Nodes: [{Labels: [A], Properties: {A: A}}], Relationships: [{Type: R, Start: A, End: B}]}`,
+		"duplicate mapping": `Nodes: [{Labels: [A], Properties: {A: A}}, {Labels: [A], Properties: {B: B}}]`,
+	}
+	for name, mapping := range tests {
+		if _, err := ReadTableMapping([]byte(mapping)); err == nil {
+			t.Errorf("%s: wanted an error", name)
+		}
+	}
+
+	m, _ := ReadTableMapping([]byte(`Nodes: [{Labels: [A], Properties: {A: MISSING}}]`))
+	if _, err := GetGraphFromTable(strings.NewReader("B\n1\n"), m); err == nil {
+		t.Error("a property of a column which is not in the table should be an error")
+	}
+}
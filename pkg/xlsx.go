@@ -0,0 +1,237 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkbookMapping describes how the sheets of a workbook become nodes and relationships
+type WorkbookMapping struct {
+	Sheets []SheetMapping `yaml:"Sheets"`
+}
+
+// SheetMapping maps the rows of one sheet as a TableMapping maps the rows of a delimited table.
+// A sheet without node mappings becomes one node per row, labelled with the name of the sheet
+// and holding every column as a property.
+type SheetMapping struct {
+	Sheet        string `yaml:"Sheet"`
+	HeaderRow    int    `yaml:"HeaderRow"` // row holding the column names, defaults to 1; rows above it are ignored
+	TableMapping `yaml:",inline"`
+}
+
+// ReadWorkbookMapping reads a mapping file written in yaml
+func ReadWorkbookMapping(raw []byte) (m WorkbookMapping, err error) {
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return m, err
+	}
+	if len(m.Sheets) == 0 {
+		return m, errors.New("a workbook mapping must map at least one sheet")
+	}
+	for i := range m.Sheets {
+		s := &m.Sheets[i]
+		if s.Sheet == "" {
+			return m, fmt.Errorf("sheet mapping %d does not name its sheet", i+1)
+		}
+		if s.HeaderRow < 0 {
+			return m, fmt.Errorf("sheet %s: the header row must be at least 1", s.Sheet)
+		}
+		if len(s.Nodes) == 0 {
+			continue // mapped from the header once the sheet is read
+		}
+		if err := s.validate(); err != nil {
+			return m, fmt.Errorf("sheet %s: %w", s.Sheet, err)
+		}
+	}
+	return m, nil
+}
+
+// sheetTableMapping maps every column of a sheet to a property of the same name, on nodes labelled with the sheet's name
+func sheetTableMapping(sheet string, header []string) TableMapping {
+	var n NodeMapping = NodeMapping{Labels: []string{plainName(sheet)}, Properties: make(map[string]string, len(header))}
+	for _, h := range header {
+		if name := plainName(h); name != "" {
+			n.Properties[name] = strings.TrimSpace(h)
+		}
+	}
+	return TableMapping{Nodes: []NodeMapping{n}}
+}
+
+// excelDateFormats are the built in number formats showing a date or time
+var excelDateFormats map[int]bool = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+	27: true, 28: true, 29: true, 30: true, 31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	45: true, 46: true, 47: true, 50: true, 51: true, 52: true, 53: true, 54: true, 55: true, 56: true, 57: true, 58: true,
+}
+
+// isDateFormat reports whether a custom number format shows a date or time, ignoring quoted text and bracketed colours
+func isDateFormat(code string) bool {
+	var quoted, bracketed bool
+	for _, c := range strings.ToLower(code) {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			bracketed = true
+		case c == ']':
+			bracketed = false
+		case bracketed:
+		case c == 'y' || c == 'd' || c == 'h' || c == 's':
+			return true
+		}
+	}
+	return false
+}
+
+// workbookReader types the cells of a workbook by their cell type and number format
+type workbookReader struct {
+	file     *excelize.File
+	date1904 bool
+}
+
+func (w workbookReader) isDate(sheet, axis string) bool {
+	style, err := w.file.GetCellStyle(sheet, axis)
+	if err != nil || w.file.Styles == nil || w.file.Styles.CellXfs == nil || style >= len(w.file.Styles.CellXfs.Xf) {
+		return false
+	}
+	numFmt := w.file.Styles.CellXfs.Xf[style].NumFmtID
+	if numFmt == nil {
+		return false
+	}
+	if excelDateFormats[*numFmt] {
+		return true
+	}
+	if w.file.Styles.NumFmts != nil {
+		for _, f := range w.file.Styles.NumFmts.NumFmt {
+			if f != nil && f.NumFmtID == *numFmt {
+				return isDateFormat(f.FormatCode)
+			}
+		}
+	}
+	return false
+}
+
+// cell types a raw cell value: booleans, whole numbers as integers, other numbers as floats, numbers formatted
+// as dates as dates (or local date times and times when they have a time of day), and everything else as text
+func (w workbookReader) cell(sheet string, col, row int, raw string) (any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	axis, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return nil, err
+	}
+	cellType, err := w.file.GetCellType(sheet, axis)
+	if err != nil {
+		return nil, err
+	}
+	switch cellType {
+	case excelize.CellTypeBool:
+		return raw == "1" || strings.EqualFold(raw, "true"), nil
+	case excelize.CellTypeUnset, excelize.CellTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return raw, nil
+		}
+		if w.isDate(sheet, axis) {
+			return excelDate(number, w.date1904)
+		}
+		if number == math.Trunc(number) && math.Abs(number) < 1<<53 {
+			return int64(number), nil
+		}
+		return number, nil
+	default:
+		return raw, nil
+	}
+}
+
+func excelDate(serial float64, date1904 bool) (any, error) {
+	t, err := excelize.ExcelDateToTime(serial, date1904)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case serial < 1:
+		return dbtype.LocalTime(t), nil
+	case serial == math.Trunc(serial):
+		return dbtype.Date(t), nil
+	default:
+		return dbtype.LocalDateTime(t), nil
+	}
+}
+
+// GetGraphFromXlsx builds nodes and relationships from the rows of the mapped sheets of a workbook, as
+// GetGraphFromTable does for a delimited table. Cells keep their type: numbers, booleans and dates are imported
+// as such unless a property joins several columns or is given a type. With no sheets mapped, every sheet of
+// the workbook becomes nodes labelled with its name. Identical nodes of different rows and sheets are included once.
+func GetGraphFromXlsx(r io.Reader, m WorkbookMapping) (g Graph, err error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return g, err
+	}
+	defer f.Close()
+	w := workbookReader{file: f}
+	if props, err := f.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+		w.date1904 = *props.Date1904
+	}
+
+	sheets := m.Sheets
+	if len(sheets) == 0 {
+		for _, name := range f.GetSheetList() {
+			sheets = append(sheets, SheetMapping{Sheet: name})
+		}
+	}
+
+	t := newTableGraph()
+	for _, s := range sheets {
+		rows, err := f.GetRows(s.Sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return g, fmt.Errorf("sheet %s: %w", s.Sheet, err)
+		}
+		headerRow := s.HeaderRow
+		if headerRow == 0 {
+			headerRow = 1
+		}
+		if len(rows) < headerRow {
+			return g, fmt.Errorf("sheet %s has no header in row %d", s.Sheet, headerRow)
+		}
+		header := rows[headerRow-1]
+
+		mapping := s.TableMapping
+		if len(mapping.Nodes) == 0 {
+			mapping = sheetTableMapping(s.Sheet, header)
+			if err := mapping.validate(); err != nil {
+				return g, fmt.Errorf("sheet %s: %w", s.Sheet, err)
+			}
+		}
+
+		next := headerRow // index within rows of the next row, which is also the number of the row before it
+		err = t.addTable(mapping, header, headerRow+1, func() ([]any, error) {
+			if next >= len(rows) {
+				return nil, io.EOF
+			}
+			var row []any = make([]any, len(rows[next]))
+			for col, raw := range rows[next] {
+				cell, err := w.cell(s.Sheet, col+1, next+1, raw)
+				if err != nil {
+					return nil, err
+				}
+				row[col] = cell
+			}
+			next++
+			return row, nil
+		})
+		if err != nil {
+			return g, fmt.Errorf("sheet %s: %w", s.Sheet, err)
+		}
+	}
+	return t.Graph, nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Viking2012/geno/geno"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j/dbtype"
+	"github.com/xuri/excelize/v2"
+)
+
+// testWorkbook builds a workbook with a sheet of banks, whose header is in the second row, and a sheet of tax codes
+func testWorkbook(t *testing.T) *bytes.Buffer {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Banks")
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for axis, val := range map[string]any{
+		"A1": "Bank list, maintained by treasury",
+		"A2": "BANKL", "B2": "Bank Name", "C2": "Valid From", "D2": "Active",
+		"A3": "10020030", "B3": "First Bank", "C3": 44621, "D3": true,
+		"A4": "10020031", "B4": "Second Bank",
+	} {
+		f.SetCellValue("Banks", axis, val)
+	}
+	f.SetCellStyle("Banks", "C3", "C3", dateStyle)
+
+	f.NewSheet("Tax Codes")
+	for axis, val := range map[string]any{
+		"A1": "Code", "B1": "Rate",
+		"A2": "V1", "B2": 19,
+		"A3": "V2", "B3": 7.5,
+	} {
+		f.SetCellValue("Tax Codes", axis, val)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := f.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func TestGetGraphFromXlsx(t *testing.T) {
+	const mapping = `
+Sheets:
+    - Sheet: Banks
+      HeaderRow: 2
+      Nodes:
+          - Labels: [Bank]
+            Properties:
+                BANKL: BANKL
+                Name: Bank Name
+                ValidFrom: Valid From
+                Active: Active
+                Key: "'DE-' + BANKL"
+    - Sheet: Tax Codes
+`
+	m, err := ReadWorkbookMapping([]byte(mapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetGraphFromXlsx(testWorkbook(t), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []geno.Node{
+		geno.NewNode(0, []string{"Bank"}, map[string]any{"BANKL": "10020030", "Name": "First Bank", "ValidFrom": dbtype.Date(time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)), "Active": true, "Key": "DE-10020030"}),
+		geno.NewNode(1, []string{"Bank"}, map[string]any{"BANKL": "10020031", "Name": "Second Bank", "Key": "DE-10020031"}),
+		geno.NewNode(2, []string{"Tax_Codes"}, map[string]any{"Code": "V1", "Rate": int64(19)}),
+		geno.NewNode(3, []string{"Tax_Codes"}, map[string]any{"Code": "V2", "Rate": 7.5}),
+	}
+	if !reflect.DeepEqual(want, got.Nodes) {
+		t.Errorf("wanted nodes\n%v\nbut got\n%v", want, got.Nodes)
+	}
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetGraphFromYaml reads the same nodes and rels structure as GetGraphFromJson, written as yaml.
+// Anchors, aliases and merge keys are resolved first, so shared property blocks can be declared once:
+//
+//	defaults: &sap
+//	    Database: PRD
+//	nodes:
+//	    - identity: 1
+//	      labels: [Customer]
+//	      properties:
+//	          <<: *sap
+//	          KUNNR: "0000001"
+//
+// The document is then handed to GetGraphFromJson, so both formats type their values identically.
+func GetGraphFromYaml(raw []byte) (g Graph, err error) {
+	return GetGraphFromYamlDocuments([][]byte{raw})
+}
+
+// GetGraphFromYamlDocuments reads several yaml documents as one graph, as GetGraphFromJsonDocuments does
+func GetGraphFromYamlDocuments(docs [][]byte) (g Graph, err error) {
+	jsDocs, err := YamlAsJson(docs)
+	if err != nil {
+		return g, err
+	}
+	return GetGraphFromJsonDocuments(jsDocs)
+}
+
+// YamlAsJson rewrites yaml documents as json, resolving anchors, aliases and merge keys, so that they can be read
+// alongside json documents by GetGraphsFromJsonDocuments
+func YamlAsJson(docs [][]byte) ([][]byte, error) {
+	var jsDocs [][]byte = make([][]byte, len(docs))
+	for i, raw := range docs {
+		var doc any
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		js, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("yaml could not be read as a graph: %w", err)
+		}
+		jsDocs[i] = js
+	}
+	return jsDocs, nil
+}
+
+// WriteYaml writes a graph in the same format read by GetGraphFromYaml
+func WriteYaml(w io.Writer, g Graph) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(toReadGraph(g)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
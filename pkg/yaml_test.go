@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Viking2012/geno/geno"
+)
+
+func TestGetGraphFromYaml(t *testing.T) {
+	const doc = `
+shared: &shared
+    Prop2: Shared
+nodes:
+    - identity: 1
+      labels: [TypeA]
+      properties:
+          <<: *shared
+          Prop1: Value1A
+    - identity: 2
+      labels: [TypeB]
+      properties:
+          <<: *shared
+          Prop1: Value1B
+          Count: 3
+rels:
+    - identity: 4
+      start: 1
+      end: 2
+      type: RelTypeA
+      properties: {RelProp1: RelValue1A}
+`
+	var (
+		nodeA geno.Node         = geno.NewNode(1, []string{"TypeA"}, map[string]any{"Prop1": "Value1A", "Prop2": "Shared"})
+		nodeB geno.Node         = geno.NewNode(2, []string{"TypeB"}, map[string]any{"Prop1": "Value1B", "Prop2": "Shared", "Count": float64(3)})
+		relA  geno.Relationship = geno.NewRelationship(4, nodeA, nodeB, "RelTypeA", map[string]any{"RelProp1": "RelValue1A"})
+	)
+
+	got, err := GetGraphFromYaml([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]geno.Node{nodeA, nodeB}, got.Nodes) {
+		t.Errorf("wanted nodes of\n%v\nbut got\n%v\n", []geno.Node{nodeA, nodeB}, got.Nodes)
+	}
+	if !reflect.DeepEqual([]geno.Relationship{relA}, got.Relationships) {
+		t.Errorf("wanted rels of\n%v\nbut got\n%v\n", []geno.Relationship{relA}, got.Relationships)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteYaml(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	again, err := GetGraphFromYaml(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Errorf("wanted a round trip to give\n%v\nbut got\n%v\n", got, again)
+	}
+}